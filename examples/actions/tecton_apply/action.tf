@@ -0,0 +1,7 @@
+# Invoke with `terraform apply -invoke=action.tecton_apply.feature_repo`
+action "tecton_apply" "feature_repo" {
+  config {
+    repo_path = "${path.module}/feature_repo"
+    workspace = "prod"
+  }
+}
@@ -0,0 +1,12 @@
+# Invoke with `terraform apply -invoke=action.tecton_materialization_job.backfill`
+action "tecton_materialization_job" "backfill" {
+  config {
+    workspace    = "prod"
+    feature_view = "user_transaction_counts"
+    start_time   = "2024-01-01T00:00:00Z"
+    end_time     = "2024-02-01T00:00:00Z"
+    offline      = true
+    wait         = true
+    wait_timeout = "2h"
+  }
+}
@@ -0,0 +1,8 @@
+data "tecton_access_report" "quarterly_review" {
+  format = "markdown"
+}
+
+resource "local_file" "access_review" {
+  filename = "${path.module}/access-review.md"
+  content  = data.tecton_access_report.quarterly_review.report
+}
@@ -0,0 +1,14 @@
+data "tecton_assertion" "access_policy" {
+  assertion {
+    name            = "No user is an owner of a prod workspace"
+    workspaces      = ["prod-*"]
+    principal_type  = "user"
+    forbidden_roles = ["owner", "admin"]
+  }
+
+  assertion {
+    name          = "The CI service account is read-only"
+    principals    = ["ci-bot"]
+    allowed_roles = ["viewer"]
+  }
+}
@@ -0,0 +1,13 @@
+data "tecton_feature_service_schema" "fraud_detection" {
+  workspace = "prod"
+  name      = "fraud_detection"
+}
+
+output "fraud_detection_request_fields" {
+  value = {
+    for field in concat(
+      data.tecton_feature_service_schema.fraud_detection.join_keys,
+      data.tecton_feature_service_schema.fraud_detection.request_context,
+    ) : field.name => field.type
+  }
+}
@@ -0,0 +1,8 @@
+data "tecton_feature_view" "user_clicks" {
+  workspace = "prod"
+  name      = "user_clicks"
+}
+
+output "user_clicks_features" {
+  value = { for feature in data.tecton_feature_view.user_clicks.features : feature.name => feature.type }
+}
@@ -0,0 +1,11 @@
+data "tecton_feature_view_usage" "prod" {
+  workspace = "prod"
+  window    = "168h"
+}
+
+check "no_dominant_feature_view" {
+  assert {
+    condition     = alltrue([for fv in data.tecton_feature_view_usage.prod.feature_views : fv.compute_share < 0.5])
+    error_message = "A single feature view used more than half of the materialization compute in the last week."
+  }
+}
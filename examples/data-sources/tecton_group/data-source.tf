@@ -0,0 +1,7 @@
+data "tecton_group" "ml_engineers" {
+  name = "ml-engineers"
+}
+
+data "tecton_group_members" "ml_engineers" {
+  group_id = data.tecton_group.ml_engineers.id
+}
@@ -0,0 +1,12 @@
+data "tecton_group_members" "ml_engineers" {
+  group_id = "abc123"
+}
+
+resource "tecton_access_policy" "ml_engineers" {
+  for_each = toset(data.tecton_group_members.ml_engineers.users)
+
+  user_id = each.value
+  workspaces = {
+    "prod" = ["viewer"]
+  }
+}
@@ -0,0 +1,11 @@
+data "tecton_materialization_failures" "prod" {
+  workspace = "prod"
+  window    = "6h"
+}
+
+check "feature_pipelines_healthy" {
+  assert {
+    condition     = data.tecton_materialization_failures.prod.failed_job_count == 0
+    error_message = "Feature views with failed materialization jobs: ${join(", ", data.tecton_materialization_failures.prod.feature_views[*].name)}"
+  }
+}
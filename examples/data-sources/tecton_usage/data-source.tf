@@ -0,0 +1,11 @@
+data "tecton_usage" "last_week" {
+  window     = "168h"
+  workspaces = ["prod", "staging"]
+}
+
+check "materialization_budget" {
+  assert {
+    condition     = data.tecton_usage.last_week.materialization_compute_hours < 500
+    error_message = "Materialization used more than 500 compute hours in the last week."
+  }
+}
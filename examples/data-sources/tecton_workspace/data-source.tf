@@ -0,0 +1,15 @@
+data "tecton_workspace" "staging" {
+  name = "staging"
+}
+
+resource "tecton_workspace" "staging_v2" {
+  name = "staging-v2"
+  live = true
+
+  lifecycle {
+    precondition {
+      condition     = !data.tecton_workspace.staging.has_live_serving
+      error_message = "Move the feature services off the staging workspace before replacing it."
+    }
+  }
+}
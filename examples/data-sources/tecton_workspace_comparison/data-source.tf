@@ -0,0 +1,11 @@
+data "tecton_workspace_comparison" "staging_to_prod" {
+  source_workspace = "staging"
+  target_workspace = "prod"
+}
+
+check "prod_in_sync_with_staging" {
+  assert {
+    condition     = data.tecton_workspace_comparison.staging_to_prod.in_sync
+    error_message = "Feature views not yet promoted to prod: ${join(", ", data.tecton_workspace_comparison.staging_to_prod.only_in_source.feature_views)}"
+  }
+}
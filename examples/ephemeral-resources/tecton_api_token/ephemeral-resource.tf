@@ -0,0 +1,17 @@
+ephemeral "tecton_api_token" "feature_server" {
+  description = "Terraform feature server smoke test"
+}
+
+data "http" "feature_service_metadata" {
+  url    = "https://yourcluster.tecton.ai/api/v1/feature-service/metadata"
+  method = "POST"
+  request_headers = {
+    Authorization = ephemeral.tecton_api_token.feature_server.authorization_header
+  }
+  request_body = jsonencode({
+    params = {
+      workspace_name       = "prod"
+      feature_service_name = "fraud_detection"
+    }
+  })
+}
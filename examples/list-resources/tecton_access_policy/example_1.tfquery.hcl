@@ -0,0 +1,7 @@
+list "tecton_access_policy" "service_accounts" {
+  provider = tecton
+
+  config {
+    principal_type = "service_account"
+  }
+}
@@ -0,0 +1,8 @@
+list "tecton_workspace" "live" {
+  provider = tecton
+
+  config {
+    live       = true
+    name_regex = "^prod-"
+  }
+}
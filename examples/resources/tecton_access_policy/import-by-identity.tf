@@ -0,0 +1,6 @@
+import {
+  to = tecton_access_policy.example
+  identity = {
+    id = "user-abc"
+  }
+}
@@ -0,0 +1,6 @@
+resource "tecton_bulk_role_assignment" "on_call" {
+  workspace           = "prod"
+  roles               = ["viewer", "operator"]
+  user_ids            = ["alice@example.com", "bob@example.com"]
+  service_account_ids = ["abc"]
+}
@@ -0,0 +1,4 @@
+resource "tecton_cli_command" "service_account" {
+  create_args  = ["service-account", "create", "--name", "ci-pipeline"]
+  destroy_args = ["service-account", "delete", "--name", "ci-pipeline"]
+}
@@ -0,0 +1,12 @@
+resource "tecton_integration" "snowflake" {
+  name = "snowflake-prod"
+  type = "snowflake"
+  settings = {
+    url       = "https://myaccount.snowflakecomputing.com"
+    warehouse = "TECTON"
+    user      = "TECTON_SVC"
+  }
+  secret_settings = {
+    password = var.snowflake_password
+  }
+}
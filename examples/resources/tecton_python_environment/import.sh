@@ -0,0 +1,2 @@
+# Python environments can be imported by specifying the environment ID
+terraform import tecton_python_environment.example 0123456789abcdef0123456789abcdef
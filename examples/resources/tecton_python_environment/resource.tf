@@ -0,0 +1,6 @@
+resource "tecton_python_environment" "rift" {
+  name         = "rift-fraud-detection"
+  description  = "Environment for the fraud detection feature views"
+  requirements = file("${path.module}/requirements.txt")
+  wait_timeout = "30m"
+}
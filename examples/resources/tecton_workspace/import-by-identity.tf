@@ -0,0 +1,6 @@
+import {
+  to = tecton_workspace.example
+  identity = {
+    name = "test-workspace-name"
+  }
+}
@@ -0,0 +1,19 @@
+data "tecton_group_members" "team_a" {
+  group_id = "abc123"
+}
+
+resource "tecton_workspace_bootstrap" "team_a" {
+  name = "team-a-prod"
+  live = true
+
+  grant {
+    roles    = ["owner"]
+    user_ids = ["lead@example.com"]
+  }
+
+  grant {
+    roles               = ["editor"]
+    user_ids            = data.tecton_group_members.team_a.users
+    service_account_ids = ["abc"]
+  }
+}
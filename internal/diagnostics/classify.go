@@ -0,0 +1,51 @@
+package diagnostics
+
+import "regexp"
+
+// FailureClass is a common cause of failed commands, derived from their output. It's shared by the
+// client, which decides whether to retry a command by it, and the provider, which derives the error
+// code and advice of a diagnostic from it.
+type FailureClass string
+
+const (
+	// The output doesn't match any known class.
+	FailureUnknown FailureClass = ""
+	// Tecton rejected the credentials, e.g. an invalid or expired API key.
+	FailureAuthentication FailureClass = "authentication"
+	// The account isn't allowed to do what the command does.
+	FailurePermissionDenied FailureClass = "permission_denied"
+	// Tecton rejected the request because too many requests were made, which is worth retrying later.
+	FailureThrottled FailureClass = "throttled"
+	// The workspace the command refers to doesn't exist.
+	FailureWorkspaceNotFound FailureClass = "workspace_not_found"
+	// The user or service account the command refers to doesn't exist.
+	FailurePrincipalNotFound FailureClass = "principal_not_found"
+	// The object the command creates already exists.
+	FailureAlreadyExists FailureClass = "already_exists"
+)
+
+// Patterns in the output of failed commands, checked in order. The first match determines the class.
+// HTTP status codes must follow "status" or "code", so that e.g. a workspace named "team-401" doesn't
+// match.
+var failureClassPatterns = []struct {
+	Pattern *regexp.Regexp
+	Class   FailureClass
+}{
+	{regexp.MustCompile(`(?i)((status|code):? ?429|too many requests|rate[ _-]?limit|resource[ _]exhausted|throttl)`), FailureThrottled},
+	{regexp.MustCompile(`(?i)(unauthenticated|(status|code):? ?401|invalid api key|api key .*(invalid|expired)|not logged in)`), FailureAuthentication},
+	{regexp.MustCompile(`(?i)(permission[ _]denied|(status|code):? ?403|forbidden|not authorized|unauthorized)`), FailurePermissionDenied},
+	{regexp.MustCompile(`(?i)workspace .*(not found|does not exist|doesn't exist)`), FailureWorkspaceNotFound},
+	{regexp.MustCompile(`(?i)(user|service account|principal) .*(not found|does not exist|doesn't exist)`), FailurePrincipalNotFound},
+	{regexp.MustCompile(`(?i)already exists`), FailureAlreadyExists},
+}
+
+// Returns the class of a failed command's output, or of an error that includes it, e.g. a
+// *CommandError's message.
+func ClassifyOutput(output string) FailureClass {
+	for _, p := range failureClassPatterns {
+		if p.Pattern.MatchString(output) {
+			return p.Class
+		}
+	}
+	return FailureUnknown
+}
@@ -0,0 +1,30 @@
+package diagnostics
+
+import "testing"
+
+func TestClassifyOutput(t *testing.T) {
+	testCases := map[string]struct {
+		output   string
+		expected FailureClass
+	}{
+		"unauthenticated":     {output: "UNAUTHENTICATED: invalid api key", expected: FailureAuthentication},
+		"expired key":         {output: "Error: API key abc123 has expired", expected: FailureAuthentication},
+		"forbidden":           {output: "Error: status code 403: Forbidden", expected: FailurePermissionDenied},
+		"permission denied":   {output: "PERMISSION_DENIED: caller is not an admin", expected: FailurePermissionDenied},
+		"too many requests":   {output: "Error: status code 429: Too Many Requests", expected: FailureThrottled},
+		"rate limit":          {output: "Rate limit exceeded, try again later", expected: FailureThrottled},
+		"workspace not found": {output: "Workspace 'prod' not found", expected: FailureWorkspaceNotFound},
+		"user not found":      {output: "Error: user alice@example.com does not exist", expected: FailurePrincipalNotFound},
+		"already exists":      {output: "Workspace prod already exists", expected: FailureAlreadyExists},
+		"workspace number":    {output: "Applied to workspace team-401", expected: FailureUnknown},
+		"unknown":             {output: "Segmentation fault", expected: FailureUnknown},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := ClassifyOutput(testCase.output)
+			if actual != testCase.expected {
+				t.Errorf("expected '%v', got '%v'", testCase.expected, actual)
+			}
+		})
+	}
+}
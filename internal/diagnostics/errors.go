@@ -0,0 +1,141 @@
+// Package diagnostics defines the errors returned by the provider's commands and scripts. They
+// wrap the underlying error, so callers can inspect it with errors.Is and errors.As, and attach the
+// context needed to debug a failure, such as the command line and its output. Their messages have a
+// standard format that is used as the detail of the provider's diagnostics. The package also
+// classifies the output of failed commands, so that retries and diagnostics agree on what failed.
+package diagnostics
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrTimedOut is wrapped by the errors of commands that were killed because they ran longer than
+// the command timeout, or than the timeout of the resource operation that ran them.
+var ErrTimedOut = errors.New("Command timed out")
+
+// ErrWaitingForInput is wrapped by the errors of commands that failed because they prompted for
+// input, which the provider can't give.
+var ErrWaitingForInput = errors.New("Command waited for input")
+
+// ErrWorkspaceNotFound matches the errors of lookups of workspaces that don't exist in the cluster,
+// e.g. a *WorkspaceNotFoundError.
+var ErrWorkspaceNotFound = errors.New("Workspace not found")
+
+// WorkspaceNotFoundError is returned when a workspace isn't in the cluster's list of workspaces.
+type WorkspaceNotFoundError struct {
+	// The name of the workspace.
+	Name string
+}
+
+func (e *WorkspaceNotFoundError) Error() string {
+	return fmt.Sprintf("Tecton workspace with name '%v' does not exist.", e.Name)
+}
+
+func (e *WorkspaceNotFoundError) Is(target error) bool {
+	return target == ErrWorkspaceNotFound
+}
+
+// CommandError is returned when a command, e.g. `tecton workspace create` or an embedded script,
+// fails.
+type CommandError struct {
+	// What the command does, e.g. "create Tecton workspace 'prod'". Optional.
+	Action string
+	// The command line, e.g. "tecton workspace create prod --live".
+	Command string
+	// The output of the command, which may be truncated.
+	Output string
+	// The file the full output was written to if Output was truncated. Optional.
+	OutputFile string
+	// The correlation ID of the operation that ran the command. Optional.
+	CorrelationID string
+	// Why the command failed, usually an *exec.ExitError.
+	Err error
+}
+
+func (e *CommandError) Error() string {
+	var message strings.Builder
+	if e.Action != "" {
+		fmt.Fprintf(&message, "Command to %v failed.\nCommand: %v", e.Action, e.Command)
+	} else {
+		fmt.Fprintf(&message, "Command '%v' failed.", e.Command)
+	}
+	if e.CorrelationID != "" {
+		fmt.Fprintf(&message, "\nCorrelation ID: %v", e.CorrelationID)
+	}
+	if e.OutputFile != "" {
+		fmt.Fprintf(&message, "\nError: %v\nOutput (truncated, the full output is in %v): %v", e.Err, e.OutputFile, e.Output)
+	} else {
+		fmt.Fprintf(&message, "\nError: %v\nOutput: %v", e.Err, e.Output)
+	}
+	return message.String()
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// Returns the exit code of the command, or -1 if it didn't exit normally, e.g. because it couldn't
+// be started or timed out.
+func (e *CommandError) ExitCode() int {
+	var exitErr *exec.ExitError
+	if errors.As(e.Err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// ParseError is returned when the output of a command or script can't be parsed.
+type ParseError struct {
+	// What produced the output, e.g. "tecton workspace list" or "list_admins.py".
+	Source string
+	// The unparseable output.
+	Output string
+	// Why the output couldn't be parsed, e.g. a *json.SyntaxError. Optional.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("Failed to parse output of `%v`.\nGot: %v", e.Source, e.Output)
+	}
+	return fmt.Sprintf("Failed to parse output of `%v`.\nError: %v\nGot: %v", e.Source, e.Err, e.Output)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// SchemaError is returned when the output of a command or script is valid JSON, but doesn't have the
+// structure the provider expects, e.g. because a required field is missing or a field has an
+// unknown enum value. This usually means that the installed tecton CLI is a different version than
+// the provider supports, so the output isn't acted on.
+type SchemaError struct {
+	// What produced the output, e.g. "tecton access-control get-roles".
+	Source string
+	// The output.
+	Output string
+	// How the output deviates from the expected structure, e.g. "[0].resource_type is missing".
+	Problems []string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf(
+		"CLI/provider version mismatch: the output of `%v` doesn't have the structure the provider expects, so it isn't used. "+
+			"Check that the installed tecton CLI is the version the provider supports.\nProblems:\n  - %v\nGot: %v",
+		e.Source,
+		strings.Join(e.Problems, "\n  - "),
+		e.Output,
+	)
+}
+
+// Wrap describes err in the context it occurred in, e.g. "Failed to check workspace 'prod'", while
+// keeping it inspectable with errors.Is and errors.As. Returns nil if err is nil.
+func Wrap(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%v.\n%w", fmt.Sprintf(format, args...), err)
+}
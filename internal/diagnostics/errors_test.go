@@ -0,0 +1,89 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+)
+
+func TestCommandError(t *testing.T) {
+	exitErr := exec.Command("sh", "-c", "exit 3").Run()
+	err := Wrap(&CommandError{
+		Action:  "create Tecton workspace 'prod'",
+		Command: "tecton workspace create prod --live",
+		Output:  "Workspace prod already exists",
+		Err:     exitErr,
+	}, "Failed to set up workspace '%v'", "prod")
+
+	expected := "Failed to set up workspace 'prod'.\n" +
+		"Command to create Tecton workspace 'prod' failed.\n" +
+		"Command: tecton workspace create prod --live\n" +
+		"Error: exit status 3\n" +
+		"Output: Workspace prod already exists"
+	if err.Error() != expected {
+		t.Errorf("expected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+
+	var commandErr *CommandError
+	if !errors.As(err, &commandErr) {
+		t.Fatal("expected the wrapped error to be a *CommandError")
+	}
+	if commandErr.ExitCode() != 3 {
+		t.Errorf("expected exit code 3, got %v", commandErr.ExitCode())
+	}
+	var unwrapped *exec.ExitError
+	if !errors.As(err, &unwrapped) {
+		t.Error("expected the wrapped error to be an *exec.ExitError")
+	}
+}
+
+func TestCommandError_timedOut(t *testing.T) {
+	err := &CommandError{Command: "tecton plan", Err: fmt.Errorf("%w after 10m0s", ErrTimedOut)}
+	if !errors.Is(err, ErrTimedOut) {
+		t.Error("expected the error to match ErrTimedOut")
+	}
+	if err.ExitCode() != -1 {
+		t.Errorf("expected exit code -1, got %v", err.ExitCode())
+	}
+	expected := "Command 'tecton plan' failed.\nError: Command timed out after 10m0s\nOutput: "
+	if err.Error() != expected {
+		t.Errorf("expected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestParseError(t *testing.T) {
+	var target map[string]any
+	jsonErr := json.Unmarshal([]byte("oops"), &target)
+	err := &ParseError{Source: "list_admins.py", Output: "oops", Err: jsonErr}
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Error("expected the error to be a *json.SyntaxError")
+	}
+	expected := "Failed to parse output of `list_admins.py`.\nError: invalid character 'o' looking for beginning of value\nGot: oops"
+	if err.Error() != expected {
+		t.Errorf("expected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
+
+func TestWrap_nil(t *testing.T) {
+	if Wrap(nil, "Failed") != nil {
+		t.Error("expected nil")
+	}
+}
+
+func TestSchemaError(t *testing.T) {
+	err := &SchemaError{
+		Source:   "tecton access-control get-roles",
+		Output:   `[{"roles_granted": []}]`,
+		Problems: []string{"[0].resource_type is missing"},
+	}
+	expected := "CLI/provider version mismatch: the output of `tecton access-control get-roles` doesn't have the structure the provider expects, so it isn't used. " +
+		"Check that the installed tecton CLI is the version the provider supports.\n" +
+		"Problems:\n  - [0].resource_type is missing\n" +
+		`Got: [{"roles_granted": []}]`
+	if err.Error() != expected {
+		t.Errorf("expected:\n%v\ngot:\n%v", expected, err.Error())
+	}
+}
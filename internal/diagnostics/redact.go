@@ -0,0 +1,66 @@
+package diagnostics
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// The text secrets are replaced with.
+const Redacted = "<redacted>"
+
+// Values shorter than this aren't registered as secrets, since replacing them everywhere would mangle
+// unrelated text.
+const minSecretLength = 8
+
+// Matches an API key assigned to TECTON_API_KEY, e.g. in a command's environment that a traceback
+// echoes, whether or not the key was registered with a Redactor.
+var APIKeyAssignmentRegex = regexp.MustCompile(`TECTON_API_KEY=["']?[^\s"'<]+["']?`)
+
+// Redactor holds the secrets of a single provider instance, e.g. its API key, so that they can be
+// replaced in the output of its commands before it's logged or included in errors. A nil *Redactor
+// has no secrets. It's safe for concurrent use.
+type Redactor struct {
+	mu     sync.RWMutex
+	values []string
+}
+
+// Registers secret, e.g. the provider's API key, so that Redact replaces it. Empty and very short
+// values are ignored.
+func (r *Redactor) AddSecret(secret string) {
+	secret = strings.TrimSpace(secret)
+	if len(secret) < minSecretLength {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !slices.Contains(r.values, secret) {
+		r.values = append(r.values, secret)
+	}
+}
+
+// Returns the secrets registered with AddSecret.
+func (r *Redactor) Secrets() []string {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.values)
+}
+
+// Returns s with every registered secret and every value assigned to TECTON_API_KEY replaced with
+// "<redacted>", e.g. for command output that's included in errors or logs.
+func (r *Redactor) Redact(s string) string {
+	for _, secret := range r.Secrets() {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	return RedactAPIKeyAssignments(s)
+}
+
+// Returns s with every value assigned to TECTON_API_KEY replaced with "<redacted>", for text that
+// isn't tied to a provider instance's Redactor.
+func RedactAPIKeyAssignments(s string) string {
+	return APIKeyAssignmentRegex.ReplaceAllString(s, "TECTON_API_KEY="+Redacted)
+}
@@ -0,0 +1,43 @@
+package diagnostics
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	redactor := &Redactor{}
+	redactor.AddSecret("registered-key-123")
+	redactor.AddSecret("short")
+
+	testCases := map[string]struct {
+		text     string
+		expected string
+	}{
+		"registered secret":  {text: "401: invalid key registered-key-123", expected: "401: invalid key <redacted>"},
+		"short values":       {text: "short", expected: "short"},
+		"environment":        {text: "env: TECTON_API_KEY=unregistered API_SERVICE=x", expected: "env: TECTON_API_KEY=<redacted> API_SERVICE=x"},
+		"quoted environment": {text: "TECTON_API_KEY='unregistered'", expected: "TECTON_API_KEY=<redacted>"},
+		"already redacted":   {text: "TECTON_API_KEY=<redacted>", expected: "TECTON_API_KEY=<redacted>"},
+		"empty environment":  {text: "TECTON_API_KEY= API_SERVICE=x", expected: "TECTON_API_KEY= API_SERVICE=x"},
+		"no secrets":         {text: "Workspace 'prod' not found", expected: "Workspace 'prod' not found"},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := redactor.Redact(testCase.text)
+			if actual != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedact_otherInstance(t *testing.T) {
+	redactor := &Redactor{}
+	redactor.AddSecret("registered-key-123")
+
+	var other *Redactor
+	if actual := other.Redact("key registered-key-123"); actual != "key registered-key-123" {
+		t.Errorf("expected another instance's secret to be kept, got %q", actual)
+	}
+	if actual := (&Redactor{}).Redact("TECTON_API_KEY=abc"); actual != "TECTON_API_KEY=<redacted>" {
+		t.Errorf("expected the assignment to be redacted, got %q", actual)
+	}
+}
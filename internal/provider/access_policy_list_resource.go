@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ list.ListResource              = &accessPolicyListResource{}
+	_ list.ListResourceWithConfigure = &accessPolicyListResource{}
+)
+
+// NewAccessPolicyListResource is a helper function to simplify the provider implementation.
+func NewAccessPolicyListResource() list.ListResource {
+	return &accessPolicyListResource{}
+}
+
+// accessPolicyListResource lists the principals with direct role grants so that their access
+// policies can be imported with `terraform query`.
+type accessPolicyListResource struct {
+	CLI           tectonclient.Client
+	WorkspaceData *WorkspaceCache
+}
+
+// accessPolicyListResourceModel maps the list resource config schema data.
+type accessPolicyListResourceModel struct {
+	PrincipalType types.String `tfsdk:"principal_type"`
+}
+
+// The JSON output of the `list_principals.py` script.
+type principalList struct {
+	Users           []string `json:"users"`
+	ServiceAccounts []string `json:"service_accounts"`
+}
+
+// Returns the users and then the service accounts in the list.
+func (l principalList) Principals() []tectonclient.Principal {
+	var principals []tectonclient.Principal
+	for _, userID := range l.Users {
+		principals = append(principals, tectonclient.Principal{UserID: userID})
+	}
+	for _, serviceAccountID := range l.ServiceAccounts {
+		principals = append(principals, tectonclient.Principal{ServiceAccountID: serviceAccountID})
+	}
+	return principals
+}
+
+// Configure adds the provider configured client to the list resource.
+func (r *accessPolicyListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.CLI = providerData.CLI
+	r.WorkspaceData = providerData.WorkspaceData
+}
+
+// Metadata returns the resource type name.
+func (r *accessPolicyListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_access_policy"
+}
+
+// ListResourceConfigSchema defines the filters accepted by the list resource.
+func (r *accessPolicyListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"principal_type": schema.StringAttribute{
+				Description: "If set, only list access policies for this type of principal. Must be one of (\"user\", \"service_account\").",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("user", "service_account"),
+				},
+			},
+		},
+	}
+}
+
+// List streams an access policy for every user and service account with a direct role grant on the
+// organization or on any workspace.
+func (r *accessPolicyListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "list.tecton_access_policy", "List")
+	var config accessPolicyListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	workspaces, err := r.WorkspaceData.Get(ctx)
+	if err != nil {
+		AddCommandError(&diags, "Failed to list Tecton workspaces", err)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	principals, err := ListPrincipals(ctx, r.CLI, workspaces)
+	if err != nil {
+		AddCommandError(&diags, "Failed to list Tecton principals", err)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	var policies []accessPolicyResourceModel
+	if config.PrincipalType.IsNull() || config.PrincipalType.ValueString() == "user" {
+		for _, userID := range principals.Users {
+			policies = append(policies, accessPolicyResourceModel{
+				ID:       types.StringValue(fmt.Sprintf("user-%v", userID)),
+				UserID:   types.StringValue(userID),
+				Timeouts: nullTimeouts(),
+			})
+		}
+	}
+	if config.PrincipalType.IsNull() || config.PrincipalType.ValueString() == "service_account" {
+		for _, serviceAccountID := range principals.ServiceAccounts {
+			policies = append(policies, accessPolicyResourceModel{
+				ID:               types.StringValue(fmt.Sprintf("service-%v", serviceAccountID)),
+				ServiceAccountID: types.StringValue(serviceAccountID),
+				Timeouts:         nullTimeouts(),
+			})
+		}
+	}
+	if req.Limit > 0 && int64(len(policies)) > req.Limit {
+		policies = policies[:req.Limit]
+	}
+
+	// Reading the roles takes a command per principal, so only do it if Terraform needs the resource
+	readErrs := make([]error, len(policies))
+	if req.IncludeResource {
+		resource := &accessPolicyResource{CLI: r.CLI}
+		_ = runParallel(len(policies), func(i int) error {
+			_, readErrs[i] = resource.GetFromTecton(ctx, &policies[i])
+			return readErrs[i]
+		})
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		for i, policy := range policies {
+			result := req.NewListResult(ctx)
+			result.DisplayName = policy.ID.ValueString()
+			result.Diagnostics.Append(result.Identity.Set(ctx, accessPolicyResourceIdentityModel{ID: policy.ID})...)
+			if req.IncludeResource {
+				if readErrs[i] != nil {
+					AddCommandError(&result.Diagnostics, "Failed to read Tecton roles", readErrs[i])
+				} else {
+					result.Diagnostics.Append(result.Resource.Set(ctx, policy)...)
+				}
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
+
+// Lists the users and service accounts with roles assigned directly on the organization or on any of
+// the given workspaces.
+func ListPrincipals(ctx context.Context, cli tectonclient.Client, workspaces tectonclient.Workspaces) (principalList, error) {
+	var principals principalList
+	tflog.Info(ctx, "Listing principals with direct role assignments")
+
+	names := append(append([]string{}, workspaces.Lives...), workspaces.Devs...)
+	output, err := cli.RunScript(ctx, "list_principals.py", names...)
+	if err != nil {
+		return principals, err
+	}
+	err = json.Unmarshal(output, &principals)
+	if err != nil {
+		return principals, &diagnostics.ParseError{Source: "list_principals.py", Output: string(output), Err: err}
+	}
+	return principals, nil
+}
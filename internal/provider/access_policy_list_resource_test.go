@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+func TestAccessPolicyListResourceList(t *testing.T) {
+	fakeTectonPythonCLI(
+		t,
+		`{"users": ["alice@example.com"], "service_accounts": ["abc123"]}`,
+		`[{"resource_type": "WORKSPACE", "workspace_name": "prod", "roles_granted": [{"role": "owner"}]}]`,
+	)
+	ctx := context.Background()
+
+	var listSchemaResp list.ListResourceSchemaResponse
+	NewAccessPolicyListResource().ListResourceConfigSchema(ctx, list.ListResourceSchemaRequest{}, &listSchemaResp)
+	var schemaResp fwresource.SchemaResponse
+	NewAccessPolicyResource().Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	var identitySchemaResp fwresource.IdentitySchemaResponse
+	NewAccessPolicyResource().(fwresource.ResourceWithIdentity).IdentitySchema(ctx, fwresource.IdentitySchemaRequest{}, &identitySchemaResp)
+
+	testCases := map[string]struct {
+		principalType tftypes.Value
+		expected      []string
+	}{
+		"all": {
+			principalType: tftypes.NewValue(tftypes.String, nil),
+			expected:      []string{"user-alice@example.com", "service-abc123"},
+		},
+		"service accounts": {
+			principalType: tftypes.NewValue(tftypes.String, "service_account"),
+			expected:      []string{"service-abc123"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := list.ListRequest{
+				Config: tfsdk.Config{
+					Schema: listSchemaResp.Schema,
+					Raw: tftypes.NewValue(listSchemaResp.Schema.Type().TerraformType(ctx), map[string]tftypes.Value{
+						"principal_type": testCase.principalType,
+					}),
+				},
+				IncludeResource:        true,
+				ResourceSchema:         schemaResp.Schema,
+				ResourceIdentitySchema: identitySchemaResp.IdentitySchema,
+			}
+			r := &accessPolicyListResource{WorkspaceData: NewWorkspaceCache(tectonclient.Workspaces{Lives: []string{"prod"}})}
+			stream := list.ListResultsStream{}
+			r.List(ctx, req, &stream)
+
+			var ids []string
+			for result := range stream.Results {
+				var model accessPolicyResourceModel
+				result.Diagnostics.Append(result.Resource.Get(ctx, &model)...)
+				if result.Diagnostics.HasError() {
+					t.Fatalf("unexpected error: %v", result.Diagnostics)
+				}
+				if len(model.Workspaces["prod"]) != 1 || model.Workspaces["prod"][0].ValueString() != "owner" {
+					t.Errorf("expected %v to have the owner role on prod, got %v", result.DisplayName, model.Workspaces)
+				}
+				ids = append(ids, result.DisplayName)
+			}
+			if len(ids) != len(testCase.expected) {
+				t.Fatalf("expected %v, got %v", testCase.expected, ids)
+			}
+			for i := range ids {
+				if ids[i] != testCase.expected[i] {
+					t.Fatalf("expected %v, got %v", testCase.expected, ids)
+				}
+			}
+		})
+	}
+}
@@ -3,26 +3,34 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"golang.org/x/exp/slices"
-	"os/exec"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -30,6 +38,8 @@ var (
 	_ resource.Resource                = &accessPolicyResource{}
 	_ resource.ResourceWithConfigure   = &accessPolicyResource{}
 	_ resource.ResourceWithImportState = &accessPolicyResource{}
+	_ resource.ResourceWithModifyPlan  = &accessPolicyResource{}
+	_ resource.ResourceWithIdentity    = &accessPolicyResource{}
 )
 
 // NewWorkspaceResource is a helper function to simplify the provider implementation.
@@ -39,39 +49,73 @@ func NewAccessPolicyResource() resource.Resource {
 
 // accessPolicyResource is the resource implementation.
 type accessPolicyResource struct {
-	CommandEnv []string
+	CLI                tectonclient.Client
+	WorkspaceData      *WorkspaceCache
+	MinWorkspaceOwners int64
+	// The roles in order of increasing power, used to sort roles and to find implied roles.
+	RoleOrder      []string
+	Notifier       *Notifier
+	DisableDestroy bool
+	NameRules      tectonclient.NameRules
+	// How long before a service account's API key expires plans warn about it.
+	KeyExpiryWarningWindow time.Duration
 }
 
+// The values of `on_destroy`.
+const (
+	accessPolicyOnDestroyRevoke = "revoke"
+	accessPolicyOnDestroyRetain = "retain"
+)
+
 // The valid roles, in order of increasing power.
 var validRoles = []string{"viewer", "operator", "editor", "owner"}
 
 // accessPolicyResourceModel maps the resource schema data.
 type accessPolicyResourceModel struct {
-	ID               types.String              `tfsdk:"id"`
-	LastUpdated      types.String              `tfsdk:"last_updated"`
-	UserID           types.String              `tfsdk:"user_id"`
-	ServiceAccountID types.String              `tfsdk:"service_account_id"`
-	Admin            types.Bool                `tfsdk:"admin"`
-	AllWorkspaces    []types.String            `tfsdk:"all_workspaces"`
-	Workspaces       map[string][]types.String `tfsdk:"workspaces"`
+	ID                   types.String              `tfsdk:"id"`
+	LastUpdated          types.String              `tfsdk:"last_updated"`
+	UserID               types.String              `tfsdk:"user_id"`
+	ServiceAccountID     types.String              `tfsdk:"service_account_id"`
+	Admin                types.Bool                `tfsdk:"admin"`
+	AllWorkspaces        []types.String            `tfsdk:"all_workspaces"`
+	Workspaces           map[string][]types.String `tfsdk:"workspaces"`
+	SkipSafetyCheck      types.Bool                `tfsdk:"skip_safety_check"`
+	UnmanagedRoles       types.Map                 `tfsdk:"unmanaged_roles"`
+	SuppressImpliedRoles types.Bool                `tfsdk:"suppress_implied_roles"`
+	AdoptExisting        types.Bool                `tfsdk:"adopt_existing"`
+	EnforceEmpty         types.Bool                `tfsdk:"enforce_empty"`
+	AssignmentSources    types.List                `tfsdk:"assignment_sources"`
+	ScopedRoles          types.List                `tfsdk:"scoped_roles"`
+	OnDestroy            types.String              `tfsdk:"on_destroy"`
+	Timeouts             timeouts.Value            `tfsdk:"timeouts"`
 }
 
-// A policy for a single workspace (or organization) in the JSON output of `tecton access-control get-roles`.
-type tectonGetRolesPolicy struct {
-	ResourceType  string                      `json:"resource_type"`
-	WorkspaceName string                      `json:"workspace_name,omitempty"`
-	RolesGranted  []tectonGetRolesRoleGranted `json:"roles_granted"`
+// The type of an element of `assignment_sources`.
+var assignmentSourceType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"workspace": types.StringType,
+		"role":      types.StringType,
+		"source":    types.StringType,
+		"group":     types.StringType,
+	},
 }
 
-// A single role (e.g. "owner") in the JSON output of `tecton access-control get-roles`.
-type tectonGetRolesRoleGranted struct {
-	Role              string                          `json:"role"`
-	AssignmentSources []tectonGetRoleAssignmentSource `json:"assignment_sources"`
+// The type of an element of `scoped_roles`.
+var scopedRoleType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"resource_type": types.StringType,
+		"resource":      types.StringType,
+		"roles":         types.ListType{ElemType: types.StringType},
+	},
 }
 
-// An assignment source (e.g. DIRECT) in the JSON output of `tecton access-control get-roles`.
-type tectonGetRoleAssignmentSource struct {
-	AssignmentType string `json:"assignment_type"`
+// The key of unmanaged_roles for roles granted on the organization, which can't clash with a
+// workspace name.
+const unmanagedRolesOrganizationKey = "*"
+
+// accessPolicyResourceIdentityModel maps the resource identity schema data.
+type accessPolicyResourceIdentityModel struct {
+	ID types.String `tfsdk:"id"`
 }
 
 // A type to store a key-value pair in a map.
@@ -86,18 +130,27 @@ func (r *accessPolicyResource) Configure(_ context.Context, req resource.Configu
 		return
 	}
 
-	providerData, ok := req.ProviderData.(ProviderData)
+	providerData, ok := req.ProviderData.(*ProviderData)
 
 	if !ok {
-		resp.Diagnostics.AddError(
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.CommandEnv = providerData.CommandEnv
+	r.CLI = providerData.CLI
+	r.Notifier = providerData.Notifier
+	r.WorkspaceData = providerData.WorkspaceData
+	r.MinWorkspaceOwners = providerData.MinWorkspaceOwners
+	r.RoleOrder = providerData.RoleOrder
+	r.DisableDestroy = providerData.DisableDestroy
+	r.NameRules = providerData.NameRules
+	r.KeyExpiryWarningWindow = providerData.KeyExpiryWarningWindow
 }
 
 // Metadata returns the resource type name.
@@ -106,7 +159,7 @@ func (r *accessPolicyResource) Metadata(_ context.Context, req resource.Metadata
 }
 
 // Schema defines the schema for the resource.
-func (r *accessPolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *accessPolicyResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -144,6 +197,110 @@ func (r *accessPolicyResource) Schema(_ context.Context, _ resource.SchemaReques
 				Description: "True if this account should have admin privileges. False otherwise.",
 				Optional:    true,
 			},
+			"unmanaged_roles": schema.MapAttribute{
+				Description: "Roles granted to this account that the provider doesn't manage, e.g. custom roles or roles added in newer Tecton versions. " +
+					"A map where the keys are workspace names, or \"*\" for roles granted on all workspaces, and the values are lists of roles. " +
+					"These roles are never granted or revoked by the provider.",
+				Computed: true,
+				ElementType: types.ListType{
+					ElemType: types.StringType,
+				},
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"suppress_implied_roles": schema.BoolAttribute{
+				Description: "If true, lower roles that Tecton reports because they are implied by a higher role, e.g. `viewer` for an `owner`, are ignored unless they are " +
+					"in the configuration, so that clusters which expand inherited roles don't cause a diff on every plan. Defaults to false.",
+				Optional: true,
+			},
+			"adopt_existing": schema.BoolAttribute{
+				Description: "If true, creating this resource for an account that already has roles adopts them instead of failing, and then grants and revokes roles " +
+					"to match the configuration, as an alternative to `terraform import`. Roles that aren't in the configuration are revoked, except unmanaged roles. " +
+					"Only affects creation. Defaults to false.",
+				Optional: true,
+			},
+			"enforce_empty": schema.BoolAttribute{
+				Description: "Set to true to declare that this account must have no roles at all. Every role it has, including admin, is revoked when the resource is created, " +
+					"even if it already has roles, and any role granted outside of Terraform shows up as a diff and is revoked on the next apply. " +
+					"Unmanaged roles are reported in `unmanaged_roles` but never revoked. Cannot be set together with `admin`, `all_workspaces` or `workspaces`.",
+				Optional: true,
+				Validators: []validator.Bool{
+					boolvalidator.ConflictsWith(
+						path.MatchRoot("admin"),
+						path.MatchRoot("all_workspaces"),
+						path.MatchRoot("workspaces"),
+					),
+				},
+			},
+			"assignment_sources": schema.ListNestedAttribute{
+				Description: "Why this account has each of its roles, including roles that aren't in the configuration, e.g. because they are granted " +
+					"through a principal group. Contains an element for every way each role is granted, sorted by workspace and role. Roles whose " +
+					"source Tecton doesn't report are omitted.",
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"workspace": schema.StringAttribute{
+							Description: "The workspace the role is granted on, or \"*\" for roles granted on all workspaces, including admin.",
+							Computed:    true,
+						},
+						"role": schema.StringAttribute{
+							Description: "The role, e.g. \"owner\".",
+							Computed:    true,
+						},
+						"source": schema.StringAttribute{
+							Description: "\"DIRECT\" if the role is granted to this account directly, or \"GROUP\" if it is granted through a principal group. " +
+								"Other sources are reported as Tecton names them.",
+							Computed: true,
+						},
+						"group": schema.StringAttribute{
+							Description: "The name of the principal group the role is granted through. Null unless `source` is \"GROUP\".",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"on_destroy": schema.StringAttribute{
+				Description: "What happens to the account's roles when this resource is destroyed, e.g. because it was removed from the configuration. " +
+					"\"revoke\" revokes every role the resource manages. \"retain\" only removes the resource from the state and leaves the account's roles unchanged, " +
+					"e.g. to move the access policy to another Terraform state, and isn't blocked by the provider's `disable_destroy`. " +
+					"Must be applied before the destroy to take effect. Defaults to \"revoke\".",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(accessPolicyOnDestroyRevoke, accessPolicyOnDestroyRetain),
+				},
+			},
+			"scoped_roles": schema.ListNestedAttribute{
+				Description: "Roles granted to this account on resources other than the organization and workspaces, e.g. secret scopes, which newer " +
+					"clusters report. Contains an element for every resource, sorted by resource type and resource. These roles are never granted or revoked by the provider, and a warning is reported when a refresh finds new ones.",
+				Computed: true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"resource_type": schema.StringAttribute{
+							Description: "The type of the resource as Tecton reports it, e.g. \"SECRET_SCOPE\".",
+							Computed:    true,
+						},
+						"resource": schema.StringAttribute{
+							Description: "The name of the resource, or its ID if Tecton doesn't report a name.",
+							Computed:    true,
+						},
+						"roles": schema.ListAttribute{
+							Description: "The roles granted on the resource, sorted.",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+			"skip_safety_check": schema.BoolAttribute{
+				Description: "Before admin is revoked from this account, the provider checks that another user or service account is still an admin, " +
+					"and refuses to revoke it otherwise since nobody (including Terraform) could manage the cluster afterwards. " +
+					"Set to true to skip this check. Must be applied before the destroy to take effect. Defaults to false.",
+				Optional: true,
+			},
 			"all_workspaces": schema.ListAttribute{
 				Description: "The list of roles that will be applied to all workspaces. List values must be one of (\"viewer\", \"operator\", \"editor\", \"owner\").",
 				Optional:    true,
@@ -168,6 +325,28 @@ func (r *accessPolicyResource) Schema(_ context.Context, _ resource.SchemaReques
 					),
 				},
 			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create:            true,
+				Read:              true,
+				Update:            true,
+				Delete:            true,
+				CreateDescription: "How long creating the resource may take. Defaults to \"30m\".",
+				ReadDescription:   "How long reading the resource may take. Defaults to \"30m\".",
+				UpdateDescription: "How long updating the resource may take. Defaults to \"30m\".",
+				DeleteDescription: "How long deleting the resource may take. Defaults to \"30m\".",
+			}),
+		},
+	}
+}
+
+// IdentitySchema defines the identity of the resource, which is used to import and list access policies.
+func (r *accessPolicyResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"id": identityschema.StringAttribute{
+				Description:       "Identifier for this access policy. In the format of {user|service}-{id}.",
+				RequiredForImport: true,
+			},
 		},
 	}
 }
@@ -182,12 +361,221 @@ func (r *accessPolicyResource) ConfigValidators(ctx context.Context) []resource.
 			path.MatchRoot("admin"),
 			path.MatchRoot("all_workspaces"),
 			path.MatchRoot("workspaces"),
+			path.MatchRoot("enforce_empty"),
 		),
 	}
 }
 
+// ModifyPlan handles principals and workspaces that aren't known yet, e.g. because the workspaces are
+// created in the same run with computed names. If Terraform supports deferred actions, the access
+// policy is planned in a later run rather than showing a misleading diff. Otherwise the unknown values
+// are planned as-is, and every value derived from them is marked as "(known after apply)".
+//
+// The principal's ID is checked against the cluster's rules, and fully known plans are checked against
+// the provider's `min_workspace_owners` policy and for revoking admin from the last admin.
+func (r *accessPolicyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_access_policy", "ModifyPlan")
+	// The resource is being destroyed, which revokes all of its roles unless they are retained
+	if req.Plan.Raw.IsNull() {
+		var id, onDestroy types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("on_destroy"), &onDestroy)...)
+		if resp.Diagnostics.HasError() || onDestroy.ValueString() == accessPolicyOnDestroyRetain {
+			return
+		}
+		if !CheckDestroyAllowed(r.DisableDestroy, fmt.Sprintf("tecton_access_policy '%v'", id.ValueString()), &resp.Diagnostics) {
+			return
+		}
+		r.CheckModifyPlanSafety(ctx, req, resp)
+		return
+	}
+
+	var userID, serviceAccountID types.String
+	var allWorkspaces types.List
+	var workspaces types.Map
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("user_id"), &userID)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("service_account_id"), &serviceAccountID)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("all_workspaces"), &allWorkspaces)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("workspaces"), &workspaces)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	CheckNameRule(r.NameRules.UserID, userID, path.Root("user_id"), &resp.Diagnostics)
+	CheckNameRule(r.NameRules.ServiceAccountID, serviceAccountID, path.Root("service_account_id"), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	principalUnknown := userID.IsUnknown() || serviceAccountID.IsUnknown()
+	rolesUnknown := containsUnknown(allWorkspaces) || containsUnknown(workspaces)
+	if !principalUnknown && !rolesUnknown {
+		r.CheckModifyPlanSafety(ctx, req, resp)
+		return
+	}
+
+	if req.ClientCapabilities.DeferralAllowed {
+		tflog.Info(ctx, "Deferring access policy since its principal or workspaces are not known yet")
+		resp.Deferred = &resource.Deferred{
+			Reason: resource.DeferredReasonResourceConfigUnknown,
+		}
+		return
+	}
+
+	tflog.Info(ctx, "Access policy principal or workspaces are not known yet, so they will be known after apply")
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("last_updated"), types.StringUnknown())...)
+	if principalUnknown {
+		// The ID is derived from the principal, so the prior ID can't be kept
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringUnknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("unmanaged_roles"), types.MapUnknown(types.ListType{ElemType: types.StringType}))...)
+	}
+}
+
+// Reads the plan and prior state of a ModifyPlan request and checks them with CheckAdminRevocation and
+// CheckMinWorkspaceOwners, so that plans which are certain to fail are rejected before apply. The same
+// checks are repeated during apply in case the cluster changed in between. The plan must not contain
+// unknown values.
+func (r *accessPolicyResource) CheckModifyPlanSafety(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing can be revoked from a policy that is being created
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var state, plan accessPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	skipSafetyCheck := state.SkipSafetyCheck
+	if !req.Plan.Raw.IsNull() {
+		resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+		skipSafetyCheck = plan.SkipSafetyCheck
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.CheckAdminRevocation(ctx, &plan, &state, skipSafetyCheck, &resp.Diagnostics)
+	r.CheckMinWorkspaceOwners(ctx, &plan, &state, &resp.Diagnostics)
+}
+
+// Enforces the provider's `min_workspace_owners` policy. Adds an error to diags for every workspace
+// where plan revokes owner from the principal and the workspace would be left with fewer than
+// MinWorkspaceOwners owners.
+func (r *accessPolicyResource) CheckMinWorkspaceOwners(
+	ctx context.Context,
+	plan *accessPolicyResourceModel,
+	state *accessPolicyResourceModel,
+	diags *diag.Diagnostics,
+) {
+	if r.MinWorkspaceOwners == 0 {
+		return
+	}
+
+	// Find the workspaces where the principal loses owner, either directly or through all_workspaces
+	isOwner := func(policy *accessPolicyResourceModel, workspace string) bool {
+		return slices.Contains(policy.AllWorkspaces, types.StringValue("owner")) ||
+			slices.Contains(policy.Workspaces[workspace], types.StringValue("owner"))
+	}
+	var candidates []string
+	if slices.Contains(state.AllWorkspaces, types.StringValue("owner")) {
+		workspaces, err := r.WorkspaceData.Get(ctx)
+		if err != nil {
+			AddCommandError(diags, "Failed to list Tecton workspaces", err)
+			return
+		}
+		candidates = append(append(candidates, workspaces.Lives...), workspaces.Devs...)
+	} else {
+		for workspace := range state.Workspaces {
+			candidates = append(candidates, workspace)
+		}
+	}
+	var revoked []string
+	for _, workspace := range candidates {
+		if isOwner(state, workspace) && !isOwner(plan, workspace) {
+			revoked = append(revoked, workspace)
+		}
+	}
+	if len(revoked) == 0 {
+		return
+	}
+	slices.Sort(revoked)
+
+	tflog.Info(ctx, fmt.Sprintf("Checking that workspaces [%v] keep at least %v owners", strings.Join(revoked, ", "), r.MinWorkspaceOwners))
+	owners, err := GetWorkspaceOwners(ctx, r.CLI, revoked)
+	if err != nil {
+		AddError(
+			diags,
+			ClassifyError(err),
+			"Failed to check Tecton workspace owners",
+			fmt.Sprintf("Failed to check whether workspaces keep at least %v owners.\nError: %v", r.MinWorkspaceOwners, err.Error()),
+		)
+		return
+	}
+
+	self := tectonclient.Principal{UserID: state.UserID.ValueString(), ServiceAccountID: state.ServiceAccountID.ValueString()}
+	for _, workspace := range revoked {
+		var remaining []string
+		for _, owner := range owners[workspace].Principals() {
+			if owner != self {
+				remaining = append(remaining, owner.String())
+			}
+		}
+		if int64(len(remaining)) < r.MinWorkspaceOwners {
+			AddError(
+				diags,
+				ErrorCodeUnsafeOperation,
+				"Workspace Would Have Too Few Owners",
+				fmt.Sprintf(
+					"Revoking owner from %v would leave workspace '%v' with %v owners, but the provider's `min_workspace_owners` requires at least %v.\n"+
+						"Remaining owners: [%v]",
+					self.String(),
+					workspace,
+					len(remaining),
+					r.MinWorkspaceOwners,
+					strings.Join(remaining, ", "),
+				),
+			)
+		}
+	}
+}
+
+// Reads the users and service accounts with the owner role on each of the given workspaces.
+func GetWorkspaceOwners(ctx context.Context, cli tectonclient.Client, workspaces []string) (map[string]principalList, error) {
+	output, err := cli.RunScript(ctx, "workspace_owners.py", workspaces...)
+	if err != nil {
+		return nil, err
+	}
+	var owners map[string]principalList
+	err = json.Unmarshal(output, &owners)
+	if err != nil {
+		return nil, &diagnostics.ParseError{Source: "workspace_owners.py", Output: string(output), Err: err}
+	}
+	return owners, nil
+}
+
+// Returns true if the value, or any value nested in it, is unknown.
+func containsUnknown(value attr.Value) bool {
+	if value.IsUnknown() {
+		return true
+	}
+	var elements []attr.Value
+	switch v := value.(type) {
+	case types.List:
+		elements = v.Elements()
+	case types.Map:
+		for _, element := range v.Elements() {
+			elements = append(elements, element)
+		}
+	}
+	for _, element := range elements {
+		if containsUnknown(element) {
+			return true
+		}
+	}
+	return false
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *accessPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_access_policy", "Create")
 	// Retrieve values from plan
 	var plan accessPolicyResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -196,6 +584,14 @@ func (r *accessPolicyResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withOperationTimeout(ctx, "create", createTimeout)
+	defer cancel()
+
 	var entity string
 	if plan.UserID.ValueString() != "" {
 		entity = fmt.Sprintf("user '%v'", plan.UserID.ValueString())
@@ -204,45 +600,66 @@ func (r *accessPolicyResource) Create(ctx context.Context, req resource.CreateRe
 	}
 	tflog.Info(ctx, fmt.Sprintf("Creating access policy for %v", entity))
 
-	// Fail if any roles already exist. The state must first be imported.
+	// Report the changes made in Tecton to the notification webhook, even if creation fails. The
+	// changes are made through a copy of the resource, since Terraform runs operations on the same
+	// resource concurrently.
+	cli, changes := r.CLI.RecordingChanges()
+	policy := *r
+	policy.CLI = cli
+	defer r.Notifier.Notify(ctx, fmt.Sprintf("tecton_access_policy '%v'", accessPolicyID(&plan).ValueString()), changes, &resp.Diagnostics)
+
+	// Fail if any roles already exist, unless they should be adopted. Otherwise the state must first
+	// be imported.
 	var state accessPolicyResourceModel
 	state.UserID = plan.UserID
 	state.ServiceAccountID = plan.ServiceAccountID
+	state.SuppressImpliedRoles = plan.SuppressImpliedRoles
 	tflog.Info(ctx, "Creating an access_policy")
-	alreadyExists, err := r.GetFromTecton(ctx, &state)
+	alreadyExists, err := policy.GetFromTecton(ctx, &state)
 	if err != nil {
-		resp.Diagnostics.AddError("Role Read Failure", err.Error())
+		AddCommandError(&resp.Diagnostics, "Role Read Failure", err)
 		return
 	}
-	if alreadyExists {
-		resp.Diagnostics.AddError(
+	if alreadyExists && !plan.AdoptExisting.ValueBool() && !plan.EnforceEmpty.ValueBool() {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeAlreadyExists,
 			"Access Policy Already Exists",
 			fmt.Sprintf(
 				"An access policy already exists for %v on Tecton. The state must first be imported "+
-					"via `terraform import` so that no permissions are accidentally deleted.",
+					"via `terraform import` so that no permissions are accidentally deleted, or `adopt_existing = true` "+
+					"must be set to reconcile the existing roles with the configuration.",
 				entity,
 			),
 		)
 		return
 	}
 
-	// Create resource by updating from an empty state
+	// Create resource by updating from an empty state, or from the existing roles if they're adopted
+	// or must be revoked
 	var emptyState accessPolicyResourceModel
 	emptyState.UserID = plan.UserID
 	emptyState.ServiceAccountID = plan.ServiceAccountID
-	err = r.UpdateAccessPolicy(ctx, &plan, &emptyState)
+	fromState := &emptyState
+	if alreadyExists {
+		tflog.Info(ctx, fmt.Sprintf("Adopting the existing access policy for %v", entity))
+		policy.CheckAdminRevocation(ctx, &plan, &state, plan.SkipSafetyCheck, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		fromState = &state
+	}
+	err = policy.UpdateAccessPolicy(ctx, &plan, fromState)
 	if err != nil {
-		resp.Diagnostics.AddError("Access Policy Creation Failure", err.Error())
+		policy.SavePartialState(ctx, &plan, state.UnmanagedRoles, entity, err, resp)
 		return
 	}
 
 	// // Generated computed values
-	if plan.UserID.ValueString() != "" {
-		plan.ID = types.StringValue(fmt.Sprintf("user-%v", state.UserID.ValueString()))
-	} else if plan.ServiceAccountID.ValueString() != "" {
-		plan.ID = types.StringValue(fmt.Sprintf("service-%v", state.ServiceAccountID.ValueString()))
-	}
+	plan.ID = accessPolicyID(&plan)
 	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850)) // Time format copy-pasted from Hashicorp tutorial
+	plan.UnmanagedRoles = state.UnmanagedRoles
+	policy.VerifyAccessPolicy(ctx, &plan, &resp.Diagnostics)
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -250,10 +667,64 @@ func (r *accessPolicyResource) Create(ctx context.Context, req resource.CreateRe
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	setAccessPolicyIdentity(ctx, resp.Identity, plan.ID, &resp.Diagnostics)
+}
+
+// Saves the state of an access policy whose Create failed to make some of its role changes. Failing
+// would taint the access policy, and replacing it would revoke every role it was granted before
+// granting them again, so the failure is only a warning and the planned roles are saved. The next
+// refresh reads the roles that were actually granted, so the next plan updates the access policy in
+// place to make the remaining changes.
+func (r *accessPolicyResource) SavePartialState(
+	ctx context.Context,
+	plan *accessPolicyResourceModel,
+	unmanagedRoles types.Map,
+	entity string,
+	err error,
+	resp *resource.CreateResponse,
+) {
+	AddWarning(
+		&resp.Diagnostics,
+		ClassifyError(err),
+		"Access Policy Partially Created",
+		fmt.Sprintf(
+			"Created the access policy for %v, but failed to make some of its role changes, so the roles in Tecton don't match the configuration yet. "+
+				"The next plan updates the access policy in place to make the remaining changes.\nError: %v",
+			entity,
+			err.Error(),
+		),
+	)
+
+	plan.ID = accessPolicyID(plan)
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	plan.UnmanagedRoles = unmanagedRoles
+	plan.AssignmentSources = types.ListValueMust(assignmentSourceType, []attr.Value{})
+	plan.ScopedRoles = types.ListValueMust(scopedRoleType, []attr.Value{})
+	actual := *plan
+	if _, readErr := r.GetFromTecton(ctx, &actual); readErr != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Failed to read the roles granted before the failure, so `assignment_sources` and `scoped_roles` are empty until the next refresh: %v", readErr.Error()))
+	} else {
+		plan.AssignmentSources = actual.AssignmentSources
+		plan.ScopedRoles = actual.ScopedRoles
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Saving access policy '%v' after a partial failure", plan.ID.ValueString()))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	setAccessPolicyIdentity(ctx, resp.Identity, plan.ID, &resp.Diagnostics)
+}
+
+// Returns the ID of the access policy for the user or service account in plan.
+func accessPolicyID(plan *accessPolicyResourceModel) types.String {
+	if plan.UserID.ValueString() != "" {
+		return types.StringValue(fmt.Sprintf("user-%v", plan.UserID.ValueString()))
+	}
+	return types.StringValue(fmt.Sprintf("service-%v", plan.ServiceAccountID.ValueString()))
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (r *accessPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_access_policy", "Read")
 	// Get current state
 	var state accessPolicyResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -262,6 +733,14 @@ func (r *accessPolicyResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withOperationTimeout(ctx, "read", readTimeout)
+	defer cancel()
+
 	// If we imported this access policy both IDs will be empty.
 	if state.UserID.ValueString() == "" && state.ServiceAccountID.ValueString() == "" {
 		if strings.HasPrefix(state.ID.ValueString(), "user-") {
@@ -269,7 +748,9 @@ func (r *accessPolicyResource) Read(ctx context.Context, req resource.ReadReques
 		} else if strings.HasPrefix(state.ID.ValueString(), "service-") {
 			state.ServiceAccountID = types.StringValue(strings.TrimPrefix(state.ID.ValueString(), "service-"))
 		} else {
-			resp.Diagnostics.AddError(
+			AddError(
+				&resp.Diagnostics,
+				ErrorCodeInvalidConfig,
 				"Invalid ID prefix",
 				fmt.Sprintf("Expected either 'user-' or 'service-' as a prefix, got: %v", state.ID.ValueString()),
 			)
@@ -278,11 +759,14 @@ func (r *accessPolicyResource) Read(ctx context.Context, req resource.ReadReques
 	}
 
 	// Read existing policies
+	priorScopedRoles := state.ScopedRoles
 	_, err := r.GetFromTecton(ctx, &state)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to read Tecton roles", err.Error())
+		AddCommandError(&resp.Diagnostics, "Failed to read Tecton roles", err)
 		return
 	}
+	r.CheckPrincipalStatus(ctx, &state, &resp.Diagnostics)
+	CheckNewScopedRoles(priorScopedRoles, state.ScopedRoles, state.ID.ValueString(), &resp.Diagnostics)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -290,10 +774,13 @@ func (r *accessPolicyResource) Read(ctx context.Context, req resource.ReadReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	setAccessPolicyIdentity(ctx, resp.Identity, state.ID, &resp.Diagnostics)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *accessPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_access_policy", "Update")
 	// Retrieve values from plan
 	var plan accessPolicyResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -302,6 +789,14 @@ func (r *accessPolicyResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withOperationTimeout(ctx, "update", updateTimeout)
+	defer cancel()
+
 	// Also retrieve current state
 	var state accessPolicyResourceModel
 	diags = req.State.Get(ctx, &state)
@@ -310,30 +805,45 @@ func (r *accessPolicyResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
+	cli, changes := r.CLI.RecordingChanges()
+	policy := *r
+	policy.CLI = cli
+	defer r.Notifier.Notify(ctx, fmt.Sprintf("tecton_access_policy '%v'", state.ID.ValueString()), changes, &resp.Diagnostics)
+
 	// Refresh current state. We can't trust the Terraform state because a delete on a workspace
 	// may already have been applied, and that delete may have altered the existing role list.
-	_, err := r.GetFromTecton(ctx, &state)
+	_, err := policy.GetFromTecton(ctx, &state)
 	if err != nil {
-		resp.Diagnostics.AddError("Role Read Failure", err.Error())
+		AddCommandError(&resp.Diagnostics, "Role Read Failure", err)
 		return
 	}
 
-	err = r.UpdateAccessPolicy(ctx, &plan, &state)
+	policy.CheckAdminRevocation(ctx, &plan, &state, plan.SkipSafetyCheck, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.UnmanagedRoles = state.UnmanagedRoles
+
+	err = policy.UpdateAccessPolicy(ctx, &plan, &state)
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to update acess policy", err.Error())
+		AddCommandError(&resp.Diagnostics, "Unable to update acess policy", err)
 	}
 
 	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	policy.VerifyAccessPolicy(ctx, &plan, &resp.Diagnostics)
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	setAccessPolicyIdentity(ctx, resp.Identity, plan.ID, &resp.Diagnostics)
 }
 
 // Delete deletes the resource.
 func (r *accessPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_access_policy", "Delete")
 	// Get current state
 	var state accessPolicyResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -342,72 +852,99 @@ func (r *accessPolicyResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withOperationTimeout(ctx, "delete", deleteTimeout)
+	defer cancel()
+
+	if state.OnDestroy.ValueString() == accessPolicyOnDestroyRetain {
+		tflog.Info(ctx, fmt.Sprintf("Removing access policy '%v' from the state without revoking its roles, since `on_destroy` is \"retain\"", state.ID.ValueString()))
+		return
+	}
+
+	if !CheckDestroyAllowed(r.DisableDestroy, fmt.Sprintf("tecton_access_policy '%v'", state.ID.ValueString()), &resp.Diagnostics) {
+		return
+	}
+
 	// Refresh current state. We can't trust the Terraform state because a delete on a workspace
 	// may already have been applied, and that delete may have altered the existing role list.
 	_, err := r.GetFromTecton(ctx, &state)
 	if err != nil {
-		resp.Diagnostics.AddError("Role Read Failure", err.Error())
+		AddCommandError(&resp.Diagnostics, "Role Read Failure", err)
 		return
 	}
 
 	// Delete resource by updating to an empty plan
+	cli, changes := r.CLI.RecordingChanges()
+	policy := *r
+	policy.CLI = cli
+	defer r.Notifier.Notify(ctx, fmt.Sprintf("tecton_access_policy '%v'", state.ID.ValueString()), changes, &resp.Diagnostics)
 	var emptyPlan accessPolicyResourceModel
 	emptyPlan.UserID = state.UserID
 	emptyPlan.ServiceAccountID = state.ServiceAccountID
-	err = r.UpdateAccessPolicy(ctx, &emptyPlan, &state)
+	policy.CheckAdminRevocation(ctx, &emptyPlan, &state, state.SkipSafetyCheck, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	err = policy.UpdateAccessPolicy(ctx, &emptyPlan, &state)
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to delete acess policy", err.Error())
+		AddCommandError(&resp.Diagnostics, "Unable to delete acess policy", err)
 	}
 }
 
 func (r *accessPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("id"), req, resp)
+}
+
+// Sets the identity of an access policy, if the Terraform version supports resource identities.
+func setAccessPolicyIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, id types.String, diags *diag.Diagnostics) {
+	if identity == nil {
+		return
+	}
+	diags.Append(identity.Set(ctx, accessPolicyResourceIdentityModel{ID: id})...)
 }
 
 // Like Read but does not update Terraform's state. Returns true if a policy already exists in Tecton, or False otherwise.
 func (r *accessPolicyResource) GetFromTecton(ctx context.Context, state *accessPolicyResourceModel) (bool, error) {
 	// Read existing policies
-	var args = []string{"access-control", "get-roles", "--json-out"}
-	if state.UserID.ValueString() != "" {
-		args = append(args, "--user", state.UserID.ValueString())
-	} else if state.ServiceAccountID.ValueString() != "" {
-		args = append(args, "--service-account", state.ServiceAccountID.ValueString())
-	} else {
-		return false, errors.New("Cannot read from Tecton without an ID. This is a bug in the provider.")
-	}
-	var cmd = exec.Command("tecton", args...)
-	cmd.Env = r.CommandEnv
-	tflog.Info(ctx, fmt.Sprintf("Reading roles for '%v'", strings.Join(args[3:], " ")))
-
-	output, err := cmd.CombinedOutput()
+	policies, err := r.CLI.GetRoles(ctx, tectonclient.Principal{UserID: state.UserID.ValueString(), ServiceAccountID: state.ServiceAccountID.ValueString()})
 	if err != nil {
-		return false, fmt.Errorf(
-			"Command to read Tecton roles for '%v' failed.\nError: %v\nOutput: %v",
-			strings.Join(args[3:], " "),
-			err.Error(),
-			string(output),
-		)
-	}
-
-	// Parse the output
-	var policies []tectonGetRolesPolicy
-	err = json.Unmarshal(output, &policies)
-	if err != nil {
-		return false, fmt.Errorf("Failed to parse output of `tecton access-control get-roles`.\nGot: %v", output)
+		return false, err
 	}
 
 	// Clear fields
+	priorAllWorkspaces, priorWorkspaces := state.AllWorkspaces, state.Workspaces
 	state.Admin = types.BoolValue(false)
 	state.AllWorkspaces = nil
 	state.Workspaces = nil
 
-	// Map states to objects
+	// Map states to objects. Roles the provider doesn't know about are kept separately so that they
+	// are never granted or revoked.
+	unmanagedRoles := make(map[string][]attr.Value)
+	addUnmanagedRole := func(key string, role string) {
+		unmanagedRoles[key] = append(unmanagedRoles[key], types.StringValue(role))
+	}
+	var sources []attr.Value
+	scopedRoles := make(map[[2]string][]string)
 	for _, policy := range policies {
+		if policy.ResourceType != "ORGANIZATION" && policy.ResourceType != "WORKSPACE" {
+			key := [2]string{policy.ResourceType, policy.Resource()}
+			for _, roleGranted := range policy.RolesGranted {
+				scopedRoles[key] = append(scopedRoles[key], roleGranted.Role)
+			}
+			continue
+		}
 		for _, roleGranted := range policy.RolesGranted {
+			sources = append(sources, assignmentSources(policy, roleGranted)...)
 			if policy.ResourceType == "ORGANIZATION" {
 				if roleGranted.Role == "admin" {
 					state.Admin = types.BoolValue(true)
+				} else if !slices.Contains(validRoles, roleGranted.Role) {
+					addUnmanagedRole(unmanagedRolesOrganizationKey, roleGranted.Role)
 				} else {
 					if state.AllWorkspaces == nil {
 						state.AllWorkspaces = []types.String{}
@@ -415,6 +952,10 @@ func (r *accessPolicyResource) GetFromTecton(ctx context.Context, state *accessP
 					state.AllWorkspaces = append(state.AllWorkspaces, types.StringValue(roleGranted.Role))
 				}
 			} else if policy.ResourceType == "WORKSPACE" {
+				if !slices.Contains(validRoles, roleGranted.Role) {
+					addUnmanagedRole(policy.WorkspaceName, roleGranted.Role)
+					continue
+				}
 				if state.Workspaces == nil {
 					state.Workspaces = make(map[string][]types.String)
 				}
@@ -426,20 +967,49 @@ func (r *accessPolicyResource) GetFromTecton(ctx context.Context, state *accessP
 		}
 	}
 
-	// Sort the roles in order of increasing power
-	roleToLevel := make(map[string]int)
-	for i, role := range validRoles {
-		level := i
-		roleToLevel[role] = level
+	state.UnmanagedRoles = types.MapNull(types.ListType{ElemType: types.StringType})
+	if len(unmanagedRoles) > 0 {
+		elements := make(map[string]attr.Value)
+		for key, roles := range unmanagedRoles {
+			slices.SortFunc(roles, func(lhs attr.Value, rhs attr.Value) int {
+				return strings.Compare(lhs.String(), rhs.String())
+			})
+			elements[key] = types.ListValueMust(types.StringType, roles)
+		}
+		state.UnmanagedRoles = types.MapValueMust(types.ListType{ElemType: types.StringType}, elements)
 	}
-	cmp := func(lhs types.String, rhs types.String) int {
-		lhsLevel, lhsOk := roleToLevel[lhs.ValueString()]
-		rhsLevel, rhsOk := roleToLevel[rhs.ValueString()]
-		if !lhsOk || !rhsOk {
-			return 0
+
+	state.ScopedRoles = scopedRolesValue(scopedRoles)
+
+	slices.SortFunc(sources, func(lhs attr.Value, rhs attr.Value) int {
+		lhsAttributes, rhsAttributes := lhs.(types.Object).Attributes(), rhs.(types.Object).Attributes()
+		for _, key := range []string{"workspace", "role", "source", "group"} {
+			if c := strings.Compare(lhsAttributes[key].String(), rhsAttributes[key].String()); c != 0 {
+				return c
+			}
 		}
-		return lhsLevel - rhsLevel
+		return 0
+	})
+	state.AssignmentSources = types.ListValueMust(assignmentSourceType, append([]attr.Value{}, sources...))
+
+	// Admin can't be configured in an empty policy, so it's only in the state if it was granted
+	if state.EnforceEmpty.ValueBool() && !state.Admin.ValueBool() {
+		state.Admin = types.BoolNull()
 	}
+
+	roleOrder := r.RoleOrder
+	if roleOrder == nil {
+		roleOrder = validRoles
+	}
+	if state.SuppressImpliedRoles.ValueBool() {
+		state.AllWorkspaces = suppressImpliedRoles(state.AllWorkspaces, priorAllWorkspaces, roleOrder)
+		for ws, roles := range state.Workspaces {
+			state.Workspaces[ws] = suppressImpliedRoles(roles, priorWorkspaces[ws], roleOrder)
+		}
+	}
+
+	// Sort the roles in order of increasing power
+	cmp := compareRoles(roleOrder)
 	slices.SortFunc(state.AllWorkspaces, cmp)
 	for _, roles := range state.Workspaces {
 		slices.SortFunc(roles, cmp)
@@ -447,39 +1017,373 @@ func (r *accessPolicyResource) GetFromTecton(ctx context.Context, state *accessP
 	return len(policies) > 0, nil
 }
 
-// Modifies a role in Tecton for a particular user or service. If grant is true, the role will be added. If it is false, the role will be removed.
-// If no workspace is provided, the role will be applied to all workspaces.
-func (r *accessPolicyResource) ModifyRole(ctx context.Context, userID string, serviceAccountID string, role string, workspace string, grant bool) error {
-	var accessControlSubcommand string
-	if grant {
-		accessControlSubcommand = "assign-role"
-	} else {
-		accessControlSubcommand = "unassign-role"
+// Adds a warning to diags if the account of state was deactivated or deleted, since roles granted to
+// it are usually stale, or if its API key expires soon. Failing to read the status is only logged, so
+// that it never blocks a plan.
+func (r *accessPolicyResource) CheckPrincipalStatus(ctx context.Context, state *accessPolicyResourceModel, diags *diag.Diagnostics) {
+	principal := tectonclient.Principal{UserID: state.UserID.ValueString(), ServiceAccountID: state.ServiceAccountID.ValueString()}
+	status, err := r.CLI.GetPrincipalStatus(ctx, principal)
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Failed to check whether %v is active: %v", principal, err.Error()))
+		return
 	}
-	var args = []string{"access-control", accessControlSubcommand, "--role", role}
-	if workspace != "" {
-		args = append(args, "--workspace", workspace)
+	if status.KeyExpiresAt != nil && !status.Deactivated() {
+		CheckKeyExpiration(
+			fmt.Sprintf("The API key of the %v of access policy '%v'", principal, state.ID.ValueString()),
+			*status.KeyExpiresAt,
+			time.Now(),
+			r.KeyExpiryWarningWindow,
+			diags,
+		)
 	}
-	if userID != "" {
-		args = append(args, "--user", userID)
-	} else if serviceAccountID != "" {
-		args = append(args, "--service-account", serviceAccountID)
-	} else {
-		return errors.New("Cannot set role in Tecton without an ID. This is a bug in the provider.")
+	if !status.Deactivated() {
+		return
 	}
-	var cmd = exec.Command("tecton", args...)
-	cmd.Env = r.CommandEnv
-	tflog.Info(ctx, fmt.Sprintf("Running 'tecton %v'", strings.Join(args, " ")))
 
-	output, err := cmd.CombinedOutput()
+	reason := "no longer exists"
+	if status.Found {
+		reason = fmt.Sprintf("is deactivated (status: %v)", status.Status)
+	}
+	AddWarning(
+		diags,
+		ErrorCodePrincipalDeactivated,
+		"Access Policy Account Is Deactivated",
+		fmt.Sprintf(
+			"The %v of access policy '%v' %v, so its roles are likely stale. "+
+				"Consider removing the access policy to revoke them.",
+			principal,
+			state.ID.ValueString(),
+			reason,
+		),
+	)
+}
+
+// Returns the value of `scoped_roles` for roles keyed by resource type and resource.
+func scopedRolesValue(scopedRoles map[[2]string][]string) types.List {
+	var keys [][2]string
+	for key := range scopedRoles {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(lhs [2]string, rhs [2]string) int {
+		if c := strings.Compare(lhs[0], rhs[0]); c != 0 {
+			return c
+		}
+		return strings.Compare(lhs[1], rhs[1])
+	})
+	elements := []attr.Value{}
+	for _, key := range keys {
+		roles := scopedRoles[key]
+		slices.Sort(roles)
+		roleValues := make([]attr.Value, 0, len(roles))
+		for _, role := range roles {
+			roleValues = append(roleValues, types.StringValue(role))
+		}
+		elements = append(elements, types.ObjectValueMust(scopedRoleType.AttrTypes, map[string]attr.Value{
+			"resource_type": types.StringValue(key[0]),
+			"resource":      types.StringValue(key[1]),
+			"roles":         types.ListValueMust(types.StringType, roleValues),
+		}))
+	}
+	return types.ListValueMust(scopedRoleType, elements)
+}
+
+// Adds a warning to diags listing the elements of `scoped_roles` that aren't in the prior state, since
+// roles on resource types the provider doesn't understand are only reported, so the access policy
+// doesn't describe all of the account's access. Only new elements are listed, so that the warning
+// isn't repeated on every refresh.
+func CheckNewScopedRoles(prior types.List, current types.List, id string, diags *diag.Diagnostics) {
+	var added []string
+	for _, element := range current.Elements() {
+		if slices.ContainsFunc(prior.Elements(), element.Equal) {
+			continue
+		}
+		attributes := element.(types.Object).Attributes()
+		added = append(added, fmt.Sprintf(
+			"%v '%v': %v",
+			attributes["resource_type"].(types.String).ValueString(),
+			attributes["resource"].(types.String).ValueString(),
+			attributes["roles"],
+		))
+	}
+	if len(added) == 0 {
+		return
+	}
+	AddWarning(
+		diags,
+		ErrorCodeUnknownResourceType,
+		"Roles on unsupported resource types",
+		fmt.Sprintf(
+			"Tecton reported roles of '%v' on resource types the provider doesn't manage. They are reported in `scoped_roles`, "+
+				"but can't be configured, granted or revoked by this resource:\n%v",
+			id,
+			strings.Join(added, "\n"),
+		),
+	)
+}
+
+// Returns the elements of `assignment_sources` for a role in the output of `tecton access-control get-roles`.
+func assignmentSources(policy tectonclient.RolesPolicy, roleGranted tectonclient.RoleGranted) []attr.Value {
+	workspace := policy.WorkspaceName
+	if policy.ResourceType == "ORGANIZATION" {
+		workspace = unmanagedRolesOrganizationKey
+	} else if policy.ResourceType != "WORKSPACE" {
+		return nil
+	}
+	var sources []attr.Value
+	for _, source := range roleGranted.AssignmentSources {
+		kind := source.AssignmentType
+		group := types.StringNull()
+		if source.IsDirect() {
+			kind = "DIRECT"
+		} else if source.IsGroup() {
+			kind = "GROUP"
+			group = types.StringValue(source.PrincipalGroupName)
+		}
+		sources = append(sources, types.ObjectValueMust(assignmentSourceType.AttrTypes, map[string]attr.Value{
+			"workspace": types.StringValue(workspace),
+			"role":      types.StringValue(roleGranted.Role),
+			"source":    types.StringValue(kind),
+			"group":     group,
+		}))
+	}
+	return sources
+}
+
+// Re-reads the roles of plan's account after they were changed and checks that they match plan, since
+// a `tecton access-control` command may fail silently. On a mismatch, the roles in plan are replaced
+// with the ones in Tecton so that the saved state is accurate, and an error describing the mismatch
+// is added to diags unless it already has an error, which would explain the mismatch. Also refreshes
+// `assignment_sources`, which change with the roles. A failure to read the roles is only a warning,
+// since the roles have already been changed. Nothing is verified in dry-run mode, since the roles
+// weren't changed.
+func (r *accessPolicyResource) VerifyAccessPolicy(ctx context.Context, plan *accessPolicyResourceModel, diags *diag.Diagnostics) {
+	if r.CLI.DryRun {
+		plan.AssignmentSources = types.ListValueMust(assignmentSourceType, []attr.Value{})
+		plan.ScopedRoles = types.ListValueMust(scopedRoleType, []attr.Value{})
+		return
+	}
+	actual := *plan
+	_, err := r.GetFromTecton(ctx, &actual)
 	if err != nil {
-		return fmt.Errorf(
-			"Command to set Tecton role failed.\nError: %v\nOutput: %v",
-			err.Error(),
-			string(output),
+		AddWarning(
+			diags,
+			ClassifyError(err),
+			"Failed to verify access policy",
+			fmt.Sprintf(
+				"The roles of '%v' were updated, but couldn't be read back to verify them, so `assignment_sources` and `scoped_roles` are empty until the next refresh.\nError: %v",
+				accessPolicyID(plan).ValueString(),
+				err.Error(),
+			),
 		)
+		plan.AssignmentSources = types.ListValueMust(assignmentSourceType, []attr.Value{})
+		plan.ScopedRoles = types.ListValueMust(scopedRoleType, []attr.Value{})
+		return
 	}
-	return nil
+	plan.AssignmentSources = actual.AssignmentSources
+	plan.ScopedRoles = actual.ScopedRoles
+
+	mismatches := accessPolicyMismatches(plan, directAccessPolicyRoles(&actual))
+	if len(mismatches) == 0 {
+		return
+	}
+	tflog.Warn(ctx, fmt.Sprintf("The roles of '%v' in Tecton don't match the plan: %v", accessPolicyID(plan).ValueString(), strings.Join(mismatches, "; ")))
+	plan.Admin = actual.Admin
+	plan.AllWorkspaces = actual.AllWorkspaces
+	plan.Workspaces = actual.Workspaces
+	if diags.HasError() {
+		return
+	}
+	AddError(
+		diags,
+		ErrorCodeCommandFailed,
+		"Access Policy Verification Failed",
+		fmt.Sprintf(
+			"The roles of '%v' were updated without errors, but the roles read back from Tecton don't match the plan:\n- %v\n"+
+				"The roles in Tecton were saved to the state, so the next plan shows the remaining changes.",
+			accessPolicyID(plan).ValueString(),
+			strings.Join(mismatches, "\n- "),
+		),
+	)
+}
+
+// Returns a copy of actual without the roles that are only granted through a principal group,
+// according to its `assignment_sources`, since the resource only grants and revokes direct roles.
+// Roles without assignment sources are direct, like in `RoleGranted.IsDirect`.
+func directAccessPolicyRoles(actual *accessPolicyResourceModel) *accessPolicyResourceModel {
+	direct := make(map[[2]string]bool)
+	for _, element := range actual.AssignmentSources.Elements() {
+		attributes := element.(types.Object).Attributes()
+		key := [2]string{attributes["workspace"].(types.String).ValueString(), attributes["role"].(types.String).ValueString()}
+		direct[key] = direct[key] || attributes["source"].(types.String).ValueString() == "DIRECT"
+	}
+	isDirect := func(workspace string, role types.String) bool {
+		isDirect, ok := direct[[2]string{workspace, role.ValueString()}]
+		return !ok || isDirect
+	}
+	filter := func(workspace string, roles []types.String) []types.String {
+		var filtered []types.String
+		for _, role := range roles {
+			if isDirect(workspace, role) {
+				filtered = append(filtered, role)
+			}
+		}
+		return filtered
+	}
+
+	result := *actual
+	if actual.Admin.ValueBool() && !isDirect(unmanagedRolesOrganizationKey, types.StringValue("admin")) {
+		result.Admin = types.BoolValue(false)
+	}
+	result.AllWorkspaces = filter(unmanagedRolesOrganizationKey, actual.AllWorkspaces)
+	result.Workspaces = nil
+	for ws, roles := range actual.Workspaces {
+		if filtered := filter(ws, roles); len(filtered) > 0 {
+			if result.Workspaces == nil {
+				result.Workspaces = make(map[string][]types.String)
+			}
+			result.Workspaces[ws] = filtered
+		}
+	}
+	return &result
+}
+
+// Returns a description of each difference between the roles in plan and the roles in actual.
+func accessPolicyMismatches(plan *accessPolicyResourceModel, actual *accessPolicyResourceModel) []string {
+	var mismatches []string
+	if plan.Admin.ValueBool() != actual.Admin.ValueBool() {
+		mismatches = append(mismatches, fmt.Sprintf("admin is %v instead of %v", actual.Admin.ValueBool(), plan.Admin.ValueBool()))
+	}
+	describe := func(name string, planRoles []types.String, actualRoles []types.String) {
+		if missing := SliceDifference(planRoles, actualRoles); len(missing) > 0 {
+			mismatches = append(mismatches, fmt.Sprintf("%v is missing %v", name, strings.Join(missing, ", ")))
+		}
+		if unexpected := SliceDifference(actualRoles, planRoles); len(unexpected) > 0 {
+			mismatches = append(mismatches, fmt.Sprintf("%v unexpectedly has %v", name, strings.Join(unexpected, ", ")))
+		}
+	}
+	describe("all_workspaces", plan.AllWorkspaces, actual.AllWorkspaces)
+	var workspaces []string
+	for ws := range plan.Workspaces {
+		workspaces = append(workspaces, ws)
+	}
+	for ws := range actual.Workspaces {
+		if _, ok := plan.Workspaces[ws]; !ok {
+			workspaces = append(workspaces, ws)
+		}
+	}
+	slices.Sort(workspaces)
+	for _, ws := range workspaces {
+		describe(fmt.Sprintf("workspace '%v'", ws), plan.Workspaces[ws], actual.Workspaces[ws])
+	}
+	return mismatches
+}
+
+// Returns a function that orders roles by their position in roleOrder. Roles that aren't in
+// roleOrder come after the ones that are, in alphabetical order, so that the order is always
+// deterministic.
+func compareRoles(roleOrder []string) func(lhs types.String, rhs types.String) int {
+	roleToLevel := make(map[string]int)
+	for i, role := range roleOrder {
+		roleToLevel[role] = i
+	}
+	return func(lhs types.String, rhs types.String) int {
+		lhsLevel, lhsOk := roleToLevel[lhs.ValueString()]
+		rhsLevel, rhsOk := roleToLevel[rhs.ValueString()]
+		switch {
+		case lhsOk && rhsOk:
+			return lhsLevel - rhsLevel
+		case lhsOk:
+			return -1
+		case rhsOk:
+			return 1
+		default:
+			return strings.Compare(lhs.ValueString(), rhs.ValueString())
+		}
+	}
+}
+
+// Removes the roles that are implied by a more powerful role in roles, unless they are in prior, i.e.
+// they were granted explicitly. roleOrder lists the roles in order of increasing power.
+func suppressImpliedRoles(roles []types.String, prior []types.String, roleOrder []string) []types.String {
+	highest := -1
+	for _, role := range roles {
+		highest = max(highest, slices.Index(roleOrder, role.ValueString()))
+	}
+	var kept []types.String
+	for _, role := range roles {
+		level := slices.Index(roleOrder, role.ValueString())
+		if level >= 0 && level < highest && !slices.Contains(prior, role) {
+			continue
+		}
+		kept = append(kept, role)
+	}
+	return kept
+}
+
+// Refuses to revoke admin from the last admin on the cluster, since that would lock everyone out of
+// it. Adds an error to diags if plan revokes admin from the only remaining admin, unless
+// skipSafetyCheck is true.
+func (r *accessPolicyResource) CheckAdminRevocation(
+	ctx context.Context,
+	plan *accessPolicyResourceModel,
+	state *accessPolicyResourceModel,
+	skipSafetyCheck types.Bool,
+	diags *diag.Diagnostics,
+) {
+	if !state.Admin.ValueBool() || plan.Admin.ValueBool() || skipSafetyCheck.ValueBool() {
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Checking that admin can be revoked from '%v'", state.ID.ValueString()))
+	admins, err := ListAdmins(ctx, r.CLI)
+	if err != nil {
+		AddError(
+			diags,
+			ClassifyError(err),
+			"Failed to check Tecton admins",
+			fmt.Sprintf(
+				"Failed to check whether another account is an admin before revoking admin from '%v'. Set `skip_safety_check = true` to skip this check.\nError: %v",
+				state.ID.ValueString(),
+				err.Error(),
+			),
+		)
+		return
+	}
+
+	self := tectonclient.Principal{UserID: state.UserID.ValueString(), ServiceAccountID: state.ServiceAccountID.ValueString()}
+	otherAdmins := 0
+	for _, admin := range admins {
+		if admin != self {
+			otherAdmins++
+		}
+	}
+	if otherAdmins == 0 {
+		AddError(
+			diags,
+			ErrorCodeUnsafeOperation,
+			"Cannot Revoke Last Admin",
+			fmt.Sprintf(
+				"Refusing to revoke admin from '%v' since it is the last admin on the cluster, and nobody (including Terraform) "+
+					"would be able to manage the cluster afterwards. Grant admin to another account first, or set "+
+					"`skip_safety_check = true` and apply before revoking to revoke it anyway.",
+				state.ID.ValueString(),
+			),
+		)
+	}
+}
+
+// Lists the users and service accounts with the admin role on the cluster.
+func ListAdmins(ctx context.Context, cli tectonclient.Client) ([]tectonclient.Principal, error) {
+	output, err := cli.RunScript(ctx, "list_admins.py")
+	if err != nil {
+		return nil, err
+	}
+	var admins principalList
+	err = json.Unmarshal(output, &admins)
+	if err != nil {
+		return nil, &diagnostics.ParseError{Source: "list_admins.py", Output: string(output), Err: err}
+	}
+	return admins.Principals(), nil
 }
 
 // Returns elements that are in a that are not in b.
@@ -516,13 +1420,13 @@ func (r *accessPolicyResource) UpdateWorkspace(
 	// the user would have no permissions at all, which violates our requirements. Granting N
 	// before revoking O guarantees the requirements are met.
 	for _, role := range rolesToBeAdded {
-		err := r.ModifyRole(ctx, userID, serviceAccountID, role, workspace, true)
+		err := r.CLI.AssignRole(ctx, tectonclient.Principal{UserID: userID, ServiceAccountID: serviceAccountID}, role, workspace)
 		if err != nil {
 			return err
 		}
 	}
 	for _, role := range rolesToBeDeleted {
-		err := r.ModifyRole(ctx, userID, serviceAccountID, role, workspace, false)
+		err := r.CLI.UnassignRole(ctx, tectonclient.Principal{UserID: userID, ServiceAccountID: serviceAccountID}, role, workspace)
 		if err != nil {
 			return err
 		}
@@ -536,9 +1440,9 @@ func (r *accessPolicyResource) UpdateAccessPolicy(
 	plan *accessPolicyResourceModel,
 	state *accessPolicyResourceModel,
 ) error {
-	// Handle admin
-	if plan.Admin != state.Admin {
-		err := r.ModifyRole(ctx, plan.UserID.ValueString(), plan.ServiceAccountID.ValueString(), "admin", "", plan.Admin.ValueBool())
+	// Handle admin. A null admin is the same as false.
+	if plan.Admin.ValueBool() != state.Admin.ValueBool() {
+		err := r.CLI.ModifyRole(ctx, tectonclient.Principal{UserID: plan.UserID.ValueString(), ServiceAccountID: plan.ServiceAccountID.ValueString()}, "admin", "", plan.Admin.ValueBool())
 		if err != nil {
 			return err
 		}
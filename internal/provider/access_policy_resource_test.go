@@ -1,10 +1,23 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
 )
 
 func TestAccAccessPolicyResource_validation(t *testing.T) {
@@ -275,3 +288,779 @@ resource "tecton_access_policy" "no_existing_roles" {
 		CheckDestroy: nil,
 	})
 }
+
+// Builds a plan for the access policy resource from the given attribute values. Attributes that
+// aren't provided are null.
+func accessPolicyPlan(t *testing.T, values map[string]tftypes.Value) tfsdk.Plan {
+	t.Helper()
+	return resourcePlan(t, NewAccessPolicyResource(), values)
+}
+
+var workspacesType = tftypes.Map{ElementType: tftypes.List{ElementType: tftypes.String}}
+
+func TestAccessPolicyResourceModifyPlan_deferral(t *testing.T) {
+	testCases := map[string]struct {
+		workspaces      tftypes.Value
+		deferralAllowed bool
+		expectDeferred  bool
+	}{
+		"unknown workspaces": {
+			workspaces:      tftypes.NewValue(workspacesType, tftypes.UnknownValue),
+			deferralAllowed: true,
+			expectDeferred:  true,
+		},
+		"unknown workspaces without deferral": {
+			workspaces:      tftypes.NewValue(workspacesType, tftypes.UnknownValue),
+			deferralAllowed: false,
+			expectDeferred:  false,
+		},
+		"unknown roles": {
+			workspaces: tftypes.NewValue(workspacesType, map[string]tftypes.Value{
+				"prod": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+					tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				}),
+			}),
+			deferralAllowed: true,
+			expectDeferred:  true,
+		},
+		"known workspaces": {
+			workspaces: tftypes.NewValue(workspacesType, map[string]tftypes.Value{
+				"prod": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+					tftypes.NewValue(tftypes.String, "viewer"),
+				}),
+			}),
+			deferralAllowed: true,
+			expectDeferred:  false,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			plan := accessPolicyPlan(t, map[string]tftypes.Value{
+				"user_id":    tftypes.NewValue(tftypes.String, "alice"),
+				"workspaces": testCase.workspaces,
+			})
+			req := fwresource.ModifyPlanRequest{
+				Plan:               plan,
+				ClientCapabilities: fwresource.ModifyPlanClientCapabilities{DeferralAllowed: testCase.deferralAllowed},
+			}
+			resp := fwresource.ModifyPlanResponse{Plan: plan}
+			NewAccessPolicyResource().(*accessPolicyResource).ModifyPlan(context.Background(), req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			if (resp.Deferred != nil) != testCase.expectDeferred {
+				t.Errorf("expected deferred to be %v, got %v", testCase.expectDeferred, resp.Deferred)
+			}
+		})
+	}
+}
+
+func TestAccessPolicyResourceModifyPlan_unknownWithoutDeferral(t *testing.T) {
+	plan := accessPolicyPlan(t, map[string]tftypes.Value{
+		"id":           tftypes.NewValue(tftypes.String, "user-alice"),
+		"last_updated": tftypes.NewValue(tftypes.String, "Monday, 02-Jan-06 15:04:05 MST"),
+		"user_id":      tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"workspaces":   tftypes.NewValue(workspacesType, tftypes.UnknownValue),
+	})
+	req := fwresource.ModifyPlanRequest{Plan: plan}
+	resp := fwresource.ModifyPlanResponse{Plan: plan}
+	NewAccessPolicyResource().(*accessPolicyResource).ModifyPlan(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+	if resp.Deferred != nil {
+		t.Fatalf("expected no deferral, got %v", resp.Deferred)
+	}
+
+	for _, attribute := range []string{"id", "last_updated"} {
+		value, _, err := tftypes.WalkAttributePath(resp.Plan.Raw, tftypes.NewAttributePath().WithAttributeName(attribute))
+		if err != nil {
+			t.Fatalf("failed to read '%v': %v", attribute, err)
+		}
+		if value.(tftypes.Value).IsKnown() {
+			t.Errorf("expected '%v' to be unknown, got %v", attribute, value)
+		}
+	}
+}
+
+func TestAccessPolicyResourceCheckAdminRevocation(t *testing.T) {
+	ctx := context.Background()
+	admin := accessPolicyResourceModel{
+		ID:     types.StringValue("user-alice@example.com"),
+		UserID: types.StringValue("alice@example.com"),
+		Admin:  types.BoolValue(true),
+	}
+	notAdmin := accessPolicyResourceModel{
+		ID:     admin.ID,
+		UserID: admin.UserID,
+		Admin:  types.BoolValue(false),
+	}
+
+	testCases := map[string]struct {
+		admins          string
+		plan            accessPolicyResourceModel
+		skipSafetyCheck bool
+		isError         bool
+	}{
+		"last admin": {
+			admins:  `{"users": ["alice@example.com"], "service_accounts": []}`,
+			plan:    notAdmin,
+			isError: true,
+		},
+		"other admin": {
+			admins: `{"users": ["alice@example.com"], "service_accounts": ["abc123"]}`,
+			plan:   notAdmin,
+		},
+		"skip safety check": {
+			admins:          `{"users": ["alice@example.com"], "service_accounts": []}`,
+			plan:            notAdmin,
+			skipSafetyCheck: true,
+		},
+		"admin kept": {
+			admins: `{"users": ["alice@example.com"], "service_accounts": []}`,
+			plan:   admin,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fakeTectonPythonCLI(t, testCase.admins, "")
+			r := &accessPolicyResource{}
+			var diags diag.Diagnostics
+			r.CheckAdminRevocation(ctx, &testCase.plan, &admin, types.BoolValue(testCase.skipSafetyCheck), &diags)
+			if diags.HasError() != testCase.isError {
+				t.Fatalf("expected error: %v, got: %v", testCase.isError, diags)
+			}
+		})
+	}
+}
+
+func TestAccessPolicyResourceModifyPlan_lastAdmin(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"users": ["alice@example.com"], "service_accounts": []}`, "")
+	state := accessPolicyPlan(t, map[string]tftypes.Value{
+		"id":      tftypes.NewValue(tftypes.String, "user-alice@example.com"),
+		"user_id": tftypes.NewValue(tftypes.String, "alice@example.com"),
+		"admin":   tftypes.NewValue(tftypes.Bool, true),
+	})
+	plan := accessPolicyPlan(t, map[string]tftypes.Value{
+		"id":      tftypes.NewValue(tftypes.String, "user-alice@example.com"),
+		"user_id": tftypes.NewValue(tftypes.String, "alice@example.com"),
+		"admin":   tftypes.NewValue(tftypes.Bool, false),
+	})
+	r := &accessPolicyResource{}
+
+	resp := fwresource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(context.Background(), fwresource.ModifyPlanRequest{
+		State: tfsdk.State{Schema: state.Schema, Raw: state.Raw},
+		Plan:  plan,
+	}, &resp)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Cannot Revoke Last Admin" {
+		t.Fatalf("expected revoking admin from the last admin to fail at plan time, got: %v", resp.Diagnostics)
+	}
+}
+
+func TestAccessPolicyResourceCheckMinWorkspaceOwners(t *testing.T) {
+	ctx := context.Background()
+	owner := []types.String{types.StringValue("owner")}
+	state := accessPolicyResourceModel{
+		ID:         types.StringValue("user-alice@example.com"),
+		UserID:     types.StringValue("alice@example.com"),
+		Workspaces: map[string][]types.String{"prod": owner},
+	}
+	allWorkspacesState := accessPolicyResourceModel{
+		ID:            state.ID,
+		UserID:        state.UserID,
+		AllWorkspaces: owner,
+	}
+	viewer := accessPolicyResourceModel{
+		ID:         state.ID,
+		UserID:     state.UserID,
+		Workspaces: map[string][]types.String{"prod": {types.StringValue("viewer")}},
+	}
+
+	testCases := map[string]struct {
+		owners             string
+		minWorkspaceOwners int64
+		plan               accessPolicyResourceModel
+		state              accessPolicyResourceModel
+		isError            bool
+	}{
+		"too few owners": {
+			owners:             `{"prod": {"users": ["alice@example.com", "bob@example.com"], "service_accounts": []}}`,
+			minWorkspaceOwners: 2,
+			plan:               viewer,
+			state:              state,
+			isError:            true,
+		},
+		"enough owners": {
+			owners:             `{"prod": {"users": ["alice@example.com", "bob@example.com"], "service_accounts": ["abc123"]}}`,
+			minWorkspaceOwners: 2,
+			plan:               viewer,
+			state:              state,
+		},
+		"owner through all workspaces": {
+			owners:             `{"prod": {"users": ["alice@example.com"], "service_accounts": []}}`,
+			minWorkspaceOwners: 1,
+			plan:               accessPolicyResourceModel{},
+			state:              allWorkspacesState,
+			isError:            true,
+		},
+		"owner kept": {
+			owners:             `{"prod": {"users": ["alice@example.com"], "service_accounts": []}}`,
+			minWorkspaceOwners: 1,
+			plan:               state,
+			state:              state,
+		},
+		"no policy": {
+			owners: `{"prod": {"users": ["alice@example.com"], "service_accounts": []}}`,
+			plan:   viewer,
+			state:  state,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fakeTectonPythonCLI(t, testCase.owners, "")
+			r := &accessPolicyResource{
+				WorkspaceData:      NewWorkspaceCache(tectonclient.Workspaces{Lives: []string{"prod"}}),
+				MinWorkspaceOwners: testCase.minWorkspaceOwners,
+			}
+			var diags diag.Diagnostics
+			r.CheckMinWorkspaceOwners(ctx, &testCase.plan, &testCase.state, &diags)
+			if diags.HasError() != testCase.isError {
+				t.Fatalf("expected error: %v, got: %v", testCase.isError, diags)
+			}
+		})
+	}
+}
+
+func TestAccessPolicyResourceGetFromTecton_unmanagedRoles(t *testing.T) {
+	fakeTectonPythonCLI(t, "", `[
+		{"resource_type": "ORGANIZATION", "roles_granted": [{"role": "admin"}, {"role": "viewer"}, {"role": "auditor"}]},
+		{"resource_type": "WORKSPACE", "workspace_name": "prod", "roles_granted": [{"role": "owner"}, {"role": "model_deployer"}]}
+	]`)
+	r := &accessPolicyResource{}
+	state := accessPolicyResourceModel{UserID: types.StringValue("alice@example.com")}
+	exists, err := r.GetFromTecton(context.Background(), &state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !exists {
+		t.Fatal("expected the access policy to exist")
+	}
+
+	if !state.Admin.ValueBool() {
+		t.Error("expected admin to be true")
+	}
+	if len(state.AllWorkspaces) != 1 || state.AllWorkspaces[0].ValueString() != "viewer" {
+		t.Errorf("unexpected all_workspaces: %v", state.AllWorkspaces)
+	}
+	if len(state.Workspaces["prod"]) != 1 || state.Workspaces["prod"][0].ValueString() != "owner" {
+		t.Errorf("unexpected workspaces: %v", state.Workspaces)
+	}
+	expected := `{"*":["auditor"],"prod":["model_deployer"]}`
+	if state.UnmanagedRoles.String() != expected {
+		t.Errorf("expected unmanaged_roles %v, got %v", expected, state.UnmanagedRoles)
+	}
+}
+
+func TestAccessPolicyResourceCreate_partialState(t *testing.T) {
+	// Granting viewer succeeds and is visible to get-roles afterwards, but the first grant of owner fails
+	dir := t.TempDir()
+	roles, calls, failed := filepath.Join(dir, "roles.json"), filepath.Join(dir, "calls"), filepath.Join(dir, "failed")
+	err := os.WriteFile(roles, []byte("[]"), 0o644)
+	if err != nil {
+		t.Fatalf("failed to write roles: %v", err)
+	}
+	fakeTectonCLI(t, `case "$2 $4" in
+  "get-roles "*) cat `+roles+` ;;
+  "assign-role viewer")
+    echo "$@" >> `+calls+`
+    echo '[{"resource_type": "WORKSPACE", "workspace_name": "prod", "roles_granted": [{"role": "viewer"}]}]' > `+roles+` ;;
+  "assign-role owner")
+    if [ ! -f `+failed+` ]; then touch `+failed+`; echo "PERMISSION_DENIED: caller is not an admin"; exit 1; fi
+    echo "$@" >> `+calls+`
+    echo '[{"resource_type": "WORKSPACE", "workspace_name": "prod", "roles_granted": [{"role": "viewer"}, {"role": "owner"}]}]' > `+roles+` ;;
+  *) echo "$@" >> `+calls+` ;;
+esac`)
+
+	ctx := context.Background()
+	r := NewAccessPolicyResource()
+	plan := accessPolicyPlan(t, map[string]tftypes.Value{
+		"user_id": tftypes.NewValue(tftypes.String, "alice@example.com"),
+		"workspaces": tftypes.NewValue(workspacesType, map[string]tftypes.Value{
+			"prod": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+				tftypes.NewValue(tftypes.String, "viewer"),
+				tftypes.NewValue(tftypes.String, "owner"),
+			}),
+		}),
+	})
+	createResp := fwresource.CreateResponse{
+		State: tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)},
+	}
+	r.Create(ctx, fwresource.CreateRequest{Plan: plan}, &createResp)
+	// An error would taint the access policy, and replacing it would revoke viewer again
+	if createResp.Diagnostics.HasError() || createResp.Diagnostics.WarningsCount() != 1 {
+		t.Fatalf("expected only a warning, got: %v", createResp.Diagnostics)
+	}
+	var state accessPolicyResourceModel
+	createResp.Diagnostics.Append(createResp.State.Get(ctx, &state)...)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("failed to read state: %v", createResp.Diagnostics)
+	}
+	if state.ID.ValueString() != "user-alice@example.com" {
+		t.Errorf("expected id 'user-alice@example.com', got '%v'", state.ID.ValueString())
+	}
+
+	// The next refresh finds the missing grant, which the next apply makes in place
+	readResp := fwresource.ReadResponse{State: createResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: createResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", readResp.Diagnostics)
+	}
+	readResp.Diagnostics.Append(readResp.State.Get(ctx, &state)...)
+	if len(state.Workspaces["prod"]) != 1 || state.Workspaces["prod"][0].ValueString() != "viewer" {
+		t.Errorf("expected the refresh to find only the granted role, got: %v", state.Workspaces)
+	}
+	os.Remove(calls)
+	updateResp := fwresource.UpdateResponse{State: readResp.State}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: plan, State: readResp.State}, &updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", updateResp.Diagnostics)
+	}
+	output, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatalf("failed to read calls: %v", err)
+	}
+	expected := "access-control assign-role --role owner --workspace prod --user alice@example.com\n"
+	if string(output) != expected {
+		t.Errorf("expected only the missing grant:\n%v\ngot:\n%v", expected, string(output))
+	}
+}
+
+func TestAccessPolicyResourceCreate_adoptExisting(t *testing.T) {
+	calls := filepath.Join(t.TempDir(), "calls")
+	// get-roles reflects the changes once they're made, so that they can be verified
+	fakeTectonCLI(t, `case "$2" in
+  get-roles)
+    if [ -f `+calls+` ]; then
+      echo '[{"resource_type": "WORKSPACE", "workspace_name": "prod", "roles_granted": [{"role": "viewer"}, {"role": "owner"}]}]'
+    else
+      echo '[{"resource_type": "WORKSPACE", "workspace_name": "prod", "roles_granted": [{"role": "viewer"}, {"role": "editor"}]}]'
+    fi ;;
+  *) echo "$@" >> `+calls+` ;;
+esac`)
+
+	testCases := map[string]struct {
+		adoptExisting bool
+		isError       bool
+		expectedCalls string
+	}{
+		"adopt": {
+			adoptExisting: true,
+			expectedCalls: "access-control assign-role --role owner --workspace prod --user alice@example.com\n" +
+				"access-control unassign-role --role editor --workspace prod --user alice@example.com\n",
+		},
+		"already exists": {
+			adoptExisting: false,
+			isError:       true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			os.Remove(calls)
+			plan := accessPolicyPlan(t, map[string]tftypes.Value{
+				"user_id":        tftypes.NewValue(tftypes.String, "alice@example.com"),
+				"admin":          tftypes.NewValue(tftypes.Bool, false),
+				"adopt_existing": tftypes.NewValue(tftypes.Bool, testCase.adoptExisting),
+				"workspaces": tftypes.NewValue(workspacesType, map[string]tftypes.Value{
+					"prod": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+						tftypes.NewValue(tftypes.String, "viewer"),
+						tftypes.NewValue(tftypes.String, "owner"),
+					}),
+				}),
+			})
+			req := fwresource.CreateRequest{Plan: plan}
+			resp := fwresource.CreateResponse{
+				State: tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)},
+			}
+			NewAccessPolicyResource().Create(context.Background(), req, &resp)
+			if resp.Diagnostics.HasError() != testCase.isError {
+				t.Fatalf("expected error: %v, got: %v", testCase.isError, resp.Diagnostics)
+			}
+			data, _ := os.ReadFile(calls)
+			if string(data) != testCase.expectedCalls {
+				t.Errorf("expected calls:\n%v\ngot:\n%v", testCase.expectedCalls, string(data))
+			}
+		})
+	}
+}
+
+func TestAccessPolicyResourceCreate_enforceEmpty(t *testing.T) {
+	calls := filepath.Join(t.TempDir(), "calls")
+	fakeTectonCLI(t, `case "$2" in
+  get-roles)
+    if [ -f `+calls+` ]; then
+      echo '[{"resource_type": "WORKSPACE", "workspace_name": "prod", "roles_granted": [{"role": "model_deployer"}]}]'
+    else
+      echo '[{"resource_type": "WORKSPACE", "workspace_name": "prod", "roles_granted": [{"role": "viewer"}, {"role": "model_deployer"}]}]'
+    fi ;;
+  *) echo "$@" >> `+calls+` ;;
+esac`)
+
+	plan := accessPolicyPlan(t, map[string]tftypes.Value{
+		"user_id":       tftypes.NewValue(tftypes.String, "alice@example.com"),
+		"enforce_empty": tftypes.NewValue(tftypes.Bool, true),
+	})
+	req := fwresource.CreateRequest{Plan: plan}
+	resp := fwresource.CreateResponse{
+		State: tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)},
+	}
+	NewAccessPolicyResource().Create(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+	data, _ := os.ReadFile(calls)
+	expected := "access-control unassign-role --role viewer --workspace prod --user alice@example.com\n"
+	if string(data) != expected {
+		t.Errorf("expected calls:\n%v\ngot:\n%v", expected, string(data))
+	}
+
+	// Only the unmanaged role is left, which doesn't cause a diff
+	fakeTectonCLI(t, `echo '[{"resource_type": "WORKSPACE", "workspace_name": "prod", "roles_granted": [{"role": "model_deployer"}]}]'`)
+	state := accessPolicyResourceModel{UserID: types.StringValue("alice@example.com"), EnforceEmpty: types.BoolValue(true)}
+	_, err := NewAccessPolicyResource().(*accessPolicyResource).GetFromTecton(context.Background(), &state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !state.Admin.IsNull() || state.AllWorkspaces != nil || state.Workspaces != nil {
+		t.Errorf("expected no roles, got admin: %v, all_workspaces: %v, workspaces: %v", state.Admin, state.AllWorkspaces, state.Workspaces)
+	}
+}
+
+func TestAccessPolicyResourceUpdate_verification(t *testing.T) {
+	// Granting owner "succeeds", but get-roles never shows it
+	fakeTectonCLI(t, `case "$2" in
+  get-roles) echo '[{"resource_type": "WORKSPACE", "workspace_name": "prod", "roles_granted": [{"role": "viewer"}]}]' ;;
+esac`)
+
+	prod := func(roles ...string) tftypes.Value {
+		var values []tftypes.Value
+		for _, role := range roles {
+			values = append(values, tftypes.NewValue(tftypes.String, role))
+		}
+		return tftypes.NewValue(workspacesType, map[string]tftypes.Value{
+			"prod": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, values),
+		})
+	}
+	plan := accessPolicyPlan(t, map[string]tftypes.Value{
+		"id":         tftypes.NewValue(tftypes.String, "user-alice@example.com"),
+		"user_id":    tftypes.NewValue(tftypes.String, "alice@example.com"),
+		"workspaces": prod("viewer", "owner"),
+	})
+	priorState := accessPolicyPlan(t, map[string]tftypes.Value{
+		"id":         tftypes.NewValue(tftypes.String, "user-alice@example.com"),
+		"user_id":    tftypes.NewValue(tftypes.String, "alice@example.com"),
+		"workspaces": prod("viewer"),
+	})
+	req := fwresource.UpdateRequest{Plan: plan, State: tfsdk.State{Schema: priorState.Schema, Raw: priorState.Raw}}
+	resp := fwresource.UpdateResponse{
+		State: tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)},
+	}
+	NewAccessPolicyResource().Update(context.Background(), req, &resp)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "workspace 'prod' is missing owner") {
+		t.Fatalf("expected a verification error, got: %v", resp.Diagnostics)
+	}
+
+	var state accessPolicyResourceModel
+	resp.Diagnostics = nil
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("failed to read state: %v", resp.Diagnostics)
+	}
+	if len(state.Workspaces["prod"]) != 1 {
+		t.Errorf("expected the roles in Tecton to be saved, got: %v", state.Workspaces)
+	}
+}
+
+func TestAccessPolicyResourceUpdate_verificationIgnoresGroupRoles(t *testing.T) {
+	// A group grants editor on prod, which the plan doesn't have, since it isn't direct
+	fakeTectonCLI(t, `case "$2" in
+  get-roles) echo '[
+    {"resource_type": "WORKSPACE", "workspace_name": "prod", "roles_granted": [
+      {"role": "viewer", "assignment_sources": [{"assignment_type": "DIRECT"}]},
+      {"role": "owner", "assignment_sources": [{"assignment_type": "DIRECT"}, {"assignment_type": "PRINCIPAL_GROUP", "principal_group_name": "data-team"}]},
+      {"role": "editor", "assignment_sources": [{"assignment_type": "PRINCIPAL_GROUP", "principal_group_name": "data-team"}]}
+    ]}
+  ]' ;;
+esac`)
+
+	prod := func(roles ...string) tftypes.Value {
+		var values []tftypes.Value
+		for _, role := range roles {
+			values = append(values, tftypes.NewValue(tftypes.String, role))
+		}
+		return tftypes.NewValue(workspacesType, map[string]tftypes.Value{
+			"prod": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, values),
+		})
+	}
+	plan := accessPolicyPlan(t, map[string]tftypes.Value{
+		"id":         tftypes.NewValue(tftypes.String, "user-alice@example.com"),
+		"user_id":    tftypes.NewValue(tftypes.String, "alice@example.com"),
+		"admin":      tftypes.NewValue(tftypes.Bool, false),
+		"workspaces": prod("viewer", "owner"),
+	})
+	priorState := accessPolicyPlan(t, map[string]tftypes.Value{
+		"id":         tftypes.NewValue(tftypes.String, "user-alice@example.com"),
+		"user_id":    tftypes.NewValue(tftypes.String, "alice@example.com"),
+		"admin":      tftypes.NewValue(tftypes.Bool, false),
+		"workspaces": prod("viewer"),
+	})
+	req := fwresource.UpdateRequest{Plan: plan, State: tfsdk.State{Schema: priorState.Schema, Raw: priorState.Raw}}
+	resp := fwresource.UpdateResponse{
+		State: tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)},
+	}
+	NewAccessPolicyResource().Update(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("expected the group roles not to fail verification, got: %v", resp.Diagnostics)
+	}
+}
+
+func TestGetFromTecton_assignmentSources(t *testing.T) {
+	fakeTectonCLI(t, `echo '[
+		{"resource_type": "WORKSPACE", "workspace_name": "prod", "roles_granted": [
+			{"role": "viewer", "assignment_sources": [{"assignment_type": "DIRECT"}, {"assignment_type": "PRINCIPAL_GROUP", "principal_group_name": "data-team"}]}
+		]},
+		{"resource_type": "ORGANIZATION", "roles_granted": [
+			{"role": "admin", "assignment_sources": [{"assignment_type": "DIRECT"}]},
+			{"role": "editor"}
+		]}
+	]'`)
+	state := accessPolicyResourceModel{UserID: types.StringValue("alice@example.com")}
+	_, err := NewAccessPolicyResource().(*accessPolicyResource).GetFromTecton(context.Background(), &state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var actual []string
+	for _, element := range state.AssignmentSources.Elements() {
+		attributes := element.(types.Object).Attributes()
+		actual = append(actual, fmt.Sprintf("%v %v %v %v", attributes["workspace"], attributes["role"], attributes["source"], attributes["group"]))
+	}
+	expected := `"*" "admin" "DIRECT" <null>
+"prod" "viewer" "DIRECT" <null>
+"prod" "viewer" "GROUP" "data-team"`
+	if strings.Join(actual, "\n") != expected {
+		t.Errorf("expected assignment sources:\n%v\ngot:\n%v", expected, strings.Join(actual, "\n"))
+	}
+}
+
+func TestCheckPrincipalStatus(t *testing.T) {
+	keyStatus := func(expiresIn time.Duration) string {
+		return fmt.Sprintf(`{"found": true, "active": true, "status": "ACTIVE", "key_expires_at": "%v"}`, time.Now().Add(expiresIn).UTC().Format(time.RFC3339))
+	}
+	testCases := map[string]struct {
+		output  string
+		warning bool
+	}{
+		"active":            {output: `{"found": true, "active": true, "status": "ACTIVE"}`},
+		"deactivated":       {output: `{"found": true, "active": false, "status": "DEPROVISIONED"}`, warning: true},
+		"deleted":           {output: `{"found": false, "active": null, "status": ""}`, warning: true},
+		"unreadable":        {output: `not json`},
+		"key expires soon":  {output: keyStatus(3 * 24 * time.Hour), warning: true},
+		"key expired":       {output: keyStatus(-time.Hour), warning: true},
+		"key expires later": {output: keyStatus(90 * 24 * time.Hour)},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fakeTectonPythonCLI(t, testCase.output, "")
+			state := accessPolicyResourceModel{ID: types.StringValue("service-ci-bot"), ServiceAccountID: types.StringValue("ci-bot")}
+			var diags diag.Diagnostics
+			r := NewAccessPolicyResource().(*accessPolicyResource)
+			r.KeyExpiryWarningWindow = defaultKeyExpiryWarningWindow
+			r.CheckPrincipalStatus(context.Background(), &state, &diags)
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if (diags.WarningsCount() > 0) != testCase.warning {
+				t.Errorf("expected warning: %v, got: %v", testCase.warning, diags)
+			}
+		})
+	}
+}
+
+func TestSuppressImpliedRoles(t *testing.T) {
+	roles := func(names ...string) []types.String {
+		var values []types.String
+		for _, name := range names {
+			values = append(values, types.StringValue(name))
+		}
+		return values
+	}
+
+	testCases := map[string]struct {
+		roles    []types.String
+		prior    []types.String
+		expected []types.String
+	}{
+		"implied roles removed": {
+			roles:    roles("viewer", "editor", "owner"),
+			prior:    roles("owner"),
+			expected: roles("owner"),
+		},
+		"explicit roles kept": {
+			roles:    roles("viewer", "editor", "owner"),
+			prior:    roles("viewer", "owner"),
+			expected: roles("viewer", "owner"),
+		},
+		"single role": {
+			roles:    roles("operator"),
+			expected: roles("operator"),
+		},
+		"unknown roles kept": {
+			roles:    roles("custom", "owner"),
+			expected: roles("custom", "owner"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := suppressImpliedRoles(testCase.roles, testCase.prior, validRoles)
+			if !slices.Equal(actual, testCase.expected) {
+				t.Errorf("expected %v, got %v", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCompareRoles(t *testing.T) {
+	roles := []types.String{
+		types.StringValue("owner"),
+		types.StringValue("zeta"),
+		types.StringValue("viewer"),
+		types.StringValue("alpha"),
+		types.StringValue("deployer"),
+	}
+	slices.SortFunc(roles, compareRoles([]string{"viewer", "deployer", "owner"}))
+
+	expected := []types.String{
+		types.StringValue("viewer"),
+		types.StringValue("deployer"),
+		types.StringValue("owner"),
+		types.StringValue("alpha"),
+		types.StringValue("zeta"),
+	}
+	if !slices.Equal(roles, expected) {
+		t.Errorf("expected %v, got %v", expected, roles)
+	}
+}
+
+func TestAccessPolicyResourceDelete_onDestroyRetain(t *testing.T) {
+	calls := filepath.Join(t.TempDir(), "calls")
+	fakeTectonCLI(t, `echo "$@" >> `+calls)
+	plan := accessPolicyPlan(t, map[string]tftypes.Value{
+		"id":             tftypes.NewValue(tftypes.String, "user-alice@example.com"),
+		"user_id":        tftypes.NewValue(tftypes.String, "alice@example.com"),
+		"all_workspaces": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "viewer")}),
+		"on_destroy":     tftypes.NewValue(tftypes.String, "retain"),
+	})
+	state := tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}
+	// Retaining the roles doesn't destroy anything, so it's allowed even if destroys are disabled
+	r := &accessPolicyResource{DisableDestroy: true}
+
+	planResp := fwresource.ModifyPlanResponse{}
+	r.ModifyPlan(context.Background(), fwresource.ModifyPlanRequest{
+		State: state,
+		Plan:  tfsdk.Plan{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)},
+	}, &planResp)
+	if planResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected plan error: %v", planResp.Diagnostics)
+	}
+
+	deleteResp := fwresource.DeleteResponse{}
+	r.Delete(context.Background(), fwresource.DeleteRequest{State: state}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected delete error: %v", deleteResp.Diagnostics)
+	}
+	if data, _ := os.ReadFile(calls); len(data) > 0 {
+		t.Errorf("expected no commands, got:\n%v", string(data))
+	}
+}
+
+func TestGetFromTecton_scopedRoles(t *testing.T) {
+	fakeTectonCLI(t, `echo '[
+		{"resource_type": "WORKSPACE", "workspace_name": "prod", "roles_granted": [{"role": "viewer"}]},
+		{"resource_type": "SECRET_SCOPE", "resource_name": "snowflake", "roles_granted": [{"role": "secret_scope_reader"}, {"role": "secret_scope_admin"}]},
+		{"resource_type": "SERVER_GROUP", "resource_id": "abc", "roles_granted": [{"role": "operator"}]}
+	]'`)
+	state := accessPolicyResourceModel{UserID: types.StringValue("alice@example.com")}
+	_, err := NewAccessPolicyResource().(*accessPolicyResource).GetFromTecton(context.Background(), &state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var actual []string
+	for _, element := range state.ScopedRoles.Elements() {
+		attributes := element.(types.Object).Attributes()
+		actual = append(actual, fmt.Sprintf("%v %v %v", attributes["resource_type"], attributes["resource"], attributes["roles"]))
+	}
+	expected := `"SECRET_SCOPE" "snowflake" ["secret_scope_admin","secret_scope_reader"]
+"SERVER_GROUP" "abc" ["operator"]`
+	if strings.Join(actual, "\n") != expected {
+		t.Errorf("expected scoped roles:\n%v\ngot:\n%v", expected, strings.Join(actual, "\n"))
+	}
+	if len(state.Workspaces["prod"]) != 1 {
+		t.Errorf("expected the workspace roles to be unaffected, got: %v", state.Workspaces)
+	}
+}
+
+func TestCheckNewScopedRoles(t *testing.T) {
+	snowflake := scopedRolesValue(map[[2]string][]string{{"SECRET_SCOPE", "snowflake"}: {"secret_scope_reader"}})
+	both := scopedRolesValue(map[[2]string][]string{
+		{"SECRET_SCOPE", "snowflake"}: {"secret_scope_reader"},
+		{"SERVER_GROUP", "abc"}:       {"operator"},
+	})
+	testCases := map[string]struct {
+		prior         types.List
+		current       types.List
+		expectWarning string
+	}{
+		"first refresh": {
+			prior:         types.ListNull(scopedRoleType),
+			current:       snowflake,
+			expectWarning: `SECRET_SCOPE 'snowflake': ["secret_scope_reader"]`,
+		},
+		"unchanged": {
+			prior:   snowflake,
+			current: snowflake,
+		},
+		"new resource": {
+			prior:         snowflake,
+			current:       both,
+			expectWarning: `SERVER_GROUP 'abc': ["operator"]`,
+		},
+		"removed resource": {
+			prior:   both,
+			current: snowflake,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			CheckNewScopedRoles(testCase.prior, testCase.current, "user-alice@example.com", &diags)
+			if testCase.expectWarning == "" {
+				if len(diags) != 0 {
+					t.Errorf("expected no warning, got: %v", diags)
+				}
+				return
+			}
+			if len(diags.Warnings()) != 1 {
+				t.Fatalf("expected a warning, got: %v", diags)
+			}
+			detail := diags.Warnings()[0].Detail()
+			if !strings.Contains(detail, testCase.expectWarning) || strings.Count(detail, "\n") != 1 {
+				t.Errorf("expected a warning listing only %v, got: %v", testCase.expectWarning, detail)
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &accessReportDataSource{}
+	_ datasource.DataSourceWithConfigure = &accessReportDataSource{}
+)
+
+// The values of `format`.
+const (
+	accessReportFormatCSV      = "csv"
+	accessReportFormatMarkdown = "markdown"
+)
+
+// NewAccessReportDataSource is a helper function to simplify the provider implementation.
+func NewAccessReportDataSource() datasource.DataSource {
+	return &accessReportDataSource{}
+}
+
+// accessReportDataSource renders the roles of every user and service account with a direct role
+// grant, e.g. for access reviews.
+type accessReportDataSource struct {
+	CLI           tectonclient.Client
+	WorkspaceData *WorkspaceCache
+}
+
+// accessReportDataSourceModel maps the data source schema data.
+type accessReportDataSourceModel struct {
+	Format types.String        `tfsdk:"format"`
+	Grants []accessReportGrant `tfsdk:"grants"`
+	Report types.String        `tfsdk:"report"`
+}
+
+// accessReportGrant maps an element of `grants`.
+type accessReportGrant struct {
+	Workspace     types.String `tfsdk:"workspace"`
+	PrincipalType types.String `tfsdk:"principal_type"`
+	Principal     types.String `tfsdk:"principal"`
+	Role          types.String `tfsdk:"role"`
+	Source        types.String `tfsdk:"source"`
+	Group         types.String `tfsdk:"group"`
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *accessReportDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.CLI = providerData.CLI
+	d.WorkspaceData = providerData.WorkspaceData
+}
+
+// Metadata returns the data source type name.
+func (d *accessReportDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_access_report"
+}
+
+// Schema defines the schema for the data source.
+func (d *accessReportDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the roles of every user and service account with a direct role grant on the organization or on any workspace, " +
+			"and renders them as a CSV or Markdown report, e.g. to generate quarterly access reviews from a plan.",
+		Attributes: map[string]schema.Attribute{
+			"format": schema.StringAttribute{
+				Description: "The format of `report`. Must be one of (\"csv\", \"markdown\"). Defaults to \"csv\".",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(accessReportFormatCSV, accessReportFormatMarkdown),
+				},
+			},
+			"grants": schema.ListNestedAttribute{
+				Description: "An element for every way each role is granted to each account, sorted by workspace, principal type, principal, role and source. " +
+					"Includes roles granted through a principal group to accounts that also have a direct grant.",
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"workspace": schema.StringAttribute{
+							Description: "The workspace the role is granted on, or \"*\" for roles granted on all workspaces, including admin.",
+							Computed:    true,
+						},
+						"principal_type": schema.StringAttribute{
+							Description: "\"user\" or \"service_account\".",
+							Computed:    true,
+						},
+						"principal": schema.StringAttribute{
+							Description: "The user ID (e.g. email) or service account ID.",
+							Computed:    true,
+						},
+						"role": schema.StringAttribute{
+							Description: "The role, e.g. \"owner\".",
+							Computed:    true,
+						},
+						"source": schema.StringAttribute{
+							Description: "\"DIRECT\" if the role is granted to the account directly, or \"GROUP\" if it is granted through a principal group. Other sources are reported as Tecton names them.",
+							Computed:    true,
+						},
+						"group": schema.StringAttribute{
+							Description: "The name of the principal group the role is granted through. Empty unless `source` is \"GROUP\".",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"report": schema.StringAttribute{
+				Description: "The grants rendered in `format`. A CSV report has a header row and a row for every element of `grants`, in the same order. " +
+					"A Markdown report has a section for every workspace, starting with the roles on all workspaces, each with a table of its grants.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+// Read lists the principals, reads their roles and renders the report.
+func (d *accessReportDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.CLI.WithCorrelationID(ctx)
+	defer d.CLI.LogOperationSummary(ctx, "data.tecton_access_report", "Read")
+	var config accessReportDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspaceData, err := d.WorkspaceData.Get(ctx)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to list Tecton workspaces", err)
+		return
+	}
+	grants, err := ReadAccessGrants(ctx, d.CLI, workspaceData)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read Tecton roles", err)
+		return
+	}
+
+	if config.Format.IsNull() {
+		config.Format = types.StringValue(accessReportFormatCSV)
+	}
+	config.Grants = grants
+	if config.Grants == nil {
+		config.Grants = []accessReportGrant{}
+	}
+	if config.Format.ValueString() == accessReportFormatMarkdown {
+		config.Report = types.StringValue(renderAccessReportMarkdown(grants))
+	} else {
+		config.Report = types.StringValue(renderAccessReportCSV(grants))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+// Reads the grants of every user and service account with a direct role grant on the organization or
+// on any of the workspaces, sorted by sortAccessReportGrants.
+func ReadAccessGrants(ctx context.Context, cli tectonclient.Client, workspaces tectonclient.Workspaces) ([]accessReportGrant, error) {
+	principals, err := ListPrincipals(ctx, cli, workspaces)
+	if err != nil {
+		return nil, err
+	}
+	list := principals.Principals()
+	policies := make([][]tectonclient.RolesPolicy, len(list))
+	err = runParallel(len(list), func(i int) error {
+		var err error
+		policies[i], err = cli.GetRoles(ctx, list[i])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var grants []accessReportGrant
+	for i, principal := range list {
+		grants = append(grants, accessReportGrants(principal, policies[i])...)
+	}
+	sortAccessReportGrants(grants)
+	return grants, nil
+}
+
+// Returns the grants of a principal's roles on the organization and on workspaces. Roles on other
+// resource types are omitted, as are roles whose source Tecton doesn't report.
+func accessReportGrants(principal tectonclient.Principal, policies []tectonclient.RolesPolicy) []accessReportGrant {
+	principalType, principalID := "user", principal.UserID
+	if principalID == "" {
+		principalType, principalID = "service_account", principal.ServiceAccountID
+	}
+	var grants []accessReportGrant
+	for _, policy := range policies {
+		workspace := policy.WorkspaceName
+		if policy.ResourceType == "ORGANIZATION" {
+			workspace = unmanagedRolesOrganizationKey
+		} else if policy.ResourceType != "WORKSPACE" {
+			continue
+		}
+		for _, roleGranted := range policy.RolesGranted {
+			for _, source := range roleGranted.AssignmentSources {
+				kind, group := source.AssignmentType, ""
+				if source.IsDirect() {
+					kind = "DIRECT"
+				} else if source.IsGroup() {
+					kind, group = "GROUP", source.PrincipalGroupName
+				}
+				grants = append(grants, accessReportGrant{
+					Workspace:     types.StringValue(workspace),
+					PrincipalType: types.StringValue(principalType),
+					Principal:     types.StringValue(principalID),
+					Role:          types.StringValue(roleGranted.Role),
+					Source:        types.StringValue(kind),
+					Group:         types.StringValue(group),
+				})
+			}
+		}
+	}
+	return grants
+}
+
+// Sorts grants by workspace, principal type, principal, role, source and group, so that the report
+// is the same on every read. The roles on all workspaces come first, since "*" sorts before any
+// workspace name.
+func sortAccessReportGrants(grants []accessReportGrant) {
+	key := func(grant accessReportGrant) []string {
+		return []string{
+			grant.Workspace.ValueString(),
+			grant.PrincipalType.ValueString(),
+			grant.Principal.ValueString(),
+			grant.Role.ValueString(),
+			grant.Source.ValueString(),
+			grant.Group.ValueString(),
+		}
+	}
+	sort.SliceStable(grants, func(i, j int) bool {
+		a, b := key(grants[i]), key(grants[j])
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+}
+
+// Renders sorted grants as CSV with a header row.
+func renderAccessReportCSV(grants []accessReportGrant) string {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	_ = writer.Write([]string{"workspace", "principal_type", "principal", "role", "source", "group"})
+	for _, grant := range grants {
+		_ = writer.Write([]string{
+			grant.Workspace.ValueString(),
+			grant.PrincipalType.ValueString(),
+			grant.Principal.ValueString(),
+			grant.Role.ValueString(),
+			grant.Source.ValueString(),
+			grant.Group.ValueString(),
+		})
+	}
+	writer.Flush()
+	return buffer.String()
+}
+
+// Renders sorted grants as Markdown, with a section and table for every workspace.
+func renderAccessReportMarkdown(grants []accessReportGrant) string {
+	var builder strings.Builder
+	builder.WriteString("# Tecton access report\n")
+	if len(grants) == 0 {
+		builder.WriteString("\nNo roles are granted.\n")
+	}
+	workspace := ""
+	for i, grant := range grants {
+		if i == 0 || grant.Workspace.ValueString() != workspace {
+			workspace = grant.Workspace.ValueString()
+			if workspace == unmanagedRolesOrganizationKey {
+				builder.WriteString("\n## All workspaces\n\n")
+			} else {
+				fmt.Fprintf(&builder, "\n## Workspace %v\n\n", markdownCell(workspace))
+			}
+			builder.WriteString("| Principal | Type | Role | Source |\n")
+			builder.WriteString("|-----------|------|------|--------|\n")
+		}
+		source := grant.Source.ValueString()
+		if grant.Group.ValueString() != "" {
+			source = fmt.Sprintf("%v (%v)", source, grant.Group.ValueString())
+		}
+		fmt.Fprintf(
+			&builder,
+			"| %v | %v | %v | %v |\n",
+			markdownCell(grant.Principal.ValueString()),
+			grant.PrincipalType.ValueString(),
+			markdownCell(grant.Role.ValueString()),
+			markdownCell(source),
+		)
+	}
+	return builder.String()
+}
+
+// Escapes the characters that would break a Markdown table cell.
+func markdownCell(value string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(value)
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestAccessReportDataSourceRead(t *testing.T) {
+	// Every principal has the same roles, since the fake CLI always prints the same output
+	fakeTectonPythonCLI(
+		t,
+		`{"users": ["alice@example.com"], "service_accounts": ["ci"]}`,
+		`[{"resource_type": "WORKSPACE", "workspace_name": "prod", "roles_granted": [{"role": "owner", "assignment_sources": [{"assignment_type": "ASSIGNMENT_TYPE_PRINCIPAL_GROUP", "principal_group_name": "platform"}]}]}, `+
+			`{"resource_type": "ORGANIZATION", "roles_granted": [{"role": "viewer", "assignment_sources": [{"assignment_type": "ASSIGNMENT_TYPE_DIRECT"}]}]}, `+
+			`{"resource_type": "SECRET_SCOPE", "resource_name": "creds", "roles_granted": [{"role": "reader", "assignment_sources": [{"assignment_type": "ASSIGNMENT_TYPE_DIRECT"}]}]}]`,
+	)
+
+	for _, format := range []string{"csv", "markdown"} {
+		t.Run(format, func(t *testing.T) {
+			ctx := context.Background()
+			resp := readDataSource(t, NewAccessReportDataSource(), map[string]tftypes.Value{
+				"format": tftypes.NewValue(tftypes.String, format),
+			})
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+
+			var state accessReportDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("failed to read state: %v", resp.Diagnostics)
+			}
+			if len(state.Grants) != 4 {
+				t.Fatalf("expected 4 grants, got: %v", state.Grants)
+			}
+			if state.Grants[0].Workspace.ValueString() != "*" || state.Grants[0].PrincipalType.ValueString() != "service_account" {
+				t.Errorf("expected the roles on all workspaces first, sorted by principal type, got: %v", state.Grants[0])
+			}
+
+			expected := map[string]string{
+				"csv": "workspace,principal_type,principal,role,source,group\n" +
+					"*,service_account,ci,viewer,DIRECT,\n" +
+					"*,user,alice@example.com,viewer,DIRECT,\n" +
+					"prod,service_account,ci,owner,GROUP,platform\n" +
+					"prod,user,alice@example.com,owner,GROUP,platform\n",
+				"markdown": "# Tecton access report\n\n" +
+					"## All workspaces\n\n" +
+					"| Principal | Type | Role | Source |\n" +
+					"|-----------|------|------|--------|\n" +
+					"| ci | service_account | viewer | DIRECT |\n" +
+					"| alice@example.com | user | viewer | DIRECT |\n\n" +
+					"## Workspace prod\n\n" +
+					"| Principal | Type | Role | Source |\n" +
+					"|-----------|------|------|--------|\n" +
+					"| ci | service_account | owner | GROUP (platform) |\n" +
+					"| alice@example.com | user | owner | GROUP (platform) |\n",
+			}[format]
+			if state.Report.ValueString() != expected {
+				t.Errorf("expected report:\n%v\ngot:\n%v", expected, state.Report.ValueString())
+			}
+		})
+	}
+}
+
+func TestRenderAccessReportMarkdown_empty(t *testing.T) {
+	report := renderAccessReportMarkdown(nil)
+	if !strings.Contains(report, "No roles are granted.") {
+		t.Errorf("unexpected report: %v", report)
+	}
+}
@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ ephemeral.EphemeralResource              = &apiTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &apiTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &apiTokenEphemeralResource{}
+)
+
+// The default for `description`.
+const defaultAPITokenDescription = "Temporary API key created by Terraform"
+
+// The private data key that the ID of the API key is stored under between Open and Close.
+const apiTokenPrivateKey = "api_key_id"
+
+// NewApiTokenEphemeralResource is a helper function to simplify the provider implementation.
+func NewApiTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &apiTokenEphemeralResource{}
+}
+
+// apiTokenEphemeralResource creates an API key that only lives for the duration of a Terraform run.
+type apiTokenEphemeralResource struct {
+	CLI tectonclient.Client
+}
+
+// apiTokenEphemeralResourceModel maps the ephemeral resource schema data.
+type apiTokenEphemeralResourceModel struct {
+	ID                  types.String `tfsdk:"id"`
+	Description         types.String `tfsdk:"description"`
+	Token               types.String `tfsdk:"token"`
+	AuthorizationHeader types.String `tfsdk:"authorization_header"`
+}
+
+// Configure adds the provider configured client to the ephemeral resource.
+func (r *apiTokenEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.CLI = providerData.CLI
+}
+
+// Metadata returns the ephemeral resource type name.
+func (r *apiTokenEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_token"
+}
+
+// Schema defines the schema for the ephemeral resource.
+func (r *apiTokenEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Creates a Tecton API key with the provider's credentials that is deleted again when Terraform is done with it, " +
+			"so that other providers (e.g. `http` or `kubernetes`) can call Tecton during a run without a long-lived key. " +
+			"The key is never stored in the state or plan. " +
+			"If Terraform is interrupted before it closes the ephemeral resource, the key must be deleted manually with `tecton api-key delete`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of the API key.",
+				Computed:    true,
+			},
+			"description": schema.StringAttribute{
+				Description: fmt.Sprintf("The description of the API key, shown in Tecton while it exists. Defaults to \"%v\".", defaultAPITokenDescription),
+				Optional:    true,
+			},
+			"token": schema.StringAttribute{
+				Description: "The API key.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"authorization_header": schema.StringAttribute{
+				Description: "The value of the `Authorization` header for Tecton's HTTP API, i.e. `Tecton-key <token>`.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+// Open creates the API key.
+func (r *apiTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "ephemeral.tecton_api_token", "Open")
+	var config apiTokenEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	description := defaultAPITokenDescription
+	if !config.Description.IsNull() {
+		description = config.Description.ValueString()
+	}
+	key, err := r.CLI.CreateAPIKey(ctx, description)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to create API key", err)
+		return
+	}
+	ctx = tflog.MaskMessageStrings(ctx, key.Key)
+
+	id, err := json.Marshal(key.ID)
+	if err != nil {
+		AddError(&resp.Diagnostics, ErrorCodeProviderBug, "Failed to encode API key ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, apiTokenPrivateKey, id)...)
+
+	config.ID = types.StringValue(key.ID)
+	config.Description = types.StringValue(description)
+	config.Token = types.StringValue(key.Key)
+	config.AuthorizationHeader = types.StringValue("Tecton-key " + key.Key)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &config)...)
+}
+
+// Close deletes the API key.
+func (r *apiTokenEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "ephemeral.tecton_api_token", "Close")
+	value, diags := req.Private.GetKey(ctx, apiTokenPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || value == nil {
+		return
+	}
+	var id string
+	err := json.Unmarshal(value, &id)
+	if err != nil {
+		AddError(&resp.Diagnostics, ErrorCodeProviderBug, "Failed to decode API key ID", err.Error())
+		return
+	}
+
+	err = r.CLI.DeleteAPIKey(ctx, id)
+	if err != nil {
+		AddCommandError(
+			&resp.Diagnostics,
+			fmt.Sprintf("Failed to delete API key '%v'. Delete it manually with `tecton api-key delete --id %v`", id, id),
+			err,
+		)
+	}
+}
@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ action.Action              = &applyAction{}
+	_ action.ActionWithConfigure = &applyAction{}
+)
+
+// NewApplyAction is a helper function to simplify the provider implementation.
+func NewApplyAction() action.Action {
+	return &applyAction{}
+}
+
+// applyAction runs `tecton plan` or `tecton apply` for a feature repo.
+type applyAction struct {
+	CLI tectonclient.Client
+}
+
+// applyActionModel maps the action schema data.
+type applyActionModel struct {
+	RepoPath       types.String `tfsdk:"repo_path"`
+	Workspace      types.String `tfsdk:"workspace"`
+	PlanOnly       types.Bool   `tfsdk:"plan_only"`
+	SkipTests      types.Bool   `tfsdk:"skip_tests"`
+	CommandTimeout types.String `tfsdk:"command_timeout"`
+}
+
+// Configure adds the provider configured client to the action.
+func (a *applyAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	a.CLI = providerData.CLI
+}
+
+// Metadata returns the action type name.
+func (a *applyAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_apply"
+}
+
+// Schema defines the schema for the action.
+func (a *applyAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Runs `tecton apply` (or `tecton plan`) for a feature repo against a workspace when invoked with `terraform apply -invoke`, " +
+			"so that deploying feature definitions can be an explicit operator-triggered step.",
+		Attributes: map[string]schema.Attribute{
+			"repo_path": schema.StringAttribute{
+				Description: "The path to the feature repo, i.e. the directory containing the `.tecton` file.",
+				Required:    true,
+			},
+			"workspace": schema.StringAttribute{
+				Description: "The name of the workspace to apply the feature repo to.",
+				Required:    true,
+			},
+			"plan_only": schema.BoolAttribute{
+				Description: "True to only run `tecton plan` and report the changes without applying them. Defaults to false.",
+				Optional:    true,
+			},
+			"skip_tests": schema.BoolAttribute{
+				Description: "True to skip running the feature repo's tests before planning. Defaults to false.",
+				Optional:    true,
+			},
+			"command_timeout": schema.StringAttribute{
+				Description: "Overrides the provider's `command_timeout` for the `tecton apply` or `tecton plan` command, since applying a large feature repo can take longer than other commands.",
+				Optional:    true,
+				Validators: []validator.String{
+					durationValidator(),
+				},
+			},
+		},
+	}
+}
+
+// Invoke runs `tecton plan` or `tecton apply` in the feature repo.
+func (a *applyAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	ctx = a.CLI.WithCorrelationID(ctx)
+	defer a.CLI.LogOperationSummary(ctx, "action.tecton_apply", "Invoke")
+	var config applyActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	repoPath := config.RepoPath.ValueString()
+	info, err := os.Stat(repoPath)
+	if err != nil || !info.IsDir() {
+		AddAttributeError(
+			&resp.Diagnostics,
+			path.Root("repo_path"),
+			ErrorCodeInvalidConfig,
+			"Invalid Feature Repo Path",
+			fmt.Sprintf("Feature repo '%v' is not a directory.", repoPath),
+		)
+		return
+	}
+
+	args := []string{"apply", "--yes"}
+	if config.PlanOnly.ValueBool() {
+		args = []string{"plan"}
+	}
+	args = append(args, "--workspace", config.Workspace.ValueString())
+	if config.SkipTests.ValueBool() {
+		args = append(args, "--skip-tests")
+	}
+
+	if !config.PlanOnly.ValueBool() && a.CLI.SkipInDryRun(ctx, fmt.Sprintf("cd %v && tecton %v", tectonclient.ShellJoin([]string{repoPath}), tectonclient.ShellJoin(args))) {
+		resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("Skipped `tecton apply` for feature repo '%v', since the provider's `dry_run` is set", repoPath)})
+		return
+	}
+	cli := withCommandTimeout(a.CLI, config.CommandTimeout)
+	cli.Dir = repoPath
+	tflog.Info(ctx, fmt.Sprintf("Running 'tecton %v' in '%v'", tectonclient.ShellJoin(args), repoPath))
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Running `tecton %v` for feature repo '%v'", args[0], repoPath),
+	})
+	output, err := cli.Run(ctx, args...)
+	if err != nil {
+		AddCommandError(
+			&resp.Diagnostics,
+			fmt.Sprintf("Failed to run tecton %v", args[0]),
+			cli.CommandError(
+				ctx,
+				fmt.Sprintf("%v feature repo '%v' to workspace '%v'", args[0], repoPath, config.Workspace.ValueString()),
+				args,
+				output,
+				err,
+			),
+		)
+		return
+	}
+	resp.SendProgress(action.InvokeProgressEvent{Message: string(output)})
+}
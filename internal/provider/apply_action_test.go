@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestApplyActionInvoke(t *testing.T) {
+	fakeTectonCLI(t, `echo "$(pwd) $@"`)
+	repoPath, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		planOnly bool
+		expected string
+	}{
+		"apply": {
+			expected: repoPath + " apply --yes --workspace prod --skip-tests",
+		},
+		"plan": {
+			planOnly: true,
+			expected: repoPath + " plan --workspace prod --skip-tests",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := action.InvokeRequest{
+				Config: actionConfig(t, NewApplyAction(), map[string]tftypes.Value{
+					"repo_path":  tftypes.NewValue(tftypes.String, repoPath),
+					"workspace":  tftypes.NewValue(tftypes.String, "prod"),
+					"plan_only":  tftypes.NewValue(tftypes.Bool, testCase.planOnly),
+					"skip_tests": tftypes.NewValue(tftypes.Bool, true),
+				}),
+			}
+			var messages []string
+			resp := action.InvokeResponse{
+				SendProgress: func(event action.InvokeProgressEvent) {
+					messages = append(messages, event.Message)
+				},
+			}
+			NewApplyAction().Invoke(context.Background(), req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			output := strings.TrimSpace(messages[len(messages)-1])
+			if output != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, output)
+			}
+		})
+	}
+}
+
+func TestApplyActionInvoke_missingRepo(t *testing.T) {
+	fakeTectonCLI(t, `echo unreachable`)
+	req := action.InvokeRequest{
+		Config: actionConfig(t, NewApplyAction(), map[string]tftypes.Value{
+			"repo_path": tftypes.NewValue(tftypes.String, filepath.Join(t.TempDir(), "missing")),
+			"workspace": tftypes.NewValue(tftypes.String, "prod"),
+		}),
+	}
+	resp := action.InvokeResponse{SendProgress: func(action.InvokeProgressEvent) {}}
+	NewApplyAction().Invoke(context.Background(), req, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,310 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	fwpath "github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource                   = &assertionDataSource{}
+	_ datasource.DataSourceWithConfigure      = &assertionDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &assertionDataSource{}
+)
+
+// NewAssertionDataSource is a helper function to simplify the provider implementation.
+func NewAssertionDataSource() datasource.DataSource {
+	return &assertionDataSource{}
+}
+
+// assertionDataSource checks the roles granted in the cluster against access assertions.
+type assertionDataSource struct {
+	CLI           tectonclient.Client
+	WorkspaceData *WorkspaceCache
+}
+
+// assertionDataSourceModel maps the data source schema data.
+type assertionDataSourceModel struct {
+	FailOnViolation types.Bool                 `tfsdk:"fail_on_violation"`
+	Assertions      []accessAssertionModel     `tfsdk:"assertion"`
+	Passed          types.Bool                 `tfsdk:"passed"`
+	Violations      []accessAssertionViolation `tfsdk:"violations"`
+}
+
+// accessAssertionModel maps an `assertion` block.
+type accessAssertionModel struct {
+	Name           types.String   `tfsdk:"name"`
+	Workspaces     []types.String `tfsdk:"workspaces"`
+	Principals     []types.String `tfsdk:"principals"`
+	PrincipalType  types.String   `tfsdk:"principal_type"`
+	ForbiddenRoles []types.String `tfsdk:"forbidden_roles"`
+	AllowedRoles   []types.String `tfsdk:"allowed_roles"`
+}
+
+// accessAssertionViolation maps an element of `violations`.
+type accessAssertionViolation struct {
+	Assertion     types.String `tfsdk:"assertion"`
+	Workspace     types.String `tfsdk:"workspace"`
+	PrincipalType types.String `tfsdk:"principal_type"`
+	Principal     types.String `tfsdk:"principal"`
+	Role          types.String `tfsdk:"role"`
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *assertionDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.CLI = providerData.CLI
+	d.WorkspaceData = providerData.WorkspaceData
+}
+
+// Metadata returns the data source type name.
+func (d *assertionDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_assertion"
+}
+
+// Schema defines the schema for the data source.
+func (d *assertionDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Checks the roles granted in the cluster against assertions, e.g. \"no user is an owner of a prod workspace\" or \"the CI service account only has viewer\", " +
+			"and fails the plan if any is violated, as lightweight policy-as-code. Roles are read like `tecton_access_report`'s, i.e. for every account with a direct role grant, " +
+			"including the roles those accounts are granted through principal groups. Roles granted on all workspaces, including admin, apply to every workspace.",
+		Attributes: map[string]schema.Attribute{
+			"fail_on_violation": schema.BoolAttribute{
+				Description: "If true, a violated assertion fails the plan with an error listing the violations. " +
+					"If false, violations are only reported in `violations` and `passed`, e.g. to check them in a `check` block. Defaults to true.",
+				Optional: true,
+			},
+			"passed": schema.BoolAttribute{
+				Description: "True if no assertion is violated.",
+				Computed:    true,
+			},
+			"violations": schema.ListNestedAttribute{
+				Description: "An element for every role that violates an assertion, in the order of the assertions and then sorted by workspace, principal type, principal and role.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"assertion": schema.StringAttribute{
+							Description: "The `name` of the violated assertion.",
+							Computed:    true,
+						},
+						"workspace": schema.StringAttribute{
+							Description: "The workspace the role is granted on, or \"*\" for roles granted on all workspaces, including admin.",
+							Computed:    true,
+						},
+						"principal_type": schema.StringAttribute{
+							Description: "\"user\" or \"service_account\".",
+							Computed:    true,
+						},
+						"principal": schema.StringAttribute{
+							Description: "The user ID (e.g. email) or service account ID.",
+							Computed:    true,
+						},
+						"role": schema.StringAttribute{
+							Description: "The role that violates the assertion.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"assertion": schema.ListNestedBlock{
+				Description: "An assertion about the roles of the accounts and workspaces it selects. Each must set `forbidden_roles`, `allowed_roles` or both. May be repeated.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "A name for the assertion, which identifies it in errors and `violations`.",
+							Required:    true,
+						},
+						"workspaces": schema.ListAttribute{
+							Description: "Shell patterns of the workspaces the assertion applies to, e.g. [\"prod-*\"]. Defaults to every workspace.",
+							ElementType: types.StringType,
+							Optional:    true,
+						},
+						"principals": schema.ListAttribute{
+							Description: "Shell patterns of the user IDs and service account IDs the assertion applies to, e.g. [\"*@contractors.example.com\"]. Defaults to every account.",
+							ElementType: types.StringType,
+							Optional:    true,
+						},
+						"principal_type": schema.StringAttribute{
+							Description: "Only apply the assertion to users or to service accounts. Must be one of (\"user\", \"service_account\"). Defaults to both.",
+							Optional:    true,
+							Validators: []validator.String{
+								stringvalidator.OneOf("user", "service_account"),
+							},
+						},
+						"forbidden_roles": schema.ListAttribute{
+							Description: "Roles the selected accounts must not have on the selected workspaces, e.g. [\"owner\", \"admin\"].",
+							ElementType: types.StringType,
+							Optional:    true,
+						},
+						"allowed_roles": schema.ListAttribute{
+							Description: "The only roles the selected accounts may have on the selected workspaces, e.g. [\"viewer\"]. Any other role is a violation.",
+							ElementType: types.StringType,
+							Optional:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig checks that every assertion asserts something.
+func (d *assertionDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	// The config can't be read while any of its lists are unknown, so it's only validated once they're known
+	var config assertionDataSourceModel
+	if req.Config.Get(ctx, &config).HasError() {
+		return
+	}
+	for i, assertion := range config.Assertions {
+		if assertion.ForbiddenRoles == nil && assertion.AllowedRoles == nil {
+			AddAttributeError(
+				&resp.Diagnostics,
+				fwpath.Root("assertion").AtListIndex(i),
+				ErrorCodeInvalidConfig,
+				"Empty assertion",
+				fmt.Sprintf("Assertion '%v' must set `forbidden_roles`, `allowed_roles` or both.", assertion.Name.ValueString()),
+			)
+		}
+	}
+}
+
+// Read reads the roles in the cluster and checks them against the assertions.
+func (d *assertionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.CLI.WithCorrelationID(ctx)
+	defer d.CLI.LogOperationSummary(ctx, "data.tecton_assertion", "Read")
+	var config assertionDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspaceData, err := d.WorkspaceData.Get(ctx)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to list Tecton workspaces", err)
+		return
+	}
+	grants, err := ReadAccessGrants(ctx, d.CLI, workspaceData)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read Tecton roles", err)
+		return
+	}
+
+	config.Violations = []accessAssertionViolation{}
+	for _, assertion := range config.Assertions {
+		config.Violations = append(config.Violations, assertion.violations(grants)...)
+	}
+	config.Passed = types.BoolValue(len(config.Violations) == 0)
+
+	if !config.Passed.ValueBool() && (config.FailOnViolation.IsNull() || config.FailOnViolation.ValueBool()) {
+		lines := make([]string, 0, len(config.Violations))
+		for _, violation := range config.Violations {
+			lines = append(lines, fmt.Sprintf(
+				"- %v: %v '%v' has role '%v' on %v",
+				violation.Assertion.ValueString(),
+				strings.ReplaceAll(violation.PrincipalType.ValueString(), "_", " "),
+				violation.Principal.ValueString(),
+				violation.Role.ValueString(),
+				assertionWorkspaceName(violation.Workspace.ValueString()),
+			))
+		}
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodePolicyViolation,
+			"Tecton access assertion violated",
+			fmt.Sprintf("The roles granted in Tecton violate %v access assertions:\n%v", len(lines), strings.Join(lines, "\n")),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+// Returns the grants that violate the assertion, one for every distinct role of an account on a
+// workspace. grants must be sorted by sortAccessReportGrants, so that the violations are too.
+func (a accessAssertionModel) violations(grants []accessReportGrant) []accessAssertionViolation {
+	var violations []accessAssertionViolation
+	for _, grant := range grants {
+		if !a.selects(grant) {
+			continue
+		}
+		role := grant.Role.ValueString()
+		forbidden := slices.Contains(StringValues(a.ForbiddenRoles), role)
+		notAllowed := a.AllowedRoles != nil && !slices.Contains(StringValues(a.AllowedRoles), role)
+		if !forbidden && !notAllowed {
+			continue
+		}
+		violation := accessAssertionViolation{
+			Assertion:     a.Name,
+			Workspace:     grant.Workspace,
+			PrincipalType: grant.PrincipalType,
+			Principal:     grant.Principal,
+			Role:          grant.Role,
+		}
+		// A role granted both directly and through a group is one violation
+		if len(violations) > 0 && violations[len(violations)-1] == violation {
+			continue
+		}
+		violations = append(violations, violation)
+	}
+	return violations
+}
+
+// Returns true if the grant is on a workspace and to an account that the assertion applies to. A
+// grant on all workspaces applies to every workspace.
+func (a accessAssertionModel) selects(grant accessReportGrant) bool {
+	if !a.PrincipalType.IsNull() && a.PrincipalType.ValueString() != grant.PrincipalType.ValueString() {
+		return false
+	}
+	if a.Principals != nil && !matchesAnyPattern(StringValues(a.Principals), grant.Principal.ValueString()) {
+		return false
+	}
+	workspace := grant.Workspace.ValueString()
+	return a.Workspaces == nil || workspace == unmanagedRolesOrganizationKey || matchesAnyPattern(StringValues(a.Workspaces), workspace)
+}
+
+// Returns true if the value matches any of the shell patterns. Invalid patterns match nothing.
+func matchesAnyPattern(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, value); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Describes the workspace of a grant in an error.
+func assertionWorkspaceName(workspace string) string {
+	if workspace == unmanagedRolesOrganizationKey {
+		return "all workspaces"
+	}
+	return fmt.Sprintf("workspace '%v'", workspace)
+}
@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+func TestAssertionDataSourceRead(t *testing.T) {
+	fakeTectonCluster(t, `{
+		"workspaces": {"prod": {"live": true}, "prod-eu": {"live": true}, "dev": {"live": false}},
+		"roles": {
+			"user:alice": {"prod-eu": ["owner"], "dev": ["owner"]},
+			"user:bob": {"*": ["admin"]},
+			"user:carol": {"prod": ["viewer"]},
+			"service:ci": {"prod": ["viewer"], "dev": ["editor"]}
+		}
+	}`)
+	assertions := []accessAssertionModel{
+		{
+			Name:           types.StringValue("no owners on prod"),
+			Workspaces:     []types.String{types.StringValue("prod*")},
+			PrincipalType:  types.StringValue("user"),
+			ForbiddenRoles: []types.String{types.StringValue("owner"), types.StringValue("admin")},
+		},
+		{
+			Name:         types.StringValue("ci is read-only"),
+			Principals:   []types.String{types.StringValue("ci")},
+			AllowedRoles: []types.String{types.StringValue("viewer")},
+		},
+	}
+	expected := []string{
+		"no owners on prod user bob * admin",
+		"no owners on prod user alice prod-eu owner",
+		"ci is read-only service_account ci dev editor",
+	}
+
+	ctx := context.Background()
+	d := &assertionDataSource{WorkspaceData: NewWorkspaceCache(tectonclient.NewWorkspaces([]string{"prod", "prod-eu"}, []string{"dev"}))}
+	read := func(failOnViolation bool) datasource.ReadResponse {
+		config := dataSourceConfig(t, d, nil)
+		state := tfsdk.State{Schema: config.Schema, Raw: config.Raw}
+		diags := state.Set(ctx, &assertionDataSourceModel{FailOnViolation: types.BoolValue(failOnViolation), Assertions: assertions})
+		if diags.HasError() {
+			t.Fatalf("failed to build config: %v", diags)
+		}
+		var values map[string]tftypes.Value
+		err := state.Raw.As(&values)
+		if err != nil {
+			t.Fatalf("failed to build config: %v", err)
+		}
+		return readDataSource(t, d, values)
+	}
+
+	resp := read(false)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+	var state assertionDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	var actual []string
+	for _, v := range state.Violations {
+		actual = append(actual, strings.Join([]string{v.Assertion.ValueString(), v.PrincipalType.ValueString(), v.Principal.ValueString(), v.Workspace.ValueString(), v.Role.ValueString()}, " "))
+	}
+	if state.Passed.ValueBool() || strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected the violations:\n%v\ngot (passed = %v):\n%v", strings.Join(expected, "\n"), state.Passed, strings.Join(actual, "\n"))
+	}
+
+	resp = read(true)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "[TECTON_POLICY_VIOLATION]") ||
+		!strings.Contains(resp.Diagnostics[0].Detail(), "user 'bob' has role 'admin' on all workspaces") {
+		t.Errorf("expected a policy violation error, got: %v", resp.Diagnostics)
+	}
+}
+
+func TestAccessAssertionViolations_deduplicatesSources(t *testing.T) {
+	grant := func(source string) accessReportGrant {
+		return accessReportGrant{
+			Workspace:     types.StringValue("prod"),
+			PrincipalType: types.StringValue("user"),
+			Principal:     types.StringValue("alice"),
+			Role:          types.StringValue("owner"),
+			Source:        types.StringValue(source),
+		}
+	}
+	assertion := accessAssertionModel{Name: types.StringValue("no owners"), ForbiddenRoles: []types.String{types.StringValue("owner")}}
+	if violations := assertion.violations([]accessReportGrant{grant("DIRECT"), grant("GROUP")}); len(violations) != 1 {
+		t.Errorf("expected one violation for a role granted directly and through a group, got: %v", violations)
+	}
+}
@@ -0,0 +1,515 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                     = &bulkRoleAssignmentResource{}
+	_ resource.ResourceWithConfigure        = &bulkRoleAssignmentResource{}
+	_ resource.ResourceWithConfigValidators = &bulkRoleAssignmentResource{}
+	_ resource.ResourceWithModifyPlan       = &bulkRoleAssignmentResource{}
+)
+
+// The maximum number of role commands a bulk role assignment runs at the same time.
+const bulkRoleAssignmentParallelism = 8
+
+// NewBulkRoleAssignmentResource is a helper function to simplify the provider implementation.
+func NewBulkRoleAssignmentResource() resource.Resource {
+	return &bulkRoleAssignmentResource{}
+}
+
+// bulkRoleAssignmentResource is the resource implementation.
+type bulkRoleAssignmentResource struct {
+	CLI            tectonclient.Client
+	Notifier       *Notifier
+	DisableDestroy bool
+}
+
+// bulkRoleAssignmentResourceModel maps the resource schema data.
+type bulkRoleAssignmentResourceModel struct {
+	ID                types.String   `tfsdk:"id"`
+	LastUpdated       types.String   `tfsdk:"last_updated"`
+	Workspace         types.String   `tfsdk:"workspace"`
+	Roles             []types.String `tfsdk:"roles"`
+	UserIDs           []types.String `tfsdk:"user_ids"`
+	ServiceAccountIDs []types.String `tfsdk:"service_account_ids"`
+}
+
+// A single role grant or revocation for a principal.
+type roleChange struct {
+	Principal tectonclient.Principal
+	Role      string
+	Grant     bool
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *bulkRoleAssignmentResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.CLI = providerData.CLI
+	r.Notifier = providerData.Notifier
+	r.DisableDestroy = providerData.DisableDestroy
+}
+
+// Metadata returns the resource type name.
+func (r *bulkRoleAssignmentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bulk_role_assignment"
+}
+
+// Schema defines the schema for the resource.
+func (r *bulkRoleAssignmentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Grants the same set of roles on a workspace to many users and service accounts, e.g. a whole on-call rotation. " +
+			"Only the listed roles are managed, so principals may also be managed by `tecton_access_policy` as long as the two don't manage the same roles on the same workspace.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier for this role assignment. Equal to the workspace name.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Description: "Timestamp of the last Terraform update of the role assignment.",
+				Computed:    true,
+			},
+			"workspace": schema.StringAttribute{
+				Description: "The name of the workspace on which the roles are granted.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"roles": schema.SetAttribute{
+				Description: "The roles granted to every principal. Set values must be one of (\"viewer\", \"operator\", \"editor\", \"owner\").",
+				Required:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(
+						stringvalidator.OneOf(validRoles...),
+					),
+				},
+			},
+			"user_ids": schema.SetAttribute{
+				Description: "The user IDs (e.g. emails) to which the roles are granted.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(
+							regexp.MustCompile(`^[a-zA-Z0-9_.@-]+$`),
+							"must contain only alphanumeric characters, or characters in the set _.@-",
+						),
+					),
+				},
+			},
+			"service_account_ids": schema.SetAttribute{
+				Description: "The service account IDs to which the roles are granted.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(
+							regexp.MustCompile(`^[a-zA-Z0-9]+$`),
+							"must contain only alphanumeric characters",
+						),
+					),
+				},
+			},
+		},
+	}
+}
+
+func (r *bulkRoleAssignmentResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.AtLeastOneOf(
+			path.MatchRoot("user_ids"),
+			path.MatchRoot("service_account_ids"),
+		),
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *bulkRoleAssignmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_bulk_role_assignment", "Create")
+	// Retrieve values from plan
+	var plan bulkRoleAssignmentResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Report the changes made in Tecton to the notification webhook, even if creation fails
+	cli, changes := r.CLI.RecordingChanges()
+	defer r.Notifier.Notify(ctx, fmt.Sprintf("tecton_bulk_role_assignment '%v'", plan.Workspace.ValueString()), changes, &resp.Diagnostics)
+
+	// Only grant the roles that principals don't already have
+	var empty bulkRoleAssignmentResourceModel
+	err := (&bulkRoleAssignmentResource{CLI: cli}).UpdateRoleAssignment(ctx, &plan, &empty)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Role Assignment Failure", err)
+		return
+	}
+
+	// Generated computed values
+	plan.ID = plan.Workspace
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *bulkRoleAssignmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_bulk_role_assignment", "Read")
+	// Get current state
+	var state bulkRoleAssignmentResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Principals that are missing any of the roles are dropped from the state, so that the next
+	// plan grants them the roles again
+	complete, err := r.PrincipalsWithAllRoles(ctx, &state)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read Tecton roles", err)
+		return
+	}
+	state.UserIDs = filterPrincipals(state.UserIDs, complete, func(id string) tectonclient.Principal { return tectonclient.Principal{UserID: id} })
+	state.ServiceAccountIDs = filterPrincipals(state.ServiceAccountIDs, complete, func(id string) tectonclient.Principal { return tectonclient.Principal{ServiceAccountID: id} })
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *bulkRoleAssignmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_bulk_role_assignment", "Update")
+	// Retrieve values from plan
+	var plan bulkRoleAssignmentResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Also retrieve current state
+	var state bulkRoleAssignmentResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cli, changes := r.CLI.RecordingChanges()
+	defer r.Notifier.Notify(ctx, fmt.Sprintf("tecton_bulk_role_assignment '%v'", plan.Workspace.ValueString()), changes, &resp.Diagnostics)
+
+	err := (&bulkRoleAssignmentResource{CLI: cli}).UpdateRoleAssignment(ctx, &plan, &state)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Role Assignment Failure", err)
+		return
+	}
+
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// ModifyPlan fails plans that destroy the role assignment if the provider's `disable_destroy` is set.
+func (r *bulkRoleAssignmentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_bulk_role_assignment", "ModifyPlan")
+	if !req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+	var workspace types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("workspace"), &workspace)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	CheckDestroyAllowed(r.DisableDestroy, fmt.Sprintf("tecton_bulk_role_assignment '%v'", workspace.ValueString()), &resp.Diagnostics)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *bulkRoleAssignmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_bulk_role_assignment", "Delete")
+	// Get current state
+	var state bulkRoleAssignmentResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !CheckDestroyAllowed(r.DisableDestroy, fmt.Sprintf("tecton_bulk_role_assignment '%v'", state.Workspace.ValueString()), &resp.Diagnostics) {
+		return
+	}
+
+	// Delete resource by updating to an empty plan
+	cli, changes := r.CLI.RecordingChanges()
+	defer r.Notifier.Notify(ctx, fmt.Sprintf("tecton_bulk_role_assignment '%v'", state.Workspace.ValueString()), changes, &resp.Diagnostics)
+	emptyPlan := bulkRoleAssignmentResourceModel{Workspace: state.Workspace}
+	err := (&bulkRoleAssignmentResource{CLI: cli}).UpdateRoleAssignment(ctx, &emptyPlan, &state)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Unable to delete role assignment", err)
+	}
+}
+
+// Returns the principals in the model.
+func (m *bulkRoleAssignmentResourceModel) Principals() []tectonclient.Principal {
+	var principals []tectonclient.Principal
+	for _, id := range m.UserIDs {
+		principals = append(principals, tectonclient.Principal{UserID: id.ValueString()})
+	}
+	for _, id := range m.ServiceAccountIDs {
+		principals = append(principals, tectonclient.Principal{ServiceAccountID: id.ValueString()})
+	}
+	return principals
+}
+
+// Returns the set of principals in the model that have all of the model's roles on its workspace.
+func (r *bulkRoleAssignmentResource) PrincipalsWithAllRoles(ctx context.Context, model *bulkRoleAssignmentResourceModel) (map[tectonclient.Principal]bool, error) {
+	principals := model.Principals()
+	granted, err := r.GetWorkspaceRoles(ctx, principals, model.Workspace.ValueString())
+	if err != nil {
+		return nil, err
+	}
+
+	complete := make(map[tectonclient.Principal]bool)
+	for _, p := range principals {
+		complete[p] = true
+		for _, role := range model.Roles {
+			if !granted[p][role.ValueString()] {
+				complete[p] = false
+			}
+		}
+	}
+	return complete, nil
+}
+
+// Reads the roles that are granted directly to each of the principals on a workspace in Tecton.
+// Roles that are only inherited through a group are left out, since the principal loses them as soon
+// as the group changes, so a direct grant is still needed.
+func (r *bulkRoleAssignmentResource) GetWorkspaceRoles(
+	ctx context.Context,
+	principals []tectonclient.Principal,
+	workspace string,
+) (map[tectonclient.Principal]map[string]bool, error) {
+	roles := make([]map[string]bool, len(principals))
+	err := runParallel(len(principals), func(i int) error {
+		policies, err := r.CLI.GetRoles(ctx, principals[i])
+		if err != nil {
+			return err
+		}
+		roles[i] = make(map[string]bool)
+		for _, policy := range policies {
+			if policy.ResourceType != "WORKSPACE" || policy.WorkspaceName != workspace {
+				continue
+			}
+			for _, roleGranted := range policy.RolesGranted {
+				if roleGranted.IsDirect() {
+					roles[i][roleGranted.Role] = true
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	granted := make(map[tectonclient.Principal]map[string]bool)
+	for i, p := range principals {
+		granted[p] = roles[i]
+	}
+	return granted, nil
+}
+
+// Makes the necessary calls to make Tecton consistent with the plan. Role changes are independent of
+// each other, so they're run in parallel. As with access policies, grants are all applied before any
+// revocations so that no principal is left without access part way through.
+func (r *bulkRoleAssignmentResource) UpdateRoleAssignment(
+	ctx context.Context,
+	plan *bulkRoleAssignmentResourceModel,
+	state *bulkRoleAssignmentResourceModel,
+) error {
+	var grants, revocations []roleChange
+
+	planPrincipals := make(map[tectonclient.Principal]bool)
+	for _, p := range plan.Principals() {
+		planPrincipals[p] = true
+		statePrincipalRoles := state.Roles
+		if !containsPrincipal(state.Principals(), p) {
+			statePrincipalRoles = nil
+		}
+		for _, role := range SliceDifference(plan.Roles, statePrincipalRoles) {
+			grants = append(grants, roleChange{p, role, true})
+		}
+		for _, role := range SliceDifference(statePrincipalRoles, plan.Roles) {
+			revocations = append(revocations, roleChange{p, role, false})
+		}
+	}
+	for _, p := range state.Principals() {
+		if planPrincipals[p] {
+			continue
+		}
+		for _, role := range state.Roles {
+			revocations = append(revocations, roleChange{p, role.ValueString(), false})
+		}
+	}
+
+	// The Terraform state may be stale, so changes that the cluster already matches are skipped. They
+	// would be no-ops, but each still takes a command and produces an audit event.
+	workspace := plan.Workspace.ValueString()
+	if len(grants) > 0 || len(revocations) > 0 {
+		var principals []tectonclient.Principal
+		for _, change := range append(append([]roleChange{}, grants...), revocations...) {
+			if !containsPrincipal(principals, change.Principal) {
+				principals = append(principals, change.Principal)
+			}
+		}
+		granted, err := r.GetWorkspaceRoles(ctx, principals, workspace)
+		if err != nil {
+			return err
+		}
+		grants, revocations = pendingRoleChanges(ctx, grants, granted), pendingRoleChanges(ctx, revocations, granted)
+	}
+	for _, changes := range [][]roleChange{grants, revocations} {
+		err := ApplyRoleChanges(ctx, r.CLI, workspace, changes)
+		if err != nil {
+			return err
+		}
+	}
+	tflog.Info(ctx, fmt.Sprintf("Applied %v role grants and %v role revocations on workspace '%v'", len(grants), len(revocations), workspace))
+	return nil
+}
+
+// Returns the changes that aren't already reflected in the roles granted in Tecton, i.e. grants of
+// roles that principals don't have and revocations of roles that they do.
+func pendingRoleChanges(ctx context.Context, changes []roleChange, granted map[tectonclient.Principal]map[string]bool) []roleChange {
+	var needed []roleChange
+	for _, change := range changes {
+		if granted[change.Principal][change.Role] == change.Grant {
+			tflog.Info(ctx, fmt.Sprintf("Skipping a change to role '%v' for %v, since Tecton already matches the plan", change.Role, change.Principal))
+			continue
+		}
+		needed = append(needed, change)
+	}
+	return needed
+}
+
+// Applies independent role changes on a workspace in parallel.
+func ApplyRoleChanges(ctx context.Context, cli tectonclient.Client, workspace string, changes []roleChange) error {
+	return runParallel(len(changes), func(i int) error {
+		change := changes[i]
+		return cli.ModifyRole(ctx, change.Principal, change.Role, workspace, change.Grant)
+	})
+}
+
+// Returns true if the principal is in the list.
+func containsPrincipal(principals []tectonclient.Principal, p tectonclient.Principal) bool {
+	for _, other := range principals {
+		if other == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the IDs whose principals are in the set.
+func filterPrincipals(ids []types.String, principals map[tectonclient.Principal]bool, toPrincipal func(string) tectonclient.Principal) []types.String {
+	if ids == nil {
+		return nil
+	}
+	filtered := []types.String{}
+	for _, id := range ids {
+		if principals[toPrincipal(id.ValueString())] {
+			filtered = append(filtered, id)
+		}
+	}
+	return filtered
+}
+
+// Calls fn for every index in [0, n) with at most bulkRoleAssignmentParallelism calls running at
+// once. Returns all of the errors joined together, if any.
+func runParallel(n int, fn func(i int) error) error {
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, bulkRoleAssignmentParallelism)
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+
+	var messages []string
+	for _, err := range errs {
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	if len(messages) > 0 {
+		return fmt.Errorf("%v", strings.Join(messages, "\n\n"))
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+func TestAccBulkRoleAssignmentResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// No principals fails
+			{
+				Config: providerConfig + `
+resource "tecton_bulk_role_assignment" "no_principals" {
+	workspace = "test"
+	roles     = ["viewer"]
+}
+`,
+				ExpectError: regexp.MustCompile("Missing Attribute Configuration"),
+			},
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "tecton_workspace" "tf_provider_acc_test_bulk" {
+	name = "tf-provider-acc-test-bulk"
+	live = false
+}
+
+resource "tecton_bulk_role_assignment" "on_call" {
+	workspace           = tecton_workspace.tf_provider_acc_test_bulk.name
+	roles               = ["viewer", "operator"]
+	service_account_ids = [var.tecton_service_account_no_existing_roles]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tecton_bulk_role_assignment.on_call", "id", "tf-provider-acc-test-bulk"),
+					resource.TestCheckResourceAttr("tecton_bulk_role_assignment.on_call", "roles.#", "2"),
+					resource.TestCheckResourceAttr("tecton_bulk_role_assignment.on_call", "service_account_ids.#", "1"),
+					resource.TestCheckResourceAttrSet("tecton_bulk_role_assignment.on_call", "last_updated"),
+				),
+			},
+			// Update roles
+			{
+				Config: providerConfig + `
+resource "tecton_workspace" "tf_provider_acc_test_bulk" {
+	name = "tf-provider-acc-test-bulk"
+	live = false
+}
+
+resource "tecton_bulk_role_assignment" "on_call" {
+	workspace           = tecton_workspace.tf_provider_acc_test_bulk.name
+	roles               = ["editor"]
+	service_account_ids = [var.tecton_service_account_no_existing_roles]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tecton_bulk_role_assignment.on_call", "roles.#", "1"),
+					resource.TestCheckResourceAttr("tecton_bulk_role_assignment.on_call", "roles.0", "editor"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestRunParallel(t *testing.T) {
+	results := make([]int, 20)
+	err := runParallel(len(results), func(i int) error {
+		results[i] = i * i
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, result := range results {
+		if result != i*i {
+			t.Errorf("expected results[%v] to be %v, got %v", i, i*i, result)
+		}
+	}
+
+	err = runParallel(3, func(i int) error {
+		if i == 1 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	if err == nil || err.Error() != "failed" {
+		t.Errorf("expected error 'failed', got: %v", err)
+	}
+}
+
+func TestBulkRoleAssignmentUpdate_skipsRedundantChanges(t *testing.T) {
+	// alice already has viewer and no longer has editor, so only bob's grant and alice's revocation
+	// of operator should run
+	fakeTectonCluster(t, `{
+		"workspaces": {"prod": {"live": true}},
+		"roles": {"user:alice": {"prod": ["viewer", "operator"]}},
+		"failures": {
+			"access-control assign-role --role viewer --workspace prod --user alice": "redundant grant",
+			"access-control unassign-role --role editor": "redundant revocation"
+		}
+	}`)
+	ctx := context.Background()
+	r := &bulkRoleAssignmentResource{CLI: tectonclient.Client{}}
+	state := bulkRoleAssignmentResourceModel{
+		Workspace: types.StringValue("prod"),
+		Roles:     []types.String{types.StringValue("editor"), types.StringValue("operator")},
+		UserIDs:   []types.String{types.StringValue("alice")},
+	}
+	plan := bulkRoleAssignmentResourceModel{
+		Workspace: types.StringValue("prod"),
+		Roles:     []types.String{types.StringValue("viewer")},
+		UserIDs:   []types.String{types.StringValue("alice"), types.StringValue("bob")},
+	}
+	err := r.UpdateRoleAssignment(ctx, &plan, &state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	granted, err := r.GetWorkspaceRoles(ctx, plan.Principals(), "prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, p := range plan.Principals() {
+		if len(granted[p]) != 1 || !granted[p]["viewer"] {
+			t.Errorf("expected %v to only have viewer, got: %v", p, granted[p])
+		}
+	}
+}
+
+func TestBulkRoleAssignmentUpdate_grantsRolesInheritedFromGroups(t *testing.T) {
+	calls := filepath.Join(t.TempDir(), "calls")
+	// alice only has viewer through a group, so it must still be granted to her directly
+	fakeTectonCLI(t, `echo "$@" >> `+calls+`
+case "$*" in *get-roles*)
+  echo '[{"resource_type": "WORKSPACE", "workspace_name": "prod", "roles_granted": [{"role": "viewer", "assignment_sources": [{"assignment_type": "ASSIGNMENT_TYPE_PRINCIPAL_GROUP", "principal_group_name": "data-team"}]}]}]'
+esac`)
+	ctx := context.Background()
+	r := &bulkRoleAssignmentResource{CLI: tectonclient.Client{}}
+	plan := bulkRoleAssignmentResourceModel{
+		Workspace: types.StringValue("prod"),
+		Roles:     []types.String{types.StringValue("viewer")},
+		UserIDs:   []types.String{types.StringValue("alice")},
+	}
+	err := r.UpdateRoleAssignment(ctx, &plan, &bulkRoleAssignmentResourceModel{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatalf("failed to read calls: %v", err)
+	}
+	if !strings.Contains(string(output), "access-control assign-role --role viewer --workspace prod --user alice") {
+		t.Errorf("expected viewer to be granted directly, got commands:\n%v", string(output))
+	}
+
+	complete, err := r.PrincipalsWithAllRoles(ctx, &plan)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if complete[tectonclient.Principal{UserID: "alice"}] {
+		t.Error("expected a role inherited from a group not to count as granted")
+	}
+}
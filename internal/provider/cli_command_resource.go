@@ -0,0 +1,310 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &cliCommandResource{}
+	_ resource.ResourceWithConfigure  = &cliCommandResource{}
+	_ resource.ResourceWithModifyPlan = &cliCommandResource{}
+)
+
+// NewCliCommandResource is a helper function to simplify the provider implementation.
+func NewCliCommandResource() resource.Resource {
+	return &cliCommandResource{}
+}
+
+// cliCommandResource is the resource implementation.
+type cliCommandResource struct {
+	CLI            tectonclient.Client
+	DisableDestroy bool
+}
+
+// cliCommandResourceModel maps the resource schema data.
+type cliCommandResourceModel struct {
+	ID             types.String            `tfsdk:"id"`
+	CreateArgs     []types.String          `tfsdk:"create_args"`
+	DestroyArgs    []types.String          `tfsdk:"destroy_args"`
+	CheckArgs      []types.String          `tfsdk:"check_args"`
+	Triggers       map[string]types.String `tfsdk:"triggers"`
+	Output         types.String            `tfsdk:"output"`
+	CommandTimeout types.String            `tfsdk:"command_timeout"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *cliCommandResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.CLI = providerData.CLI
+	r.DisableDestroy = providerData.DisableDestroy
+}
+
+// Metadata returns the resource type name.
+func (r *cliCommandResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cli_command"
+}
+
+// Schema defines the schema for the resource.
+func (r *cliCommandResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Runs arbitrary `tecton` subcommands with the provider's credentials when the resource is created and destroyed. " +
+			"This is an escape hatch for CLI features the provider doesn't model yet. Any change to the arguments replaces the resource.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier for this command. Generated when the resource is created.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"create_args": schema.ListAttribute{
+				Description: "The arguments passed to `tecton` when the resource is created, excluding the `tecton` executable itself. For example, [\"service-account\", \"create\", \"--name\", \"my-service\"].",
+				Required:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"destroy_args": schema.ListAttribute{
+				Description: "The arguments passed to `tecton` when the resource is destroyed. If not set, destroying the resource only removes it from the Terraform state.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"check_args": schema.ListAttribute{
+				Description: "The arguments passed to `tecton` to check whether `create_args` has already taken effect. " +
+					"If the check succeeds before the resource is created, `create_args` is not run. " +
+					"If the check fails when the resource is read, the resource is removed from the state so that it's created again.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				Description: "Arbitrary values that cause the resource to be replaced, and so the commands to be run again, when they change.",
+				Optional:    true,
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"command_timeout": schema.StringAttribute{
+				Description: "Overrides the provider's `command_timeout` for the create, destroy and check commands, e.g. for commands that are known to take a long time. " +
+					"Changing it doesn't replace the resource, but it must be applied before a destroy to take effect.",
+				Optional: true,
+				Validators: []validator.String{
+					durationValidator(),
+				},
+			},
+			"output": schema.StringAttribute{
+				Description: "The combined stdout and stderr of the create command. Empty if the command was skipped because the check succeeded.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *cliCommandResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_cli_command", "Create")
+	// Retrieve values from plan
+	var plan cliCommandResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Skip the command if it has already taken effect
+	alreadyApplied := false
+	if len(plan.CheckArgs) > 0 {
+		alreadyApplied = r.Check(ctx, plan.CheckArgs, plan.CommandTimeout)
+	}
+
+	plan.Output = types.StringValue("")
+	if alreadyApplied {
+		tflog.Info(ctx, fmt.Sprintf("Skipping 'tecton %v' since the check succeeded", tectonclient.ShellJoin(StringValues(plan.CreateArgs))))
+	} else {
+		output, err := r.RunChangeArgs(ctx, plan.CreateArgs, plan.CommandTimeout)
+		if err != nil {
+			AddCommandError(&resp.Diagnostics, "Tecton Command Failed", err)
+			return
+		}
+		plan.Output = types.StringValue(string(output))
+	}
+
+	// Generated computed values
+	plan.ID = types.StringValue(strconv.FormatInt(time.Now().UnixNano(), 10))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *cliCommandResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_cli_command", "Read")
+	// Get current state
+	var state cliCommandResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Without a check there's no way to tell whether the command is still in effect
+	if len(state.CheckArgs) == 0 {
+		return
+	}
+	if !r.Check(ctx, state.CheckArgs, state.CommandTimeout) {
+		tflog.Info(ctx, fmt.Sprintf("Check 'tecton %v' failed, so the command will be run again", tectonclient.ShellJoin(StringValues(state.CheckArgs))))
+		resp.State.RemoveResource(ctx)
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *cliCommandResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_cli_command", "Update")
+	// Every attribute requires replacement, so there's never anything to do in Tecton
+	var plan cliCommandResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// ModifyPlan fails plans that destroy the command if it has `destroy_args` and the provider's
+// `disable_destroy` is set.
+func (r *cliCommandResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_cli_command", "ModifyPlan")
+	if !req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+	var state cliCommandResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || len(state.DestroyArgs) == 0 {
+		return
+	}
+	CheckDestroyAllowed(r.DisableDestroy, fmt.Sprintf("tecton_cli_command '%v'", state.ID.ValueString()), &resp.Diagnostics)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *cliCommandResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_cli_command", "Delete")
+	// Get current state
+	var state cliCommandResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(state.DestroyArgs) == 0 {
+		return
+	}
+	if !CheckDestroyAllowed(r.DisableDestroy, fmt.Sprintf("tecton_cli_command '%v'", state.ID.ValueString()), &resp.Diagnostics) {
+		return
+	}
+	_, err := r.RunChangeArgs(ctx, state.DestroyArgs, state.CommandTimeout)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Tecton Command Failed", err)
+		return
+	}
+}
+
+// Runs `tecton` with the given arguments, returning an error containing the output on failure. If
+// timeout is set, it overrides the provider's command timeout.
+func (r *cliCommandResource) RunArgs(ctx context.Context, args []types.String, timeout types.String) ([]byte, error) {
+	stringArgs := StringValues(args)
+	tflog.Info(ctx, fmt.Sprintf("Running 'tecton %v'", tectonclient.ShellJoin(stringArgs)))
+	output, err := withCommandTimeout(r.CLI, timeout).Run(ctx, stringArgs...)
+	if err != nil {
+		return output, r.CLI.CommandError(ctx, "", stringArgs, output, err)
+	}
+	return output, nil
+}
+
+// Like RunArgs, but for the commands that change Tecton, which are only logged in dry-run mode.
+func (r *cliCommandResource) RunChangeArgs(ctx context.Context, args []types.String, timeout types.String) ([]byte, error) {
+	if r.CLI.SkipInDryRun(ctx, "tecton "+tectonclient.ShellJoin(StringValues(args))) {
+		return nil, nil
+	}
+	return r.RunArgs(ctx, args, timeout)
+}
+
+// Returns true if the check command exits successfully.
+func (r *cliCommandResource) Check(ctx context.Context, args []types.String, timeout types.String) bool {
+	_, err := r.RunArgs(ctx, args, timeout)
+	return err == nil
+}
+
+// Returns the string values of a list of Terraform strings.
+func StringValues(values []types.String) []string {
+	var strs []string
+	for _, value := range values {
+		strs = append(strs, value.ValueString())
+	}
+	return strs
+}
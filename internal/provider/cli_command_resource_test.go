@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccCliCommandResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "tecton_cli_command" "create_workspace" {
+	create_args  = ["workspace", "create", "tf-provider-acc-test-cli", "--no-live"]
+	destroy_args = ["workspace", "delete", "--yes", "tf-provider-acc-test-cli"]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("tecton_cli_command.create_workspace", "id"),
+					resource.TestCheckResourceAttrSet("tecton_cli_command.create_workspace", "output"),
+					resource.TestCheckResourceAttr("tecton_cli_command.create_workspace", "create_args.#", "4"),
+				),
+			},
+			// Invalid command fails
+			{
+				Config: providerConfig + `
+resource "tecton_cli_command" "invalid" {
+	create_args = ["not-a-command"]
+}
+`,
+				ExpectError: regexp.MustCompile("Tecton Command Failed"),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
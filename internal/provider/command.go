@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// The default for the provider's `command_timeout`.
+const defaultCommandTimeout = 10 * time.Minute
+
+// The default for the provider's `error_output_limit`.
+const defaultErrorOutputLimit = 10000
+
+// The default for each operation in the `timeouts` of resources that have them.
+const defaultOperationTimeout = 30 * time.Minute
+
+// The defaults for the provider's `lock_retry` block.
+var defaultLockRetry = tectonclient.LockRetry{
+	Timeout:      10 * time.Minute,
+	InitialDelay: 5 * time.Second,
+	MaxDelay:     time.Minute,
+}
+
+// LockRetryModel maps the provider's `lock_retry` block.
+type LockRetryModel struct {
+	Timeout      types.String `tfsdk:"timeout"`
+	InitialDelay types.String `tfsdk:"initial_delay"`
+	MaxDelay     types.String `tfsdk:"max_delay"`
+}
+
+// CommandRetryModel maps the provider's `retry` block.
+type CommandRetryModel struct {
+	MaxAttempts  types.Int64  `tfsdk:"max_attempts"`
+	InitialDelay types.String `tfsdk:"initial_delay"`
+	MaxDelay     types.String `tfsdk:"max_delay"`
+}
+
+// Matches Go duration strings such as "30s", "10m" or "1h30m".
+var durationRegex = regexp.MustCompile(`^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`)
+
+// Returns a validator for attributes that hold a Go duration string, e.g. a command timeout.
+func durationValidator() validator.String {
+	return stringvalidator.RegexMatches(durationRegex, `must be a Go duration string, e.g. "30s" or "10m"`)
+}
+
+// Returns a copy of ctx that's cancelled once timeout passes, e.g. a resource's create timeout from its
+// `timeouts`, so that the commands run with it are killed and fail with an error that names the
+// operation.
+func withOperationTimeout(ctx context.Context, operation string, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeoutCause(ctx, timeout, fmt.Errorf("%w: the %v timeout of %v in `timeouts` was exceeded", diagnostics.ErrTimedOut, operation, timeout))
+}
+
+// Returns the value of a `timeouts` attribute that isn't configured, e.g. for the resources returned by
+// list resources. Unlike the zero timeouts.Value, it has the attribute's type.
+func nullTimeouts() timeouts.Value {
+	return timeouts.Value{
+		Object: types.ObjectNull(map[string]attr.Type{
+			"create": types.StringType,
+			"read":   types.StringType,
+			"update": types.StringType,
+			"delete": types.StringType,
+		}),
+	}
+}
+
+// Returns a copy of cli that uses timeout, a Go duration string, instead of the provider's
+// `command_timeout`. Returns cli unchanged if timeout is null, unknown or invalid, since it's
+// validated in the schema.
+func withCommandTimeout(cli tectonclient.Client, timeout types.String) tectonclient.Client {
+	if timeout.IsNull() || timeout.IsUnknown() {
+		return cli
+	}
+	duration, err := time.ParseDuration(timeout.ValueString())
+	if err != nil {
+		return cli
+	}
+	return cli.WithTimeout(duration)
+}
+
+// Returns the retry policy for commands rejected because another plan or apply is in progress, with
+// the defaults for the attributes of model that aren't set. model may be nil.
+func lockRetryPolicy(model *LockRetryModel) tectonclient.LockRetry {
+	policy := defaultLockRetry
+	if model == nil {
+		return policy
+	}
+	for _, attribute := range []struct {
+		value    types.String
+		duration *time.Duration
+	}{
+		{model.Timeout, &policy.Timeout},
+		{model.InitialDelay, &policy.InitialDelay},
+		{model.MaxDelay, &policy.MaxDelay},
+	} {
+		if duration, err := time.ParseDuration(attribute.value.ValueString()); err == nil {
+			*attribute.duration = duration
+		}
+	}
+	return policy
+}
+
+// Returns the retry policy for commands that failed with a retriable error, with the defaults for
+// the attributes of model that aren't set, or nil for the client's default policy if model is nil.
+func commandRetryPolicy(model *CommandRetryModel) *tectonclient.CommandRetry {
+	if model == nil {
+		return nil
+	}
+	policy := tectonclient.DefaultCommandRetry()
+	if !model.MaxAttempts.IsNull() && !model.MaxAttempts.IsUnknown() {
+		policy.MaxAttempts = int(model.MaxAttempts.ValueInt64())
+	}
+	for _, attribute := range []struct {
+		value    types.String
+		duration *time.Duration
+	}{
+		{model.InitialDelay, &policy.InitialDelay},
+		{model.MaxDelay, &policy.MaxDelay},
+	} {
+		if duration, err := time.ParseDuration(attribute.value.ValueString()); err == nil {
+			*attribute.duration = duration
+		}
+	}
+	return &policy
+}
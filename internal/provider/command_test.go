@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+func TestWithTimeout(t *testing.T) {
+	testCases := map[string]struct {
+		timeout  types.String
+		expected time.Duration
+	}{
+		"null": {
+			timeout:  types.StringNull(),
+			expected: defaultCommandTimeout,
+		},
+		"override": {
+			timeout:  types.StringValue("1h30m"),
+			expected: 90 * time.Minute,
+		},
+		"disabled": {
+			timeout:  types.StringValue("0s"),
+			expected: 0,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cli := withCommandTimeout(tectonclient.Client{Timeout: defaultCommandTimeout}, testCase.timeout)
+			if cli.Timeout != testCase.expected {
+				t.Errorf("expected %v, got %v", testCase.expected, cli.Timeout)
+			}
+		})
+	}
+}
+
+func TestLockRetryPolicy(t *testing.T) {
+	if policy := lockRetryPolicy(nil); policy != defaultLockRetry {
+		t.Errorf("expected the defaults without a block, got %+v", policy)
+	}
+	policy := lockRetryPolicy(&LockRetryModel{
+		Timeout:      types.StringValue("0s"),
+		InitialDelay: types.StringNull(),
+		MaxDelay:     types.StringValue("30s"),
+	})
+	expected := tectonclient.LockRetry{Timeout: 0, InitialDelay: defaultLockRetry.InitialDelay, MaxDelay: 30 * time.Second}
+	if policy != expected {
+		t.Errorf("expected %+v, got %+v", expected, policy)
+	}
+}
+
+func TestCommandRetryPolicy(t *testing.T) {
+	if policy := commandRetryPolicy(nil); policy != nil {
+		t.Errorf("expected the client's default without a block, got %+v", policy)
+	}
+	policy := commandRetryPolicy(&CommandRetryModel{
+		MaxAttempts:  types.Int64Value(5),
+		InitialDelay: types.StringNull(),
+		MaxDelay:     types.StringValue("1m"),
+	})
+	expected := tectonclient.CommandRetry{MaxAttempts: 5, InitialDelay: tectonclient.DefaultCommandRetry().InitialDelay, MaxDelay: time.Minute}
+	if *policy != expected {
+		t.Errorf("expected %+v, got %+v", expected, *policy)
+	}
+}
+
+func TestWithOperationTimeout(t *testing.T) {
+	fakeTectonCLI(t, "exec sleep 5")
+	ctx, cancel := withOperationTimeout(context.Background(), "create", 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := tectonclient.Client{Timeout: defaultCommandTimeout}.Run(ctx, "workspace", "create", "prod")
+	if !errors.Is(err, diagnostics.ErrTimedOut) || !strings.Contains(err.Error(), "the create timeout of 100ms in `timeouts` was exceeded") {
+		t.Fatalf("expected the operation's timeout error, got: %v", err)
+	}
+	if time.Since(start) > 4*time.Second {
+		t.Errorf("expected the command to be killed after the timeout, took %v", time.Since(start))
+	}
+}
@@ -0,0 +1,449 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// The default for the provider's `key_expiry_warning_window`.
+const defaultKeyExpiryWarningWindow = 14 * 24 * time.Hour
+
+// credentialSourceValidator checks that exactly one source of credentials is configured: `api_key`,
+// `api_key_command`, `api_key_secret`, `credential_helper`, `oauth`, `okta`, or `use_cli_login` set to
+// true. Unlike
+// providervalidator.ExactlyOneOf, `use_cli_login = false` doesn't count as a source, and none may be
+// configured if the API key is set in the environment instead.
+type credentialSourceValidator struct{}
+
+func (v credentialSourceValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v credentialSourceValidator) MarkdownDescription(_ context.Context) string {
+	return "Exactly one of `api_key`, `api_key_command`, `api_key_secret`, `credential_helper`, `oauth`, `okta` and `use_cli_login` must be provided."
+}
+
+func (v credentialSourceValidator) ValidateProvider(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var apiKey, apiKeySecret types.String
+	var apiKeyCommand types.List
+	var credentialHelper, oauth, okta types.Object
+	var useCliLogin types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("api_key"), &apiKey)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("api_key_command"), &apiKeyCommand)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("api_key_secret"), &apiKeySecret)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("credential_helper"), &credentialHelper)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("oauth"), &oauth)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("okta"), &okta)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("use_cli_login"), &useCliLogin)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// Unknown values are checked once they're known
+	if apiKey.IsUnknown() || apiKeyCommand.IsUnknown() || apiKeySecret.IsUnknown() || credentialHelper.IsUnknown() || oauth.IsUnknown() || okta.IsUnknown() || useCliLogin.IsUnknown() {
+		return
+	}
+
+	var sources []string
+	if !apiKey.IsNull() {
+		sources = append(sources, "api_key")
+	}
+	if !apiKeyCommand.IsNull() {
+		sources = append(sources, "api_key_command")
+	}
+	if !apiKeySecret.IsNull() {
+		sources = append(sources, "api_key_secret")
+	}
+	if !credentialHelper.IsNull() {
+		sources = append(sources, "credential_helper")
+	}
+	if !oauth.IsNull() {
+		sources = append(sources, "oauth")
+	}
+	if !okta.IsNull() {
+		sources = append(sources, "okta")
+	}
+	if useCliLogin.ValueBool() {
+		sources = append(sources, "use_cli_login")
+	}
+	if len(sources) == 0 && os.Getenv(apiKeyEnvVar) == "" {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeInvalidConfig,
+			"Missing Credentials",
+			fmt.Sprintf("No credentials are configured and the %v environment variable isn't set. %v", apiKeyEnvVar, v.MarkdownDescription(ctx)),
+		)
+	} else if len(sources) > 1 {
+		AddAttributeError(
+			&resp.Diagnostics,
+			path.Root(sources[len(sources)-1]),
+			ErrorCodeInvalidConfig,
+			"Conflicting Credentials",
+			fmt.Sprintf("%v are all configured. %v", strings.Join(sources, ", "), v.MarkdownDescription(ctx)),
+		)
+	}
+}
+
+// CredentialHelperModel maps the `credential_helper` provider block. The provider's
+// `api_key_command` is run as a credential helper with PlainText set.
+type CredentialHelperModel struct {
+	Command []types.String `tfsdk:"command"`
+	// Whether the helper prints the bare API key rather than a JSON document, as `api_key_command`
+	// does. Not part of the schema.
+	PlainText bool `tfsdk:"-"`
+}
+
+// The JSON document a credential helper is expected to print to stdout.
+type credentialHelperOutput struct {
+	ApiKey string `json:"api_key"`
+	// When the API key expires, as an RFC 3339 timestamp. Optional.
+	Expiration *time.Time `json:"expiration"`
+}
+
+// Runs the configured credential helper and returns the API key it printed, along with when the key
+// expires if the helper reported it. Like AWS's `credential_process`, the helper must print a JSON
+// document of the form {"api_key": "...", "expiration": "2030-01-01T00:00:00Z"} to stdout, where
+// `expiration` is optional. If the helper is PlainText, its stdout without surrounding whitespace is
+// the API key instead. Anything written to stderr is only surfaced if the helper fails.
+func RunCredentialHelper(ctx context.Context, helper *CredentialHelperModel) (string, *time.Time, error) {
+	what := "credential helper"
+	if helper != nil && helper.PlainText {
+		what = "API key command"
+	}
+	if helper == nil || len(helper.Command) == 0 {
+		return "", nil, fmt.Errorf("The %v command must not be empty.", what)
+	}
+	stdout, err := runCredentialCommand(ctx, what, helper.Command)
+	if err != nil {
+		return "", nil, err
+	}
+	if helper.PlainText {
+		apiKey := strings.TrimSpace(string(stdout))
+		if apiKey == "" {
+			return "", nil, fmt.Errorf("API key command '%v' printed nothing.", helper.Command[0].ValueString())
+		}
+		return apiKey, nil, nil
+	}
+
+	// The helper output contains the secret, so it is never included in errors.
+	var output credentialHelperOutput
+	err = json.Unmarshal(stdout, &output)
+	if err != nil {
+		return "", nil, fmt.Errorf(
+			"Failed to parse output of credential helper '%v'. Expected a JSON object of the form {\"api_key\": \"...\"}.",
+			helper.Command[0].ValueString(),
+		)
+	}
+	apiKey := strings.TrimSpace(output.ApiKey)
+	if apiKey == "" {
+		return "", nil, fmt.Errorf("Credential helper '%v' returned an empty `api_key`.", helper.Command[0].ValueString())
+	}
+	return apiKey, output.Expiration, nil
+}
+
+// Runs a command that prints credentials, e.g. the credential helper, and returns its stdout. what
+// describes the command in logs and errors.
+func runCredentialCommand(ctx context.Context, what string, command []types.String) ([]byte, error) {
+	var args []string
+	for _, arg := range command {
+		args = append(args, arg.ValueString())
+	}
+
+	// Only the executable name is logged since arguments may contain secret paths or tokens.
+	tflog.Info(ctx, fmt.Sprintf("Running %v '%v'", what, args[0]))
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = os.Environ()
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%v '%v' failed.\nError: %v\nStderr: %v",
+			strings.ToUpper(what[:1])+what[1:],
+			args[0],
+			err.Error(),
+			stderr.String(),
+		)
+	}
+	return stdout.Bytes(), nil
+}
+
+// OAuthModel maps the `oauth` provider block.
+type OAuthModel struct {
+	ClientID     types.String   `tfsdk:"client_id"`
+	ClientSecret types.String   `tfsdk:"client_secret"`
+	TokenURL     types.String   `tfsdk:"token_url"`
+	Scopes       []types.String `tfsdk:"scopes"`
+}
+
+// How long the request for an OAuth access token may take.
+const oauthTokenTimeout = 30 * time.Second
+
+// The response of an OAuth token endpoint, which is either a token or an error.
+type oauthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	// The lifetime of the token in seconds. Optional.
+	ExpiresIn int64 `json:"expires_in"`
+	// A new refresh token, if the authorization server rotates them. Optional.
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Exchanges OAuth client credentials for an access token with the client credentials grant, and
+// returns the token along with when it expires if the token endpoint reported it. The client
+// authenticates with HTTP basic authentication, as RFC 6749 recommends.
+func FetchOAuthToken(ctx context.Context, config *OAuthModel, client *http.Client) (string, *time.Time, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	var scopes []string
+	for _, scope := range config.Scopes {
+		scopes = append(scopes, scope.ValueString())
+	}
+	if len(scopes) > 0 {
+		form.Set("scope", strings.Join(scopes, " "))
+	}
+	token, expiresAt, err := requestOAuthToken(ctx, client, config.TokenURL.ValueString(), form, func(req *http.Request) {
+		req.SetBasicAuth(url.QueryEscape(config.ClientID.ValueString()), url.QueryEscape(config.ClientSecret.ValueString()))
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return token.AccessToken, expiresAt, nil
+}
+
+// Posts form to an OAuth token endpoint, after authenticate adds the client's credentials to the
+// request if it has any, and returns the token along with when it expires if the endpoint reported
+// it.
+func requestOAuthToken(ctx context.Context, client *http.Client, tokenURL string, form url.Values, authenticate func(*http.Request)) (oauthTokenResponse, *time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oauthTokenResponse{}, nil, fmt.Errorf("Invalid token URL '%v': %w", tokenURL, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if authenticate != nil {
+		authenticate(req)
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Requesting an OAuth access token from '%v'", tokenURL))
+	start := time.Now()
+	httpResp, err := client.Do(req)
+	if err != nil {
+		return oauthTokenResponse{}, nil, fmt.Errorf("Failed to request an OAuth access token from '%v': %w", tokenURL, err)
+	}
+	defer httpResp.Body.Close()
+
+	// The response contains the token, so only its error fields are ever included in errors.
+	var token oauthTokenResponse
+	decodeErr := json.NewDecoder(httpResp.Body).Decode(&token)
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		detail := ""
+		if token.Error != "" {
+			detail = fmt.Sprintf(": %v %v", token.Error, token.ErrorDescription)
+		}
+		return oauthTokenResponse{}, nil, fmt.Errorf("The token endpoint '%v' returned %v%v", tokenURL, httpResp.Status, strings.TrimRight(detail, " "))
+	}
+	if decodeErr != nil {
+		return oauthTokenResponse{}, nil, fmt.Errorf("Failed to parse the response of the token endpoint '%v'. Expected a JSON object with an `access_token`.", tokenURL)
+	}
+	if token.AccessToken == "" {
+		return oauthTokenResponse{}, nil, fmt.Errorf("The token endpoint '%v' returned no `access_token`.", tokenURL)
+	}
+	if token.ExpiresIn <= 0 {
+		return token, nil, nil
+	}
+	expiresAt := start.Add(time.Duration(token.ExpiresIn) * time.Second)
+	return token, &expiresAt, nil
+}
+
+// OktaModel maps the `okta` provider block.
+type OktaModel struct {
+	IssuerURL    types.String `tfsdk:"issuer_url"`
+	ClientID     types.String `tfsdk:"client_id"`
+	RefreshToken types.String `tfsdk:"refresh_token"`
+}
+
+// oktaTokenSource refreshes Okta access tokens with a refresh token from `tecton login`, the way the
+// CLI's own session is refreshed. It's safe for concurrent use.
+type oktaTokenSource struct {
+	tokenURL string
+	clientID string
+	client   *http.Client
+
+	mu sync.Mutex
+	// The latest refresh token, which changes if Okta rotates refresh tokens.
+	refreshToken string
+}
+
+// Returns a token source for the configured Okta authorization server, which sends its requests with
+// client.
+func newOktaTokenSource(config *OktaModel, client *http.Client) *oktaTokenSource {
+	return &oktaTokenSource{
+		tokenURL:     strings.TrimSuffix(config.IssuerURL.ValueString(), "/") + "/v1/token",
+		clientID:     config.ClientID.ValueString(),
+		client:       client,
+		refreshToken: config.RefreshToken.ValueString(),
+	}
+}
+
+// Returns a new access token along with when it expires, which is assumed to be in an hour, Okta's
+// default, if Okta doesn't report it. Used as tectonclient.OAuthSession's FetchToken.
+func (s *oktaTokenSource) FetchToken(ctx context.Context) (string, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {s.refreshToken},
+		"client_id":     {s.clientID},
+	}
+	token, expiresAt, err := requestOAuthToken(ctx, s.client, s.tokenURL, form, nil)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("Failed to refresh the Okta session, so run `tecton login` again for a new refresh token: %w", err)
+	}
+	if token.RefreshToken != "" {
+		s.refreshToken = token.RefreshToken
+	}
+	if expiresAt == nil {
+		return token.AccessToken, time.Now().Add(time.Hour), nil
+	}
+	return token.AccessToken, *expiresAt, nil
+}
+
+// A reference to a secret in a cloud secret manager, resolved into the command that reads it.
+type secretReference struct {
+	// The CLI used to read the secret (e.g. "aws" or "gcloud").
+	Executable string
+	Args       []string
+}
+
+var (
+	awsSecretsManagerArnRegex = regexp.MustCompile(`^arn:aws[a-z-]*:secretsmanager:([a-z0-9-]+):[0-9]+:secret:.+$`)
+	awsSsmArnRegex            = regexp.MustCompile(`^arn:aws[a-z-]*:ssm:([a-z0-9-]+):[0-9]+:parameter(/.+)$`)
+	gcpSecretNameRegex        = regexp.MustCompile(`^projects/([^/]+)/secrets/([^/]+)(?:/versions/([^/]+))?$`)
+)
+
+// Parses `api_key_secret` into the command that reads the secret. Supported formats are
+//   - AWS Secrets Manager ARNs: arn:aws:secretsmanager:<region>:<account>:secret:<name>
+//   - AWS SSM parameter ARNs:   arn:aws:ssm:<region>:<account>:parameter/<name>
+//   - GCP Secret Manager names: projects/<project>/secrets/<name>[/versions/<version>]
+func parseSecretReference(secret string) (secretReference, error) {
+	if matches := awsSecretsManagerArnRegex.FindStringSubmatch(secret); matches != nil {
+		return secretReference{
+			Executable: "aws",
+			Args: []string{
+				"secretsmanager", "get-secret-value",
+				"--secret-id", secret,
+				"--region", matches[1],
+				"--query", "SecretString",
+				"--output", "text",
+			},
+		}, nil
+	}
+	if matches := awsSsmArnRegex.FindStringSubmatch(secret); matches != nil {
+		return secretReference{
+			Executable: "aws",
+			Args: []string{
+				"ssm", "get-parameter",
+				"--name", matches[2],
+				"--with-decryption",
+				"--region", matches[1],
+				"--query", "Parameter.Value",
+				"--output", "text",
+			},
+		}, nil
+	}
+	if matches := gcpSecretNameRegex.FindStringSubmatch(secret); matches != nil {
+		version := matches[3]
+		if version == "" {
+			version = "latest"
+		}
+		return secretReference{
+			Executable: "gcloud",
+			Args: []string{
+				"secrets", "versions", "access", version,
+				"--secret", matches[2],
+				"--project", matches[1],
+			},
+		}, nil
+	}
+	return secretReference{}, fmt.Errorf(
+		"Unsupported secret reference '%v'. Expected an AWS Secrets Manager ARN, an AWS SSM parameter ARN, "+
+			"or a GCP Secret Manager name of the form projects/<project>/secrets/<name>[/versions/<version>].",
+		secret,
+	)
+}
+
+// Reads the API key from a cloud secret manager using the `aws` or `gcloud` CLI, which pick up
+// credentials from the environment the same way they do when run by hand.
+func FetchSecretApiKey(ctx context.Context, secret string) (string, error) {
+	ref, err := parseSecretReference(secret)
+	if err != nil {
+		return "", err
+	}
+	_, err = exec.LookPath(ref.Executable)
+	if err != nil {
+		return "", fmt.Errorf("Didn't find '%v' executable, which is required to read the secret '%v'.", ref.Executable, secret)
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Reading API key from secret '%v'", secret))
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, ref.Executable, ref.Args...)
+	cmd.Env = os.Environ()
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf(
+			"Command to read secret '%v' failed.\nError: %v\nStderr: %v",
+			secret,
+			err.Error(),
+			stderr.String(),
+		)
+	}
+
+	apiKey := strings.TrimSpace(stdout.String())
+	if apiKey == "" {
+		return "", fmt.Errorf("Secret '%v' is empty.", secret)
+	}
+	return apiKey, nil
+}
+
+// Adds a warning to diags if an API key expires within window of now, or already expired, so that it's
+// rotated before commands start failing. what describes the key, e.g. "The provider's API key". A zero
+// window disables the warning.
+func CheckKeyExpiration(what string, expiresAt time.Time, now time.Time, window time.Duration, diags *diag.Diagnostics) {
+	if window <= 0 || expiresAt.Sub(now) > window {
+		return
+	}
+	when := fmt.Sprintf("expired at %v", expiresAt.UTC().Format(time.RFC3339))
+	if expiresAt.After(now) {
+		when = fmt.Sprintf("expires in %v, at %v", expiresAt.Sub(now).Round(time.Minute), expiresAt.UTC().Format(time.RFC3339))
+	}
+	AddWarning(
+		diags,
+		ErrorCodeKeyExpiring,
+		"Tecton API key is expiring",
+		fmt.Sprintf(
+			"%v %v. Rotate it before it expires, since every command run with it will then fail. "+
+				"The warning is reported within the provider's `key_expiry_warning_window` of the expiration.",
+			what,
+			when,
+		),
+	)
+}
@@ -0,0 +1,403 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func credentialHelper(args ...string) *CredentialHelperModel {
+	helper := &CredentialHelperModel{}
+	for _, arg := range args {
+		helper.Command = append(helper.Command, types.StringValue(arg))
+	}
+	return helper
+}
+
+func TestRunCredentialHelper(t *testing.T) {
+	apiKey, expiresAt, err := RunCredentialHelper(context.Background(), credentialHelper("sh", "-c", `echo '{"api_key": "abc123"}'`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if apiKey != "abc123" {
+		t.Errorf("expected api key 'abc123', got '%v'", apiKey)
+	}
+	if expiresAt != nil {
+		t.Errorf("expected no expiration, got %v", expiresAt)
+	}
+}
+
+func TestRunCredentialHelper_expiration(t *testing.T) {
+	_, expiresAt, err := RunCredentialHelper(context.Background(), credentialHelper("sh", "-c", `echo '{"api_key": "abc123", "expiration": "2030-01-02T03:04:05Z"}'`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	if expiresAt == nil || !expiresAt.Equal(expected) {
+		t.Errorf("expected expiration %v, got %v", expected, expiresAt)
+	}
+}
+
+func apiKeyCommand(args ...string) *CredentialHelperModel {
+	helper := credentialHelper(args...)
+	helper.PlainText = true
+	return helper
+}
+
+func TestRunCredentialHelper_plainText(t *testing.T) {
+	apiKey, expiresAt, err := RunCredentialHelper(context.Background(), apiKeyCommand("sh", "-c", "echo '  abc123  '"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if apiKey != "abc123" || expiresAt != nil {
+		t.Errorf("expected api key 'abc123' without an expiration, got '%v', %v", apiKey, expiresAt)
+	}
+
+	testCases := map[string]struct {
+		command  []string
+		expected string
+	}{
+		"fails":  {command: []string{"sh", "-c", "echo 'permission denied' >&2; exit 1"}, expected: "API key command 'sh' failed.\nError: exit status 1\nStderr: permission denied"},
+		"empty":  {command: []string{"sh", "-c", "echo"}, expected: "API key command 'sh' printed nothing."},
+		"absent": {command: []string{"does-not-exist"}, expected: "API key command 'does-not-exist' failed."},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := RunCredentialHelper(context.Background(), apiKeyCommand(testCase.command...))
+			if err == nil || !strings.Contains(err.Error(), testCase.expected) {
+				t.Errorf("expected error containing %q, got: %v", testCase.expected, err)
+			}
+		})
+	}
+}
+
+func TestCheckKeyExpiration(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := map[string]struct {
+		expiresAt time.Time
+		window    time.Duration
+		expected  string
+	}{
+		"outside window": {expiresAt: now.Add(30 * 24 * time.Hour), window: defaultKeyExpiryWarningWindow},
+		"within window":  {expiresAt: now.Add(48 * time.Hour), window: defaultKeyExpiryWarningWindow, expected: "expires in 48h0m0s, at 2030-01-03T00:00:00Z"},
+		"expired":        {expiresAt: now.Add(-time.Hour), window: defaultKeyExpiryWarningWindow, expected: "expired at 2029-12-31T23:00:00Z"},
+		"disabled":       {expiresAt: now.Add(-time.Hour)},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			CheckKeyExpiration("The key", testCase.expiresAt, now, testCase.window, &diags)
+			if testCase.expected == "" {
+				if len(diags) > 0 {
+					t.Errorf("expected no warning, got %v", diags)
+				}
+				return
+			}
+			if len(diags.Warnings()) != 1 || !strings.Contains(diags.Warnings()[0].Detail(), testCase.expected) {
+				t.Errorf("expected a warning containing '%v', got %v", testCase.expected, diags)
+			}
+		})
+	}
+}
+
+func TestRunCredentialHelper_errors(t *testing.T) {
+	testCases := map[string]struct {
+		helper        *CredentialHelperModel
+		expectedError string
+	}{
+		"empty command": {
+			helper:        credentialHelper(),
+			expectedError: "must not be empty",
+		},
+		"command fails": {
+			helper:        credentialHelper("sh", "-c", "echo 'vault is sealed' >&2; exit 1"),
+			expectedError: "vault is sealed",
+		},
+		"invalid json": {
+			helper:        credentialHelper("sh", "-c", "echo abc123"),
+			expectedError: "Failed to parse output",
+		},
+		"empty api key": {
+			helper:        credentialHelper("sh", "-c", `echo '{"api_key": ""}'`),
+			expectedError: "empty `api_key`",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := RunCredentialHelper(context.Background(), testCase.helper)
+			if err == nil {
+				t.Fatal("expected an error, got none")
+			}
+			if !strings.Contains(err.Error(), testCase.expectedError) {
+				t.Errorf("expected error containing '%v', got: %v", testCase.expectedError, err)
+			}
+			if strings.Contains(err.Error(), "abc123") {
+				t.Errorf("error must not contain the helper's stdout, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseSecretReference(t *testing.T) {
+	testCases := map[string]struct {
+		secret       string
+		expectedArgs []string
+	}{
+		"secrets manager": {
+			secret:       "arn:aws:secretsmanager:us-east-1:123456789012:secret:tecton-api-key-AbCdEf",
+			expectedArgs: []string{"aws", "secretsmanager", "get-secret-value", "--secret-id", "arn:aws:secretsmanager:us-east-1:123456789012:secret:tecton-api-key-AbCdEf", "--region", "us-east-1", "--query", "SecretString", "--output", "text"},
+		},
+		"ssm": {
+			secret:       "arn:aws:ssm:us-west-2:123456789012:parameter/tecton/api-key",
+			expectedArgs: []string{"aws", "ssm", "get-parameter", "--name", "/tecton/api-key", "--with-decryption", "--region", "us-west-2", "--query", "Parameter.Value", "--output", "text"},
+		},
+		"gcp latest": {
+			secret:       "projects/my-project/secrets/tecton-api-key",
+			expectedArgs: []string{"gcloud", "secrets", "versions", "access", "latest", "--secret", "tecton-api-key", "--project", "my-project"},
+		},
+		"gcp version": {
+			secret:       "projects/my-project/secrets/tecton-api-key/versions/3",
+			expectedArgs: []string{"gcloud", "secrets", "versions", "access", "3", "--secret", "tecton-api-key", "--project", "my-project"},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ref, err := parseSecretReference(testCase.secret)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			args := append([]string{ref.Executable}, ref.Args...)
+			if strings.Join(args, " ") != strings.Join(testCase.expectedArgs, " ") {
+				t.Errorf("expected command '%v', got '%v'", strings.Join(testCase.expectedArgs, " "), strings.Join(args, " "))
+			}
+		})
+	}
+
+	_, err := parseSecretReference("vault:secret/tecton")
+	if err == nil {
+		t.Error("expected an error for an unsupported secret reference, got none")
+	}
+}
+
+func TestCredentialSourceValidator(t *testing.T) {
+	oauthType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"client_id":     tftypes.String,
+		"client_secret": tftypes.String,
+		"token_url":     tftypes.String,
+		"scopes":        tftypes.List{ElementType: tftypes.String},
+	}}
+	testCases := map[string]struct {
+		values map[string]tftypes.Value
+		// The value of the TECTON_API_KEY environment variable
+		env   string
+		valid bool
+	}{
+		"api key": {
+			values: map[string]tftypes.Value{"api_key": tftypes.NewValue(tftypes.String, "abc")},
+			valid:  true,
+		},
+		"cli login": {
+			values: map[string]tftypes.Value{"use_cli_login": tftypes.NewValue(tftypes.Bool, true)},
+			valid:  true,
+		},
+		"api key with cli login disabled": {
+			values: map[string]tftypes.Value{
+				"api_key":       tftypes.NewValue(tftypes.String, "abc"),
+				"use_cli_login": tftypes.NewValue(tftypes.Bool, false),
+			},
+			valid: true,
+		},
+		"unknown api key": {
+			values: map[string]tftypes.Value{"api_key": tftypes.NewValue(tftypes.String, tftypes.UnknownValue)},
+			valid:  true,
+		},
+		"none": {
+			values: map[string]tftypes.Value{"use_cli_login": tftypes.NewValue(tftypes.Bool, false)},
+			valid:  false,
+		},
+		"api key command": {
+			values: map[string]tftypes.Value{"api_key_command": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "vault")})},
+			valid:  true,
+		},
+		"oauth": {
+			values: map[string]tftypes.Value{"oauth": tftypes.NewValue(oauthType, map[string]tftypes.Value{
+				"client_id":     tftypes.NewValue(tftypes.String, "id"),
+				"client_secret": tftypes.NewValue(tftypes.String, "secret"),
+				"token_url":     tftypes.NewValue(tftypes.String, "https://idp.example.com/token"),
+				"scopes":        tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+			})},
+			valid: true,
+		},
+		"api key and oauth": {
+			values: map[string]tftypes.Value{
+				"api_key": tftypes.NewValue(tftypes.String, "abc"),
+				"oauth": tftypes.NewValue(oauthType, map[string]tftypes.Value{
+					"client_id":     tftypes.NewValue(tftypes.String, "id"),
+					"client_secret": tftypes.NewValue(tftypes.String, "secret"),
+					"token_url":     tftypes.NewValue(tftypes.String, "https://idp.example.com/token"),
+					"scopes":        tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+				}),
+			},
+			valid: false,
+		},
+		"okta": {
+			values: map[string]tftypes.Value{"okta": tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"issuer_url":    tftypes.String,
+				"client_id":     tftypes.String,
+				"refresh_token": tftypes.String,
+			}}, map[string]tftypes.Value{
+				"issuer_url":    tftypes.NewValue(tftypes.String, "https://example.okta.com/oauth2/default"),
+				"client_id":     tftypes.NewValue(tftypes.String, "tecton-cli"),
+				"refresh_token": tftypes.NewValue(tftypes.String, "refresh"),
+			})},
+			valid: true,
+		},
+		"none with api key in environment": {
+			values: map[string]tftypes.Value{},
+			env:    "abc",
+			valid:  true,
+		},
+		"api key and cli login": {
+			values: map[string]tftypes.Value{
+				"api_key":       tftypes.NewValue(tftypes.String, "abc"),
+				"use_cli_login": tftypes.NewValue(tftypes.Bool, true),
+			},
+			valid: false,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(apiKeyEnvVar, testCase.env)
+			ctx := context.Background()
+			var schemaResp provider.SchemaResponse
+			(&TectonProvider{}).Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+			objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+			attributes := map[string]tftypes.Value{}
+			for attributeName, attributeType := range objectType.AttributeTypes {
+				attributes[attributeName] = tftypes.NewValue(attributeType, nil)
+			}
+			for attributeName, value := range testCase.values {
+				attributes[attributeName] = value
+			}
+
+			resp := provider.ValidateConfigResponse{}
+			credentialSourceValidator{}.ValidateProvider(ctx, provider.ValidateConfigRequest{
+				Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, attributes)},
+			}, &resp)
+			if resp.Diagnostics.HasError() == testCase.valid {
+				t.Errorf("expected valid=%v, got: %v", testCase.valid, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestFetchOAuthToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, _ := r.BasicAuth()
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("failed to parse form: %v", err)
+		}
+		if clientID != "pipeline" || clientSecret != "s3cr%2Ft" || r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("scope") != "tecton read" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": "invalid_client", "error_description": "Client authentication failed"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token": "token123", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+
+	config := &OAuthModel{
+		ClientID:     types.StringValue("pipeline"),
+		ClientSecret: types.StringValue("s3cr/t"),
+		TokenURL:     types.StringValue(server.URL),
+		Scopes:       []types.String{types.StringValue("tecton"), types.StringValue("read")},
+	}
+	start := time.Now()
+	token, expiresAt, err := FetchOAuthToken(context.Background(), config, server.Client())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "token123" {
+		t.Errorf("expected token123, got %v", token)
+	}
+	if expiresAt == nil || expiresAt.Before(start.Add(time.Hour)) || expiresAt.After(time.Now().Add(time.Hour)) {
+		t.Errorf("expected the token to expire in an hour, got %v", expiresAt)
+	}
+
+	config.ClientSecret = types.StringValue("wrong")
+	_, _, err = FetchOAuthToken(context.Background(), config, server.Client())
+	if err == nil || !strings.Contains(err.Error(), "401 Unauthorized: invalid_client Client authentication failed") {
+		t.Errorf("expected the token endpoint's error, got: %v", err)
+	}
+}
+
+func TestFetchOAuthToken_missingToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"token_type": "Bearer"}`))
+	}))
+	defer server.Close()
+
+	config := &OAuthModel{ClientID: types.StringValue("id"), ClientSecret: types.StringValue("secret"), TokenURL: types.StringValue(server.URL)}
+	_, _, err := FetchOAuthToken(context.Background(), config, server.Client())
+	if err == nil || !strings.Contains(err.Error(), "returned no `access_token`") {
+		t.Errorf("expected an error, got: %v", err)
+	}
+}
+
+func TestOktaTokenSource(t *testing.T) {
+	var refreshTokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("failed to parse form: %v", err)
+		}
+		if r.URL.Path != "/oauth2/default/v1/token" || r.PostForm.Get("grant_type") != "refresh_token" || r.PostForm.Get("client_id") != "tecton-cli" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "invalid_request"}`))
+			return
+		}
+		refreshTokens = append(refreshTokens, r.PostForm.Get("refresh_token"))
+		if r.PostForm.Get("refresh_token") == "revoked" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "invalid_grant", "error_description": "The refresh token is invalid or expired."}`))
+			return
+		}
+		// Okta rotates the refresh token on every refresh
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"access_token": "access%v", "expires_in": 3600, "refresh_token": "refresh%v"}`, len(refreshTokens), len(refreshTokens))))
+	}))
+	defer server.Close()
+
+	source := newOktaTokenSource(&OktaModel{
+		IssuerURL:    types.StringValue(server.URL + "/oauth2/default/"),
+		ClientID:     types.StringValue("tecton-cli"),
+		RefreshToken: types.StringValue("refresh0"),
+	}, server.Client())
+	for i := 1; i <= 2; i++ {
+		token, expiresAt, err := source.FetchToken(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if token != fmt.Sprintf("access%v", i) || time.Until(expiresAt) < 59*time.Minute {
+			t.Errorf("expected a fresh token, got %v expiring at %v", token, expiresAt)
+		}
+	}
+	if expected := []string{"refresh0", "refresh1"}; !slices.Equal(refreshTokens, expected) {
+		t.Errorf("expected the rotated refresh token to be used, got %v", refreshTokens)
+	}
+
+	source.refreshToken = "revoked"
+	_, _, err := source.FetchToken(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "run `tecton login` again") || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("expected a refresh error, got: %v", err)
+	}
+}
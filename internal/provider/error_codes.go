@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+)
+
+// ErrorCode is a stable, machine-readable identifier attached to every error diagnostic produced by
+// the provider, so that automation can match on failures without parsing prose. Codes must never be
+// renamed once released.
+type ErrorCode string
+
+const (
+	ErrorCodeCliNotInstalled        ErrorCode = "TECTON_CLI_NOT_INSTALLED"
+	ErrorCodeInvalidConfig          ErrorCode = "TECTON_INVALID_CONFIG"
+	ErrorCodeCredentialsUnavailable ErrorCode = "TECTON_CREDENTIALS_UNAVAILABLE"
+	ErrorCodeAuthFailed             ErrorCode = "TECTON_AUTH_FAILED"
+	ErrorCodePermissionDenied       ErrorCode = "TECTON_PERMISSION_DENIED"
+	ErrorCodeWorkspaceNotFound      ErrorCode = "TECTON_WORKSPACE_NOT_FOUND"
+	ErrorCodePrincipalNotFound      ErrorCode = "TECTON_PRINCIPAL_NOT_FOUND"
+	ErrorCodeAlreadyExists          ErrorCode = "TECTON_ALREADY_EXISTS"
+	ErrorCodeWorkspaceLocked        ErrorCode = "TECTON_WORKSPACE_LOCKED"
+	ErrorCodeRateLimited            ErrorCode = "TECTON_RATE_LIMITED"
+	ErrorCodeUnsupportedChange      ErrorCode = "TECTON_UNSUPPORTED_CHANGE"
+	ErrorCodeUnsafeOperation        ErrorCode = "TECTON_UNSAFE_OPERATION"
+	ErrorCodePolicyViolation        ErrorCode = "TECTON_POLICY_VIOLATION"
+	ErrorCodeUnexpectedOutput       ErrorCode = "TECTON_UNEXPECTED_OUTPUT"
+	ErrorCodeVersionMismatch        ErrorCode = "TECTON_VERSION_MISMATCH"
+	ErrorCodeCommandFailed          ErrorCode = "TECTON_COMMAND_FAILED"
+	ErrorCodeCommandTimedOut        ErrorCode = "TECTON_COMMAND_TIMED_OUT"
+	ErrorCodeInteractivePrompt      ErrorCode = "TECTON_INTERACTIVE_PROMPT"
+	ErrorCodeNotificationFailed     ErrorCode = "TECTON_NOTIFICATION_FAILED"
+	ErrorCodeUnsupportedFeature     ErrorCode = "TECTON_UNSUPPORTED_FEATURE"
+	ErrorCodePrincipalDeactivated   ErrorCode = "TECTON_PRINCIPAL_DEACTIVATED"
+	ErrorCodeUnknownResourceType    ErrorCode = "TECTON_UNKNOWN_RESOURCE_TYPE"
+	ErrorCodeInsecureConnection     ErrorCode = "TECTON_INSECURE_CONNECTION"
+	ErrorCodeCliDeprecation         ErrorCode = "TECTON_CLI_DEPRECATION"
+	ErrorCodeKeyExpiring            ErrorCode = "TECTON_KEY_EXPIRING"
+	ErrorCodeDryRun                 ErrorCode = "TECTON_DRY_RUN"
+	ErrorCodeProviderBug            ErrorCode = "TECTON_PROVIDER_BUG"
+)
+
+// Patterns in the output of failed commands and classes of their output, checked in order. The first
+// match determines the code. An entry matches if its Pattern matches or, if it has no Pattern, if the
+// output is of its Class.
+var errorCodePatterns = []struct {
+	Pattern *regexp.Regexp
+	Class   diagnostics.FailureClass
+	Code    ErrorCode
+}{
+	{Pattern: regexp.MustCompile(`Command timed out after \d`), Code: ErrorCodeCommandTimedOut},
+	{Pattern: regexp.MustCompile(`Command waited for input`), Code: ErrorCodeInteractivePrompt},
+	{Pattern: regexp.MustCompile(`not supported on your cluster version`), Code: ErrorCodeUnsupportedFeature},
+	{Pattern: regexp.MustCompile(`only supported with tecton \d`), Code: ErrorCodeVersionMismatch},
+	{Pattern: regexp.MustCompile(`(?i)(another (plan|apply|operation) is (already )?(in progress|running)|workspace is (currently )?locked|(held|locked) by another (plan|apply|operation))`), Code: ErrorCodeWorkspaceLocked},
+	{Class: diagnostics.FailureThrottled, Code: ErrorCodeRateLimited},
+	{Class: diagnostics.FailureAuthentication, Code: ErrorCodeAuthFailed},
+	{Class: diagnostics.FailurePermissionDenied, Code: ErrorCodePermissionDenied},
+	{Class: diagnostics.FailureWorkspaceNotFound, Code: ErrorCodeWorkspaceNotFound},
+	{Class: diagnostics.FailurePrincipalNotFound, Code: ErrorCodePrincipalNotFound},
+	{Class: diagnostics.FailureAlreadyExists, Code: ErrorCodeAlreadyExists},
+	{Pattern: regexp.MustCompile(`(?i)failed to parse|unexpected output`), Code: ErrorCodeUnexpectedOutput},
+}
+
+// What to do about the failures with each code, which is added to the diagnostics of failed commands.
+var errorCodeAdvice = map[ErrorCode]string{
+	ErrorCodeAuthFailed: "Tecton rejected the provider's credentials. Check that the API key is valid, hasn't expired or been revoked, " +
+		"and belongs to the cluster at the provider's `url`.",
+	ErrorCodePermissionDenied: "The provider's account isn't allowed to make this change. Grant it a role that is, e.g. owner of the workspace, " +
+		"or admin for roles on all workspaces.",
+	ErrorCodeWorkspaceNotFound: "The workspace doesn't exist in the cluster at the provider's `url`. Check its name, or create it first, " +
+		"e.g. with a `tecton_workspace` resource that this resource depends on.",
+	ErrorCodePrincipalNotFound: "The user or service account doesn't exist in the cluster. Check the user ID (e.g. email) or service account ID, " +
+		"and that the account wasn't deleted.",
+	ErrorCodeRateLimited: "Tecton rejected the request because the provider made too many at once, even after retrying as configured by the provider's `retry`. " +
+		"Set the provider's `max_concurrent_operations` to run fewer commands at once, or run Terraform with a lower `-parallelism`.",
+}
+
+// Derives the error code for a failed command from its error, which includes the command output.
+func ClassifyError(err error) ErrorCode {
+	if err == nil {
+		return ErrorCodeCommandFailed
+	}
+	var parseErr *diagnostics.ParseError
+	var schemaErr *diagnostics.SchemaError
+	if errors.Is(err, diagnostics.ErrTimedOut) {
+		return ErrorCodeCommandTimedOut
+	} else if errors.Is(err, diagnostics.ErrWaitingForInput) {
+		return ErrorCodeInteractivePrompt
+	} else if errors.As(err, &schemaErr) {
+		return ErrorCodeVersionMismatch
+	} else if errors.As(err, &parseErr) {
+		return ErrorCodeUnexpectedOutput
+	}
+	class := diagnostics.ClassifyOutput(err.Error())
+	for _, p := range errorCodePatterns {
+		if p.Pattern != nil && p.Pattern.MatchString(err.Error()) || p.Pattern == nil && p.Class == class {
+			return p.Code
+		}
+	}
+	return ErrorCodeCommandFailed
+}
+
+// Formats the detail of a diagnostic so that it starts with the error code. Values assigned to
+// TECTON_API_KEY are redacted, since diagnostics are shown in plan output and CI logs. The provider's
+// own secrets are already redacted from command errors by the client's Redactor.
+func codedDetail(code ErrorCode, detail string) string {
+	return fmt.Sprintf("[%v] %v", code, diagnostics.RedactAPIKeyAssignments(detail))
+}
+
+// Adds an error diagnostic with the given error code.
+func AddError(diags *diag.Diagnostics, code ErrorCode, summary string, detail string) {
+	diags.AddError(summary, codedDetail(code, detail))
+}
+
+// Adds an error diagnostic for a particular attribute with the given error code.
+func AddAttributeError(diags *diag.Diagnostics, attributePath path.Path, code ErrorCode, summary string, detail string) {
+	diags.AddAttributeError(attributePath, summary, codedDetail(code, detail))
+}
+
+// Adds a warning diagnostic with the given error code.
+func AddWarning(diags *diag.Diagnostics, code ErrorCode, summary string, detail string) {
+	diags.AddWarning(summary, codedDetail(code, detail))
+}
+
+// Adds a warning diagnostic for a particular attribute with the given error code.
+func AddAttributeWarning(diags *diag.Diagnostics, attributePath path.Path, code ErrorCode, summary string, detail string) {
+	diags.AddAttributeWarning(attributePath, summary, codedDetail(code, detail))
+}
+
+// Adds an error diagnostic for a failed command, deriving the error code from the command output.
+// Common failures, e.g. an invalid API key, are followed by advice on how to fix them.
+func AddCommandError(diags *diag.Diagnostics, summary string, err error) {
+	code := ClassifyError(err)
+	detail := err.Error()
+	if advice, ok := errorCodeAdvice[code]; ok {
+		detail = fmt.Sprintf("%v\n\n%v", advice, detail)
+	}
+	AddError(diags, code, summary, detail)
+}
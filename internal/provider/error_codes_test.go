@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+)
+
+func TestClassifyError(t *testing.T) {
+	testCases := map[string]struct {
+		err      error
+		expected ErrorCode
+	}{
+		"auth":                {err: errors.New("Output: Error: Unauthenticated: invalid API key"), expected: ErrorCodeAuthFailed},
+		"permission":          {err: errors.New("Output: PERMISSION_DENIED: caller is not an admin"), expected: ErrorCodePermissionDenied},
+		"workspace not found": {err: errors.New("Tecton workspace with name 'prod' does not exist."), expected: ErrorCodeWorkspaceNotFound},
+		"user not found":      {err: errors.New("Output: Error: user alice@example.com not found"), expected: ErrorCodePrincipalNotFound},
+		"workspace locked":    {err: errors.New("Output: Error: another apply is already in progress on workspace prod"), expected: ErrorCodeWorkspaceLocked},
+		"rate limited":        {err: errors.New("Output: Error: status code 429: Too Many Requests"), expected: ErrorCodeRateLimited},
+		"throttled":           {err: errors.New("Output: RESOURCE_EXHAUSTED: request was throttled"), expected: ErrorCodeRateLimited},
+		"already exists":      {err: errors.New("Output: Workspace prod already exists"), expected: ErrorCodeAlreadyExists},
+		"timed out":           {err: errors.New("Error: Command timed out after 10m0s\nOutput: "), expected: ErrorCodeCommandTimedOut},
+		"unsupported feature": {err: errors.New("Secrets is not supported on your cluster version."), expected: ErrorCodeUnsupportedFeature},
+		"unsupported sdk":     {err: errors.New("'group.py' reads data ..., so it's only supported with tecton 0.7.x."), expected: ErrorCodeVersionMismatch},
+		"wrapped timeout":     {err: diagnostics.Wrap(&diagnostics.CommandError{Command: "tecton plan", Err: diagnostics.ErrTimedOut}, "Failed to plan"), expected: ErrorCodeCommandTimedOut},
+		"waiting for input":   {err: diagnostics.Wrap(&diagnostics.CommandError{Command: "tecton login", Err: fmt.Errorf("%w.", diagnostics.ErrWaitingForInput)}, "Failed to log in"), expected: ErrorCodeInteractivePrompt},
+		"parse":               {err: &diagnostics.ParseError{Source: "list_admins.py", Output: "oops"}, expected: ErrorCodeUnexpectedOutput},
+		"version mismatch":    {err: &diagnostics.SchemaError{Source: "tecton access-control get-roles", Output: "[{}]", Problems: []string{"[0].resource_type is missing"}}, expected: ErrorCodeVersionMismatch},
+		"workspace number":    {err: errors.New("Output: something broke in team-401"), expected: ErrorCodeCommandFailed},
+		"unknown":             {err: errors.New("Output: segmentation fault"), expected: ErrorCodeCommandFailed},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := ClassifyError(testCase.err)
+			if actual != testCase.expected {
+				t.Errorf("expected '%v', got '%v'", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAddCommandError(t *testing.T) {
+	var diags diag.Diagnostics
+	AddCommandError(&diags, "Role Read Failure", errors.New("Output: Unauthenticated"))
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %v", len(diags))
+	}
+	if diags[0].Summary() != "Role Read Failure" {
+		t.Errorf("expected summary 'Role Read Failure', got '%v'", diags[0].Summary())
+	}
+	expectedDetail := "[TECTON_AUTH_FAILED] " + errorCodeAdvice[ErrorCodeAuthFailed] + "\n\nOutput: Unauthenticated"
+	if diags[0].Detail() != expectedDetail {
+		t.Errorf("expected detail '%v', got '%v'", expectedDetail, diags[0].Detail())
+	}
+
+	// Failures without advice only have the error
+	diags = nil
+	AddCommandError(&diags, "Role Read Failure", errors.New("Output: segmentation fault"))
+	expectedDetail = "[TECTON_COMMAND_FAILED] Output: segmentation fault"
+	if diags[0].Detail() != expectedDetail {
+		t.Errorf("expected detail '%v', got '%v'", expectedDetail, diags[0].Detail())
+	}
+}
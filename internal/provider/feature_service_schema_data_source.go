@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &featureServiceSchemaDataSource{}
+	_ datasource.DataSourceWithConfigure = &featureServiceSchemaDataSource{}
+)
+
+// NewFeatureServiceSchemaDataSource is a helper function to simplify the provider implementation.
+func NewFeatureServiceSchemaDataSource() datasource.DataSource {
+	return &featureServiceSchemaDataSource{}
+}
+
+// featureServiceSchemaDataSource describes the inputs of a feature service's online requests.
+type featureServiceSchemaDataSource struct {
+	CLI tectonclient.Client
+}
+
+// featureServiceSchemaDataSourceModel maps the data source schema data.
+type featureServiceSchemaDataSourceModel struct {
+	ID             types.String                     `tfsdk:"id"`
+	Workspace      types.String                     `tfsdk:"workspace"`
+	Name           types.String                     `tfsdk:"name"`
+	JoinKeys       []featureServiceSchemaFieldModel `tfsdk:"join_keys"`
+	RequestContext []featureServiceSchemaFieldModel `tfsdk:"request_context"`
+}
+
+// featureServiceSchemaFieldModel maps an element of `join_keys` and `request_context`.
+type featureServiceSchemaFieldModel struct {
+	Name types.String `tfsdk:"name"`
+	Type types.String `tfsdk:"type"`
+}
+
+// A field in the JSON output of the `feature_service_schema.py` script.
+type featureServiceSchemaField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// The JSON output of the `feature_service_schema.py` script.
+type featureServiceSchema struct {
+	JoinKeys       []featureServiceSchemaField `json:"join_keys"`
+	RequestContext []featureServiceSchemaField `json:"request_context"`
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *featureServiceSchemaDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.CLI = providerData.CLI
+}
+
+// Metadata returns the data source type name.
+func (d *featureServiceSchemaDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_feature_service_schema"
+}
+
+// Schema defines the schema for the data source.
+func (d *featureServiceSchemaDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	fieldAttributes := func(kind string) map[string]schema.Attribute {
+		return map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: fmt.Sprintf("The name of the %v.", kind),
+				Computed:    true,
+			},
+			"type": schema.StringAttribute{
+				Description: fmt.Sprintf("The data type of the %v, e.g. \"string\" or \"array<float64>\".", kind),
+				Computed:    true,
+			},
+		}
+	}
+	resp.Schema = schema.Schema{
+		Description: "Describes the join keys and request context keys that online requests to a feature service must provide, " +
+			"so that client stubs, API gateway models and request validation can be generated from the same Terraform run. " +
+			"The feature service must be deployed to a live workspace. The schema is read from the feature server's metadata endpoint, " +
+			"which only accepts an API key, so the provider must be configured with one rather than with `use_cli_login`, `oauth` or `okta`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The workspace and feature service name, separated by a slash.",
+				Computed:    true,
+			},
+			"workspace": schema.StringAttribute{
+				Description: "The name of the workspace.",
+				Required:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the feature service.",
+				Required:    true,
+			},
+			"join_keys": schema.ListNestedAttribute{
+				Description: "The join keys of the feature service, in the order Tecton returns them.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: fieldAttributes("join key"),
+				},
+			},
+			"request_context": schema.ListNestedAttribute{
+				Description: "The request context keys of the feature service's on-demand feature views, in the order Tecton returns them. Empty if it has none.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: fieldAttributes("request context key"),
+				},
+			},
+		},
+	}
+}
+
+// Read describes the feature service.
+func (d *featureServiceSchemaDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.CLI.WithCorrelationID(ctx)
+	defer d.CLI.LogOperationSummary(ctx, "data.tecton_feature_service_schema", "Read")
+	var config featureServiceSchemaDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspace, name := config.Workspace.ValueString(), config.Name.ValueString()
+	if !requireAPIKey(d.CLI, "The schema of a feature service is read from the feature server's metadata endpoint, which only accepts an API key", &resp.Diagnostics) {
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("Reading the schema of feature service '%v' in workspace '%v'", name, workspace))
+	output, err := d.CLI.RunScript(ctx, "feature_service_schema.py", workspace, name)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read feature service schema", err)
+		return
+	}
+	var fsSchema featureServiceSchema
+	err = json.Unmarshal(output, &fsSchema)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read feature service schema", &diagnostics.ParseError{Source: "feature_service_schema.py", Output: string(output), Err: err})
+		return
+	}
+
+	config.ID = types.StringValue(workspace + "/" + name)
+	config.JoinKeys = featureServiceSchemaFields(fsSchema.JoinKeys)
+	config.RequestContext = featureServiceSchemaFields(fsSchema.RequestContext)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+// Converts fields in the output of the `feature_service_schema.py` script to their model.
+func featureServiceSchemaFields(fields []featureServiceSchemaField) []featureServiceSchemaFieldModel {
+	models := []featureServiceSchemaFieldModel{}
+	for _, field := range fields {
+		models = append(models, featureServiceSchemaFieldModel{
+			Name: types.StringValue(field.Name),
+			Type: types.StringValue(field.Type),
+		})
+	}
+	return models
+}
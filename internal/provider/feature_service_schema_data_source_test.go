@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+func TestFeatureServiceSchemaDataSourceRead(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"join_keys": [{"name": "user_id", "type": "string"}], "request_context": [{"name": "amount", "type": "float64"}, {"name": "embedding", "type": "array<float32>"}]}`, "")
+
+	t.Setenv("TECTON_API_KEY", "abc")
+
+	ctx := context.Background()
+	resp := readDataSource(t, NewFeatureServiceSchemaDataSource(), map[string]tftypes.Value{
+		"workspace": tftypes.NewValue(tftypes.String, "prod"),
+		"name":      tftypes.NewValue(tftypes.String, "fraud_detection"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	var state featureServiceSchemaDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("failed to read state: %v", resp.Diagnostics)
+	}
+	if state.ID.ValueString() != "prod/fraud_detection" {
+		t.Errorf("unexpected ID: %v", state.ID)
+	}
+	if len(state.JoinKeys) != 1 || state.JoinKeys[0].Name.ValueString() != "user_id" {
+		t.Errorf("unexpected join keys: %v", state.JoinKeys)
+	}
+	if len(state.RequestContext) != 2 || state.RequestContext[1].Type.ValueString() != "array<float32>" {
+		t.Errorf("unexpected request context: %v", state.RequestContext)
+	}
+}
+
+func TestFeatureServiceSchemaDataSourceRead_noAPIKey(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"join_keys": [], "request_context": []}`, "")
+
+	// e.g. with `oauth`, which clears TECTON_API_KEY so that the CLI uses the session
+	d := &featureServiceSchemaDataSource{CLI: tectonclient.Client{Env: []string{"TECTON_API_KEY="}}}
+	resp := readDataSource(t, d, map[string]tftypes.Value{
+		"workspace": tftypes.NewValue(tftypes.String, "prod"),
+		"name":      tftypes.NewValue(tftypes.String, "fraud_detection"),
+	})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "[TECTON_CREDENTIALS_UNAVAILABLE]") {
+		t.Fatalf("expected a missing API key error, got: %v", resp.Diagnostics)
+	}
+}
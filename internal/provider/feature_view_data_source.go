@@ -0,0 +1,242 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &featureViewDataSource{}
+	_ datasource.DataSourceWithConfigure = &featureViewDataSource{}
+)
+
+// NewFeatureViewDataSource is a helper function to simplify the provider implementation.
+func NewFeatureViewDataSource() datasource.DataSource {
+	return &featureViewDataSource{}
+}
+
+// featureViewDataSource describes a feature view in a workspace.
+type featureViewDataSource struct {
+	CLI tectonclient.Client
+}
+
+// featureViewDataSourceModel maps the data source schema data.
+type featureViewDataSourceModel struct {
+	ID               types.String              `tfsdk:"id"`
+	Workspace        types.String              `tfsdk:"workspace"`
+	Name             types.String              `tfsdk:"name"`
+	Description      types.String              `tfsdk:"description"`
+	Owner            types.String              `tfsdk:"owner"`
+	Tags             map[string]types.String   `tfsdk:"tags"`
+	JoinKeys         []types.String            `tfsdk:"join_keys"`
+	TimestampField   types.String              `tfsdk:"timestamp_field"`
+	Features         []featureViewFeatureModel `tfsdk:"features"`
+	Online           types.Bool                `tfsdk:"online"`
+	Offline          types.Bool                `tfsdk:"offline"`
+	FeatureStartTime types.String              `tfsdk:"feature_start_time"`
+	BatchSchedule    types.String              `tfsdk:"batch_schedule"`
+	TTL              types.String              `tfsdk:"ttl"`
+}
+
+// featureViewFeatureModel maps an element of `features`.
+type featureViewFeatureModel struct {
+	Name types.String `tfsdk:"name"`
+	Type types.String `tfsdk:"type"`
+}
+
+// The JSON output of the `feature_view.py` script.
+type featureView struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Description    string            `json:"description"`
+	Owner          string            `json:"owner"`
+	Tags           map[string]string `json:"tags"`
+	JoinKeys       []string          `json:"join_keys"`
+	TimestampField string            `json:"timestamp_field"`
+	Features       []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"features"`
+	Online               bool   `json:"online"`
+	Offline              bool   `json:"offline"`
+	FeatureStartTime     string `json:"feature_start_time"`
+	BatchScheduleSeconds int64  `json:"batch_schedule_seconds"`
+	TTLSeconds           int64  `json:"ttl_seconds"`
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *featureViewDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.CLI = providerData.CLI
+}
+
+// Metadata returns the data source type name.
+func (d *featureViewDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_feature_view"
+}
+
+// Schema defines the schema for the data source.
+func (d *featureViewDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Describes a feature view: its features, join keys and materialization settings, " +
+			"so that downstream tooling such as schema registries or documentation can consume feature view metadata from Terraform.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of the feature view.",
+				Computed:    true,
+			},
+			"workspace": schema.StringAttribute{
+				Description: "The name of the workspace.",
+				Required:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the feature view.",
+				Required:    true,
+			},
+			"description": schema.StringAttribute{
+				Description: "The description of the feature view.",
+				Computed:    true,
+			},
+			"owner": schema.StringAttribute{
+				Description: "The owner of the feature view.",
+				Computed:    true,
+			},
+			"tags": schema.MapAttribute{
+				Description: "The tags of the feature view.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"join_keys": schema.ListAttribute{
+				Description: "The join keys of the feature view's entities.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"timestamp_field": schema.StringAttribute{
+				Description: "The name of the timestamp column. Empty for on-demand feature views.",
+				Computed:    true,
+			},
+			"features": schema.ListNestedAttribute{
+				Description: "The features of the feature view, in the order Tecton returns them.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "The name of the feature.",
+							Computed:    true,
+						},
+						"type": schema.StringAttribute{
+							Description: "The data type of the feature as Tecton reports it, e.g. \"Int64\". Empty if Tecton doesn't report it.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"online": schema.BoolAttribute{
+				Description: "True if the feature view is materialized to the online store.",
+				Computed:    true,
+			},
+			"offline": schema.BoolAttribute{
+				Description: "True if the feature view is materialized to the offline store.",
+				Computed:    true,
+			},
+			"feature_start_time": schema.StringAttribute{
+				Description: "The time materialization starts from, as an RFC 3339 timestamp. Empty if the feature view isn't materialized.",
+				Computed:    true,
+			},
+			"batch_schedule": schema.StringAttribute{
+				Description: "The interval of batch materialization jobs, as a Go duration string, e.g. \"24h0m0s\". Empty if the feature view has no batch schedule.",
+				Computed:    true,
+			},
+			"ttl": schema.StringAttribute{
+				Description: "How long features are served after they're computed, as a Go duration string. Empty if the feature view has no TTL.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Read describes the feature view.
+func (d *featureViewDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.CLI.WithCorrelationID(ctx)
+	defer d.CLI.LogOperationSummary(ctx, "data.tecton_feature_view", "Read")
+	var config featureViewDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspace, name := config.Workspace.ValueString(), config.Name.ValueString()
+	tflog.Info(ctx, fmt.Sprintf("Reading feature view '%v' in workspace '%v'", name, workspace))
+	output, err := d.CLI.RunScript(ctx, "feature_view.py", workspace, name)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read feature view", err)
+		return
+	}
+	var fv featureView
+	err = json.Unmarshal(output, &fv)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read feature view", &diagnostics.ParseError{Source: "feature_view.py", Output: string(output), Err: err})
+		return
+	}
+
+	config.ID = types.StringValue(fv.ID)
+	config.Description = types.StringValue(fv.Description)
+	config.Owner = types.StringValue(fv.Owner)
+	config.Tags = map[string]types.String{}
+	for key, value := range fv.Tags {
+		config.Tags[key] = types.StringValue(value)
+	}
+	config.JoinKeys = []types.String{}
+	for _, joinKey := range fv.JoinKeys {
+		config.JoinKeys = append(config.JoinKeys, types.StringValue(joinKey))
+	}
+	config.TimestampField = types.StringValue(fv.TimestampField)
+	config.Features = []featureViewFeatureModel{}
+	for _, feature := range fv.Features {
+		config.Features = append(config.Features, featureViewFeatureModel{
+			Name: types.StringValue(feature.Name),
+			Type: types.StringValue(feature.Type),
+		})
+	}
+	config.Online = types.BoolValue(fv.Online)
+	config.Offline = types.BoolValue(fv.Offline)
+	config.FeatureStartTime = types.StringValue(fv.FeatureStartTime)
+	config.BatchSchedule = types.StringValue(formatSeconds(fv.BatchScheduleSeconds))
+	config.TTL = types.StringValue(formatSeconds(fv.TTLSeconds))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+// Formats a number of seconds as a Go duration string, or "" if it's 0.
+func formatSeconds(seconds int64) string {
+	if seconds == 0 {
+		return ""
+	}
+	return (time.Duration(seconds) * time.Second).String()
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestFeatureViewDataSourceRead(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"id": "fv1", "name": "user_clicks", "description": "", "owner": "alice@example.com", "tags": {"team": "growth"},
+		"join_keys": ["user_id"], "timestamp_field": "timestamp", "features": [{"name": "clicks", "type": "Int64"}],
+		"online": true, "offline": false, "feature_start_time": "2024-01-01T00:00:00Z", "batch_schedule_seconds": 86400, "ttl_seconds": 0}`, "")
+
+	ctx := context.Background()
+	resp := readDataSource(t, NewFeatureViewDataSource(), map[string]tftypes.Value{
+		"workspace": tftypes.NewValue(tftypes.String, "prod"),
+		"name":      tftypes.NewValue(tftypes.String, "user_clicks"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	var state featureViewDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("failed to read state: %v", resp.Diagnostics)
+	}
+	if len(state.Features) != 1 || state.Features[0].Type.ValueString() != "Int64" {
+		t.Errorf("unexpected features: %v", state.Features)
+	}
+	if state.BatchSchedule.ValueString() != "24h0m0s" || state.TTL.ValueString() != "" {
+		t.Errorf("unexpected durations: batch_schedule %v, ttl %v", state.BatchSchedule, state.TTL)
+	}
+	if !state.Online.ValueBool() || state.Offline.ValueBool() {
+		t.Errorf("unexpected stores: online %v, offline %v", state.Online, state.Offline)
+	}
+}
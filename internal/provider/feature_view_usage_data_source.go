@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &featureViewUsageDataSource{}
+	_ datasource.DataSourceWithConfigure = &featureViewUsageDataSource{}
+)
+
+// NewFeatureViewUsageDataSource is a helper function to simplify the provider implementation.
+func NewFeatureViewUsageDataSource() datasource.DataSource {
+	return &featureViewUsageDataSource{}
+}
+
+// featureViewUsageDataSource breaks down the usage of a workspace by feature view.
+type featureViewUsageDataSource struct {
+	CLI tectonclient.Client
+}
+
+// featureViewUsageDataSourceModel maps the data source schema data.
+type featureViewUsageDataSourceModel struct {
+	ID           types.String            `tfsdk:"id"`
+	Workspace    types.String            `tfsdk:"workspace"`
+	Window       types.String            `tfsdk:"window"`
+	FeatureViews []featureViewUsageModel `tfsdk:"feature_views"`
+}
+
+// featureViewUsageModel maps an element of `feature_views`.
+type featureViewUsageModel struct {
+	Name                        types.String   `tfsdk:"name"`
+	MaterializationJobCount     types.Int64    `tfsdk:"materialization_job_count"`
+	MaterializationComputeHours types.Float64  `tfsdk:"materialization_compute_hours"`
+	ComputeShare                types.Float64  `tfsdk:"compute_share"`
+	FeatureServices             []types.String `tfsdk:"feature_services"`
+	ServingRequestCount         types.Int64    `tfsdk:"serving_request_count"`
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *featureViewUsageDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.CLI = providerData.CLI
+}
+
+// Metadata returns the data source type name.
+func (d *featureViewUsageDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_feature_view_usage"
+}
+
+// Schema defines the schema for the data source.
+func (d *featureViewUsageDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Breaks down the materialization compute and online serving usage of a workspace by feature view over a recent time window, " +
+			"so that expensive feature views can be identified and gated in infrastructure reviews. Like `tecton_usage`, compute hours are the run times " +
+			"of materialization job attempts, not billed hours. " +
+			"Requires the provider to be configured with an API key rather than with `use_cli_login`, `oauth` or `okta`, since the feature views that feature services serve are read from the feature server's metadata endpoint, which only accepts API keys.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Equal to the workspace name.",
+				Computed:    true,
+			},
+			"workspace": schema.StringAttribute{
+				Description: "The name of the workspace.",
+				Required:    true,
+			},
+			"window": schema.StringAttribute{
+				Description: "How far back to report usage, as a Go duration string, e.g. \"168h\". Defaults to \"720h\", i.e. 30 days.",
+				Optional:    true,
+				Validators: []validator.String{
+					durationValidator(),
+				},
+			},
+			"feature_views": schema.ListNestedAttribute{
+				Description: "The usage of every feature view in the workspace, sorted by compute hours, most expensive first, and then by name.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "The name of the feature view.",
+							Computed:    true,
+						},
+						"materialization_job_count": schema.Int64Attribute{
+							Description: "The number of materialization jobs of the feature view that ran within the window.",
+							Computed:    true,
+						},
+						"materialization_compute_hours": schema.Float64Attribute{
+							Description: "The run time of the feature view's materialization jobs within the window, in hours.",
+							Computed:    true,
+						},
+						"compute_share": schema.Float64Attribute{
+							Description: "The feature view's share of the workspace's materialization compute hours, between 0 and 1.",
+							Computed:    true,
+						},
+						"feature_services": schema.ListAttribute{
+							Description: "The feature services that serve the feature view, sorted. Empty if the feature server's metadata couldn't be read.",
+							ElementType: types.StringType,
+							Computed:    true,
+						},
+						"serving_request_count": schema.Int64Attribute{
+							Description: "The number of online requests within the window to the feature services that serve the feature view, since every request reads it. " +
+								"Null if the installed Tecton SDK doesn't report serving metrics.",
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read breaks down the usage of the workspace by feature view.
+func (d *featureViewUsageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.CLI.WithCorrelationID(ctx)
+	defer d.CLI.LogOperationSummary(ctx, "data.tecton_feature_view_usage", "Read")
+	var config featureViewUsageDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !requireAPIKey(d.CLI, "Usage is read together with the feature views each feature service serves, from the feature server's metadata endpoint, which only accepts an API key", &resp.Diagnostics) {
+		return
+	}
+	since, ok := usageWindowStart(config.Window, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	usage, err := d.CLI.GetUsage(ctx, since, []string{config.Workspace.ValueString()})
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read usage", err)
+		return
+	}
+
+	config.ID = config.Workspace
+	config.FeatureViews = []featureViewUsageModel{}
+	for _, workspace := range usage.Workspaces {
+		totalHours := workspace.MaterializationComputeHours()
+		for _, featureView := range workspace.FeatureViews {
+			model := featureViewUsageModel{
+				Name:                        types.StringValue(featureView.Name),
+				MaterializationJobCount:     types.Int64Value(featureView.MaterializationJobCount),
+				MaterializationComputeHours: types.Float64Value(featureView.MaterializationComputeHours),
+				ComputeShare:                types.Float64Value(0),
+				FeatureServices:             []types.String{},
+			}
+			if totalHours > 0 {
+				model.ComputeShare = types.Float64Value(featureView.MaterializationComputeHours / totalHours)
+			}
+			servingUsage := tectonclient.WorkspaceUsage{FeatureServices: workspace.FeatureServicesServing(featureView.Name)}
+			for _, featureService := range servingUsage.FeatureServices {
+				model.FeatureServices = append(model.FeatureServices, types.StringValue(featureService.Name))
+			}
+			model.ServingRequestCount = types.Int64PointerValue(servingUsage.ServingRequestCount())
+			config.FeatureViews = append(config.FeatureViews, model)
+		}
+	}
+	sort.SliceStable(config.FeatureViews, func(i, j int) bool {
+		a, b := config.FeatureViews[i], config.FeatureViews[j]
+		if a.MaterializationComputeHours.ValueFloat64() != b.MaterializationComputeHours.ValueFloat64() {
+			return a.MaterializationComputeHours.ValueFloat64() > b.MaterializationComputeHours.ValueFloat64()
+		}
+		return a.Name.ValueString() < b.Name.ValueString()
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
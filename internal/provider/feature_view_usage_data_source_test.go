@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestFeatureViewUsageDataSourceRead(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"workspaces": [{"name": "prod", "feature_views": [
+		{"name": "user_clicks", "materialization_job_count": 4, "materialization_compute_hours": 1},
+		{"name": "user_transactions", "materialization_job_count": 2, "materialization_compute_hours": 3},
+		{"name": "user_embeddings", "materialization_job_count": 0, "materialization_compute_hours": 0}
+	], "feature_services": [
+		{"name": "fraud", "feature_views": ["user_transactions", "user_clicks"], "serving_request_count": 100},
+		{"name": "ads", "feature_views": ["user_clicks"], "serving_request_count": 50}
+	]}]}`, "")
+	t.Setenv(apiKeyEnvVar, "abc")
+
+	ctx := context.Background()
+	resp := readDataSource(t, NewFeatureViewUsageDataSource(), map[string]tftypes.Value{
+		"workspace": tftypes.NewValue(tftypes.String, "prod"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	var state featureViewUsageDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("failed to read state: %v", resp.Diagnostics)
+	}
+	var names []string
+	for _, featureView := range state.FeatureViews {
+		names = append(names, featureView.Name.ValueString())
+	}
+	if strings.Join(names, ",") != "user_transactions,user_clicks,user_embeddings" {
+		t.Fatalf("expected the feature views sorted by compute hours, got %v", names)
+	}
+	transactions, clicks, embeddings := state.FeatureViews[0], state.FeatureViews[1], state.FeatureViews[2]
+	if transactions.ComputeShare.ValueFloat64() != 0.75 || clicks.ComputeShare.ValueFloat64() != 0.25 {
+		t.Errorf("unexpected compute shares: %v, %v", transactions.ComputeShare, clicks.ComputeShare)
+	}
+	if clicks.ServingRequestCount.ValueInt64() != 150 || len(clicks.FeatureServices) != 2 || clicks.FeatureServices[0].ValueString() != "ads" {
+		t.Errorf("expected user_clicks to be served by both feature services, got %v requests from %v", clicks.ServingRequestCount, clicks.FeatureServices)
+	}
+	if embeddings.ServingRequestCount.ValueInt64() != 0 || len(embeddings.FeatureServices) != 0 {
+		t.Errorf("expected an unserved feature view to have no requests, got %v", embeddings.ServingRequestCount)
+	}
+}
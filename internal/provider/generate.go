@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Matches the characters that aren't allowed in a Terraform resource name.
+var invalidResourceNameCharsRegex = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// Writes the HCL of a `tecton_workspace` for every workspace and a `tecton_access_policy` for every
+// user and service account with a direct role grant in the cluster to out, each followed by an
+// `import` block, so that an existing cluster can be brought under Terraform with a plan that has no
+// changes. This is run by the `generate` subcommand of the provider binary, with the client built
+// from config the same way as the provider's. Warnings, e.g. about an insecure connection, are logged.
+func Generate(ctx context.Context, out io.Writer, config TectonProviderModel) error {
+	err := requireCredentials(&config)
+	if err != nil {
+		return err
+	}
+	var diags diag.Diagnostics
+	ctx, client := newProviderClient(ctx, &config, &diags)
+	for _, d := range diags.Warnings() {
+		log.Printf("Warning: %v: %v", d.Summary(), d.Detail())
+	}
+	if diags.HasError() {
+		return diagnosticsError(diags)
+	}
+	cli := client.CLI
+
+	workspaces, err := cli.ListWorkspaces(ctx)
+	if err != nil {
+		return err
+	}
+	principals, err := ListPrincipals(ctx, cli, workspaces)
+	if err != nil {
+		return err
+	}
+	var policies []accessPolicyResourceModel
+	for _, principal := range principals.Principals() {
+		policies = append(policies, accessPolicyResourceModel{
+			UserID:           types.StringValue(principal.UserID),
+			ServiceAccountID: types.StringValue(principal.ServiceAccountID),
+		})
+	}
+	resource := &accessPolicyResource{CLI: cli}
+	err = runParallel(len(policies), func(i int) error {
+		_, err := resource.GetFromTecton(ctx, &policies[i])
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+	names := map[string]bool{}
+	appendResource := func(resourceType string, name string, id string, attributes func(*hclwrite.Body)) {
+		name = uniqueResourceName(name, names)
+		body.AppendNewline()
+		attributes(body.AppendNewBlock("resource", []string{resourceType, name}).Body())
+		body.AppendNewline()
+		importBody := body.AppendNewBlock("import", nil).Body()
+		importBody.SetAttributeTraversal("to", hcl.Traversal{hcl.TraverseRoot{Name: resourceType}, hcl.TraverseAttr{Name: name}})
+		importBody.SetAttributeValue("id", cty.StringVal(id))
+	}
+
+	for _, live := range []bool{true, false} {
+		workspaceNames := workspaces.Devs
+		if live {
+			workspaceNames = workspaces.Lives
+		}
+		workspaceNames = append([]string{}, workspaceNames...)
+		sort.Strings(workspaceNames)
+		for _, workspace := range workspaceNames {
+			appendResource("tecton_workspace", workspace, workspace, func(b *hclwrite.Body) {
+				b.SetAttributeValue("name", cty.StringVal(workspace))
+				b.SetAttributeValue("live", cty.BoolVal(live))
+			})
+		}
+	}
+	for _, policy := range policies {
+		name, id := "user_"+policy.UserID.ValueString(), "user-"+policy.UserID.ValueString()
+		if policy.UserID.ValueString() == "" {
+			name, id = "service_"+policy.ServiceAccountID.ValueString(), "service-"+policy.ServiceAccountID.ValueString()
+		}
+		appendResource("tecton_access_policy", name, id, func(b *hclwrite.Body) {
+			if policy.UserID.ValueString() != "" {
+				b.SetAttributeValue("user_id", cty.StringVal(policy.UserID.ValueString()))
+			} else {
+				b.SetAttributeValue("service_account_id", cty.StringVal(policy.ServiceAccountID.ValueString()))
+			}
+			if policy.Admin.ValueBool() {
+				b.SetAttributeValue("admin", cty.True)
+			}
+			if len(policy.AllWorkspaces) > 0 {
+				b.SetAttributeValue("all_workspaces", stringListValue(policy.AllWorkspaces))
+			}
+			if len(policy.Workspaces) > 0 {
+				workspaceRoles := map[string]cty.Value{}
+				for workspace, roles := range policy.Workspaces {
+					workspaceRoles[workspace] = stringListValue(roles)
+				}
+				b.SetAttributeValue("workspaces", cty.MapVal(workspaceRoles))
+			}
+		})
+	}
+
+	_, err = fmt.Fprintf(out, "# Generated by `terraform-provider-tecton generate` from %v\n%s", client.URL, file.Bytes())
+	return err
+}
+
+// Returns a valid Terraform resource name based on name that isn't in used, and adds it to used.
+func uniqueResourceName(name string, used map[string]bool) string {
+	name = invalidResourceNameCharsRegex.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') || name[0] == '-' {
+		name = "_" + name
+	}
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%v_%v", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// Converts strings to a cty list for hclwrite.
+func stringListValue(values []types.String) cty.Value {
+	var elements []cty.Value
+	for _, value := range values {
+		elements = append(elements, cty.StringVal(value.ValueString()))
+	}
+	if len(elements) == 0 {
+		return cty.ListValEmpty(cty.String)
+	}
+	return cty.ListVal(elements)
+}
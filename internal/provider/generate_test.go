@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	fakeTectonCluster(t, `{
+		"workspaces": {"prod": {"live": true}, "1-dev": {"live": false}},
+		"roles": {
+			"user:alice@example.com": {"*": ["admin", "viewer"], "prod": ["owner"]},
+			"service:ci": {"1-dev": ["editor"]}
+		}
+	}`)
+
+	var out bytes.Buffer
+	err := Generate(context.Background(), &out, subcommandConfig(t, map[string]any{"url": "https://yourcluster.tecton.ai", "api_key": "abc"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{
+		"# Generated by `terraform-provider-tecton generate` from https://yourcluster.tecton.ai\n",
+		"resource \"tecton_workspace\" \"prod\" {\n  name = \"prod\"\n  live = true\n}",
+		"resource \"tecton_workspace\" \"_1-dev\" {\n  name = \"1-dev\"\n  live = false\n}",
+		"import {\n  to = tecton_workspace._1-dev\n  id = \"1-dev\"\n}",
+		"resource \"tecton_access_policy\" \"user_alice_example_com\" {\n  user_id        = \"alice@example.com\"\n  admin          = true\n  all_workspaces = [\"viewer\"]\n",
+		"import {\n  to = tecton_access_policy.user_alice_example_com\n  id = \"user-alice@example.com\"\n}",
+		"resource \"tecton_access_policy\" \"service_ci\" {\n  service_account_id = \"ci\"\n  workspaces = {\n    \"1-dev\" = [\"editor\"]\n  }\n}",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected the output to contain:\n%v\ngot:\n%v", expected, out.String())
+		}
+	}
+}
+
+func TestGenerate_noApiKey(t *testing.T) {
+	t.Setenv(apiKeyEnvVar, "")
+	err := Generate(context.Background(), &bytes.Buffer{}, subcommandConfig(t, map[string]any{"url": "https://yourcluster.tecton.ai"}))
+	if err == nil || !strings.Contains(err.Error(), "No API key provided") {
+		t.Errorf("expected an error without an API key, got: %v", err)
+	}
+}
+
+func TestGenerate_cliLogin(t *testing.T) {
+	fakeTectonCluster(t, `{"workspaces": {"prod": {"live": true}}}`)
+
+	var out bytes.Buffer
+	err := Generate(context.Background(), &out, subcommandConfig(t, map[string]any{"url": "https://yourcluster.tecton.ai", "use_cli_login": true}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "resource \"tecton_workspace\" \"prod\"") {
+		t.Errorf("expected the workspace in the output, got:\n%v", out.String())
+	}
+}
+
+func TestUniqueResourceName(t *testing.T) {
+	used := map[string]bool{}
+	for _, testCase := range []struct{ name, expected string }{
+		{"prod", "prod"},
+		{"prod", "prod_2"},
+		{"user_a@b.com", "user_a_b_com"},
+		{"1-dev", "_1-dev"},
+	} {
+		if actual := uniqueResourceName(testCase.name, used); actual != testCase.expected {
+			t.Errorf("expected '%v' for '%v', got '%v'", testCase.expected, testCase.name, actual)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &groupDataSource{}
+	_ datasource.DataSourceWithConfigure = &groupDataSource{}
+)
+
+// NewGroupDataSource is a helper function to simplify the provider implementation.
+func NewGroupDataSource() datasource.DataSource {
+	return &groupDataSource{}
+}
+
+// groupDataSource looks up a principal group by name.
+type groupDataSource struct {
+	CLI tectonclient.Client
+}
+
+// groupDataSourceModel maps the data source schema data.
+type groupDataSourceModel struct {
+	ID              types.String   `tfsdk:"id"`
+	Name            types.String   `tfsdk:"name"`
+	Description     types.String   `tfsdk:"description"`
+	CreatedBy       types.String   `tfsdk:"created_by"`
+	CreatedAt       types.String   `tfsdk:"created_at"`
+	IdpMappingNames []types.String `tfsdk:"idp_mapping_names"`
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *groupDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.CLI = providerData.CLI
+}
+
+// Metadata returns the data source type name.
+func (d *groupDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group"
+}
+
+// Schema defines the schema for the data source.
+func (d *groupDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up a principal group by name, so that roles can be granted to it without hard-coding its ID.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of the group.",
+				Computed:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the group.",
+				Required:    true,
+			},
+			"description": schema.StringAttribute{
+				Description: "The description of the group.",
+				Computed:    true,
+			},
+			"created_by": schema.StringAttribute{
+				Description: "The email of the user or the ID of the service account that created the group.",
+				Computed:    true,
+			},
+			"created_at": schema.StringAttribute{
+				Description: "When the group was created, as an RFC 3339 timestamp. Empty if Tecton doesn't report it.",
+				Computed:    true,
+			},
+			"idp_mapping_names": schema.ListAttribute{
+				Description: "The names of the identity provider groups that are mapped to the group, sorted.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Read looks up the group.
+func (d *groupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.CLI.WithCorrelationID(ctx)
+	defer d.CLI.LogOperationSummary(ctx, "data.tecton_group", "Read")
+	var config groupDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	group, err := d.CLI.GetGroupByName(ctx, config.Name.ValueString())
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read group", err)
+		return
+	}
+
+	config.ID = types.StringValue(group.ID)
+	config.Description = types.StringValue(group.Description)
+	config.CreatedBy = types.StringValue(group.CreatedBy)
+	config.CreatedAt = types.StringValue(group.CreatedAt)
+	config.IdpMappingNames = []types.String{}
+	for _, name := range group.IdpMappingNames {
+		config.IdpMappingNames = append(config.IdpMappingNames, types.StringValue(name))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
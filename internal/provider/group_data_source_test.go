@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestGroupDataSourceRead(t *testing.T) {
+	testCases := map[string]struct {
+		output  string
+		isError bool
+	}{
+		"found":     {output: `{"id": "group1", "name": "ml-engineers", "description": "ML engineers", "created_by": "alice@example.com", "created_at": "2024-01-01T00:00:00Z", "idp_mapping_names": ["okta-ml"]}`},
+		"not found": {output: `null`, isError: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fakeTectonPythonCLI(t, testCase.output, "")
+
+			ctx := context.Background()
+			resp := readDataSource(t, NewGroupDataSource(), map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, "ml-engineers"),
+			})
+			if resp.Diagnostics.HasError() != testCase.isError {
+				t.Fatalf("expected error: %v, got: %v", testCase.isError, resp.Diagnostics)
+			}
+			if testCase.isError {
+				return
+			}
+
+			var state groupDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("failed to read state: %v", resp.Diagnostics)
+			}
+			if state.ID.ValueString() != "group1" || len(state.IdpMappingNames) != 1 {
+				t.Errorf("unexpected state: %+v", state)
+			}
+		})
+	}
+}
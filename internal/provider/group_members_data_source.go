@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &groupMembersDataSource{}
+	_ datasource.DataSourceWithConfigure = &groupMembersDataSource{}
+)
+
+// NewGroupMembersDataSource is a helper function to simplify the provider implementation.
+func NewGroupMembersDataSource() datasource.DataSource {
+	return &groupMembersDataSource{}
+}
+
+// groupMembersDataSource lists the members of a principal group.
+type groupMembersDataSource struct {
+	CLI tectonclient.Client
+}
+
+// groupMembersDataSourceModel maps the data source schema data.
+type groupMembersDataSourceModel struct {
+	ID              types.String   `tfsdk:"id"`
+	GroupID         types.String   `tfsdk:"group_id"`
+	Users           []types.String `tfsdk:"users"`
+	ServiceAccounts []types.String `tfsdk:"service_accounts"`
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *groupMembersDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.CLI = providerData.CLI
+}
+
+// Metadata returns the data source type name.
+func (d *groupMembersDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group_members"
+}
+
+// Schema defines the schema for the data source.
+func (d *groupMembersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the users and service accounts that are members of a principal group, " +
+			"e.g. to audit group membership or to derive access policies from it.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Equal to the group ID.",
+				Computed:    true,
+			},
+			"group_id": schema.StringAttribute{
+				Description: "The ID of the principal group.",
+				Required:    true,
+			},
+			"users": schema.ListAttribute{
+				Description: "The emails of the users that are members of the group, sorted.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"service_accounts": schema.ListAttribute{
+				Description: "The IDs of the service accounts that are members of the group, sorted.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Read lists the members of the group.
+func (d *groupMembersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.CLI.WithCorrelationID(ctx)
+	defer d.CLI.LogOperationSummary(ctx, "data.tecton_group_members", "Read")
+	var config groupMembersDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	members, err := d.CLI.GetGroupMembers(ctx, config.GroupID.ValueString())
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read group members", err)
+		return
+	}
+
+	config.ID = config.GroupID
+	config.Users = []types.String{}
+	for _, user := range members.Users {
+		config.Users = append(config.Users, types.StringValue(user))
+	}
+	config.ServiceAccounts = []types.String{}
+	for _, serviceAccount := range members.ServiceAccounts {
+		config.ServiceAccounts = append(config.ServiceAccounts, types.StringValue(serviceAccount))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
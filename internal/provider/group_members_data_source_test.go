@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestGroupMembersDataSourceRead(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"users": ["alice@example.com", "bob@example.com"], "service_accounts": ["abc"]}`, "")
+
+	ctx := context.Background()
+	resp := readDataSource(t, NewGroupMembersDataSource(), map[string]tftypes.Value{
+		"group_id": tftypes.NewValue(tftypes.String, "group1"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	var state groupMembersDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("failed to read state: %v", resp.Diagnostics)
+	}
+	if state.ID.ValueString() != "group1" {
+		t.Errorf("expected ID 'group1', got %v", state.ID)
+	}
+	if len(state.Users) != 2 || state.Users[1].ValueString() != "bob@example.com" {
+		t.Errorf("unexpected users: %v", state.Users)
+	}
+	if len(state.ServiceAccounts) != 1 || state.ServiceAccounts[0].ValueString() != "abc" {
+		t.Errorf("unexpected service accounts: %v", state.ServiceAccounts)
+	}
+}
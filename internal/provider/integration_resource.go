@@ -0,0 +1,367 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &integrationResource{}
+	_ resource.ResourceWithConfigure      = &integrationResource{}
+	_ resource.ResourceWithValidateConfig = &integrationResource{}
+	_ resource.ResourceWithModifyPlan     = &integrationResource{}
+)
+
+// The data platforms an integration can connect to.
+var integrationTypes = []string{"snowflake", "databricks", "redshift"}
+
+// The key of the integration's scope that its type is stored under.
+const integrationTypeKey = "type"
+
+// NewIntegrationResource is a helper function to simplify the provider implementation.
+func NewIntegrationResource() resource.Resource {
+	return &integrationResource{}
+}
+
+// integrationResource is the resource implementation.
+type integrationResource struct {
+	CLI            tectonclient.Client
+	Notifier       *Notifier
+	DisableDestroy bool
+}
+
+// integrationResourceModel maps the resource schema data.
+type integrationResourceModel struct {
+	ID             types.String            `tfsdk:"id"`
+	LastUpdated    types.String            `tfsdk:"last_updated"`
+	Name           types.String            `tfsdk:"name"`
+	Type           types.String            `tfsdk:"type"`
+	Settings       map[string]types.String `tfsdk:"settings"`
+	SecretSettings map[string]types.String `tfsdk:"secret_settings"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *integrationResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.CLI = providerData.CLI
+	r.Notifier = providerData.Notifier
+	r.DisableDestroy = providerData.DisableDestroy
+}
+
+// Metadata returns the resource type name.
+func (r *integrationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_integration"
+}
+
+// Schema defines the schema for the resource.
+func (r *integrationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	keyValidator := mapvalidator.KeysAre(
+		stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z0-9_-]+$`), "must contain only alphanumeric characters, hyphens, or underscores"),
+		stringvalidator.NoneOf(integrationTypeKey),
+	)
+	resp.Schema = schema.Schema{
+		Description: "Manages the connection settings of a data platform integration, e.g. a Snowflake account, in a Tecton secret scope of the same name, " +
+			"so that data platform connectivity is provisioned alongside workspaces. Data sources in feature repositories reference the settings with " +
+			"`Secret(scope=\"<name>\", key=\"<setting>\")`. The integration's type is stored under the `type` key. " +
+			"Secret values can't be read back from Tecton, so only settings that were deleted outside of Terraform are detected as drift. Requires a tecton CLI that supports `tecton secrets`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier for this integration. Equal to the name.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Description: "Timestamp of the last Terraform update of the integration.",
+				Computed:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the integration, which is also the name of its secret scope.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z0-9_-]+$`), "must contain only alphanumeric characters, hyphens, or underscores"),
+				},
+			},
+			"type": schema.StringAttribute{
+				Description: "The data platform. One of (\"snowflake\", \"databricks\", \"redshift\").",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(integrationTypes...),
+				},
+			},
+			"settings": schema.MapAttribute{
+				Description: "Connection settings that aren't secret, e.g. `{ url = \"https://myaccount.snowflakecomputing.com\", warehouse = \"TECTON\" }`. " +
+					"Keys must not be `type` or also be in `secret_settings`.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators:  []validator.Map{keyValidator},
+			},
+			"secret_settings": schema.MapAttribute{
+				Description: "Secret connection settings, e.g. `{ password = var.snowflake_password }`. Keys must not be `type` or also be in `settings`.",
+				Optional:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+				Validators:  []validator.Map{keyValidator},
+			},
+		},
+	}
+}
+
+// ValidateConfig checks that no key is in both `settings` and `secret_settings`.
+func (r *integrationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config integrationResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for key := range config.Settings {
+		if _, ok := config.SecretSettings[key]; ok {
+			AddAttributeError(
+				&resp.Diagnostics,
+				path.Root("secret_settings").AtMapKey(key),
+				ErrorCodeInvalidConfig,
+				"Duplicate Integration Setting",
+				fmt.Sprintf("The setting '%v' is in both `settings` and `secret_settings`.", key),
+			)
+		}
+	}
+}
+
+// ModifyPlan fails plans that destroy the integration if the provider's `disable_destroy` is set.
+func (r *integrationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_integration", "ModifyPlan")
+	if !req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+	var name types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name"), &name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	CheckDestroyAllowed(r.DisableDestroy, fmt.Sprintf("tecton_integration '%v'", name.ValueString()), &resp.Diagnostics)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *integrationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_integration", "Create")
+	// Retrieve values from plan
+	var plan integrationResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Report the changes made in Tecton to the notification webhook, even if creation fails
+	cli, changes := r.CLI.RecordingChanges()
+	defer r.Notifier.Notify(ctx, fmt.Sprintf("tecton_integration '%v'", plan.Name.ValueString()), changes, &resp.Diagnostics)
+
+	// An existing scope must be imported, so that settings aren't silently overwritten
+	existing, err := cli.ReadIntegration(ctx, plan.Name.ValueString())
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read integration", err)
+		return
+	}
+	if existing.Exists {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeAlreadyExists,
+			"Integration Already Exists",
+			fmt.Sprintf("The secret scope '%v' already exists. Delete it or choose another name.", plan.Name.ValueString()),
+		)
+		return
+	}
+
+	err = cli.ApplyIntegration(ctx, plan.Name.ValueString(), integrationValues(&plan), nil)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to create integration", err)
+		return
+	}
+
+	// Generated computed values
+	plan.ID = plan.Name
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *integrationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_integration", "Read")
+	// Get current state
+	var state integrationResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	integration, err := r.CLI.ReadIntegration(ctx, state.Name.ValueString())
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read integration", err)
+		return
+	}
+	if !integration.Exists {
+		tflog.Info(ctx, fmt.Sprintf("Integration '%v' no longer exists, so it's removed from the state", state.Name.ValueString()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Settings that were deleted outside of Terraform are dropped from the state, so that the next
+	// plan sets them again
+	state.Settings = filterIntegrationSettings(state.Settings, integration.Keys)
+	state.SecretSettings = filterIntegrationSettings(state.SecretSettings, integration.Keys)
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *integrationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_integration", "Update")
+	// Retrieve values from plan
+	var plan integrationResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Also retrieve current state
+	var state integrationResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cli, changes := r.CLI.RecordingChanges()
+	defer r.Notifier.Notify(ctx, fmt.Sprintf("tecton_integration '%v'", plan.Name.ValueString()), changes, &resp.Diagnostics)
+
+	// Every value is set again, since the current values can't be read to compare them
+	values := integrationValues(&plan)
+	var deleteKeys []string
+	for key := range integrationValues(&state) {
+		if _, ok := values[key]; !ok {
+			deleteKeys = append(deleteKeys, key)
+		}
+	}
+	slices.Sort(deleteKeys)
+	err := cli.ApplyIntegration(ctx, plan.Name.ValueString(), values, deleteKeys)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to update integration", err)
+		return
+	}
+
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *integrationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_integration", "Delete")
+	// Get current state
+	var state integrationResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !CheckDestroyAllowed(r.DisableDestroy, fmt.Sprintf("tecton_integration '%v'", state.Name.ValueString()), &resp.Diagnostics) {
+		return
+	}
+
+	cli, changes := r.CLI.RecordingChanges()
+	defer r.Notifier.Notify(ctx, fmt.Sprintf("tecton_integration '%v'", state.Name.ValueString()), changes, &resp.Diagnostics)
+
+	err := cli.DeleteIntegration(ctx, state.Name.ValueString())
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to delete integration", err)
+	}
+}
+
+// Returns every key and value stored in the integration's secret scope.
+func integrationValues(m *integrationResourceModel) map[string]string {
+	values := map[string]string{integrationTypeKey: m.Type.ValueString()}
+	for key, value := range m.Settings {
+		values[key] = value.ValueString()
+	}
+	for key, value := range m.SecretSettings {
+		values[key] = value.ValueString()
+	}
+	return values
+}
+
+// Returns the settings whose keys are in keys. A nil map stays nil, so that an unset attribute
+// doesn't show a diff.
+func filterIntegrationSettings(settings map[string]types.String, keys []string) map[string]types.String {
+	if settings == nil {
+		return nil
+	}
+	filtered := map[string]types.String{}
+	for key, value := range settings {
+		if slices.Contains(keys, key) {
+			filtered[key] = value
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func integrationSettings(values map[string]string) tftypes.Value {
+	settings := map[string]tftypes.Value{}
+	for key, value := range values {
+		settings[key] = tftypes.NewValue(tftypes.String, value)
+	}
+	return tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, settings)
+}
+
+func TestIntegrationResourceValidateConfig(t *testing.T) {
+	testCases := map[string]struct {
+		settings       map[string]string
+		secretSettings map[string]string
+		isError        bool
+	}{
+		"distinct keys": {
+			settings:       map[string]string{"url": "https://example.snowflakecomputing.com"},
+			secretSettings: map[string]string{"password": "secret"},
+		},
+		"duplicate key": {
+			settings:       map[string]string{"user": "tecton"},
+			secretSettings: map[string]string{"user": "tecton"},
+			isError:        true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := NewIntegrationResource()
+			plan := resourcePlan(t, r, map[string]tftypes.Value{
+				"name":            tftypes.NewValue(tftypes.String, "snowflake"),
+				"type":            tftypes.NewValue(tftypes.String, "snowflake"),
+				"settings":        integrationSettings(testCase.settings),
+				"secret_settings": integrationSettings(testCase.secretSettings),
+			})
+			req := fwresource.ValidateConfigRequest{Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw}}
+			resp := fwresource.ValidateConfigResponse{}
+			r.(fwresource.ResourceWithValidateConfig).ValidateConfig(context.Background(), req, &resp)
+			if resp.Diagnostics.HasError() != testCase.isError {
+				t.Fatalf("expected error: %v, got: %v", testCase.isError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestIntegrationResourceRead_dropsDeletedSettings(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"exists": true, "keys": ["type", "url"]}`, "")
+
+	ctx := context.Background()
+	r := NewIntegrationResource()
+	plan := resourcePlan(t, r, map[string]tftypes.Value{
+		"id":              tftypes.NewValue(tftypes.String, "snowflake"),
+		"name":            tftypes.NewValue(tftypes.String, "snowflake"),
+		"type":            tftypes.NewValue(tftypes.String, "snowflake"),
+		"settings":        integrationSettings(map[string]string{"url": "https://example.snowflakecomputing.com", "warehouse": "TECTON"}),
+		"secret_settings": integrationSettings(map[string]string{"password": "secret"}),
+	})
+	req := fwresource.ReadRequest{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+	resp := fwresource.ReadResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+	r.Read(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	var state integrationResourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("failed to read state: %v", resp.Diagnostics)
+	}
+	if len(state.Settings) != 1 || state.Settings["url"].ValueString() != "https://example.snowflakecomputing.com" {
+		t.Errorf("unexpected settings: %v", state.Settings)
+	}
+	if len(state.SecretSettings) != 0 {
+		t.Errorf("expected the deleted secret setting to be dropped, got: %v", state.SecretSettings)
+	}
+}
+
+func TestIntegrationResourceRead_removedScope(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"exists": false, "keys": []}`, "")
+
+	ctx := context.Background()
+	r := NewIntegrationResource()
+	plan := resourcePlan(t, r, map[string]tftypes.Value{
+		"id":   tftypes.NewValue(tftypes.String, "snowflake"),
+		"name": tftypes.NewValue(tftypes.String, "snowflake"),
+		"type": tftypes.NewValue(tftypes.String, "snowflake"),
+	})
+	req := fwresource.ReadRequest{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+	resp := fwresource.ReadResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+	r.Read(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Errorf("expected the resource to be removed from the state")
+	}
+}
@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &materializationFailuresDataSource{}
+	_ datasource.DataSourceWithConfigure = &materializationFailuresDataSource{}
+)
+
+// The default for `window`.
+const defaultMaterializationFailuresWindow = 24 * time.Hour
+
+// NewMaterializationFailuresDataSource is a helper function to simplify the provider implementation.
+func NewMaterializationFailuresDataSource() datasource.DataSource {
+	return &materializationFailuresDataSource{}
+}
+
+// materializationFailuresDataSource summarizes the failed materialization jobs in a workspace.
+type materializationFailuresDataSource struct {
+	CLI tectonclient.Client
+}
+
+// materializationFailuresDataSourceModel maps the data source schema data.
+type materializationFailuresDataSourceModel struct {
+	ID             types.String                              `tfsdk:"id"`
+	Workspace      types.String                              `tfsdk:"workspace"`
+	Window         types.String                              `tfsdk:"window"`
+	FailedJobCount types.Int64                               `tfsdk:"failed_job_count"`
+	FeatureViews   []materializationFailuresFeatureViewModel `tfsdk:"feature_views"`
+}
+
+// materializationFailuresFeatureViewModel maps an element of `feature_views`.
+type materializationFailuresFeatureViewModel struct {
+	Name              types.String `tfsdk:"name"`
+	FailedJobCount    types.Int64  `tfsdk:"failed_job_count"`
+	LastFailureID     types.String `tfsdk:"last_failure_id"`
+	LastFailureState  types.String `tfsdk:"last_failure_state"`
+	LastFailureTime   types.String `tfsdk:"last_failure_time"`
+	LastFailureRunURL types.String `tfsdk:"last_failure_run_url"`
+}
+
+// The JSON output of the `materialization_failures.py` script.
+type materializationFailures struct {
+	FeatureViews []struct {
+		Name           string `json:"name"`
+		FailedJobCount int64  `json:"failed_job_count"`
+		LastFailure    struct {
+			ID        string `json:"id"`
+			State     string `json:"state"`
+			CreatedAt string `json:"created_at"`
+			RunURL    string `json:"run_url"`
+		} `json:"last_failure"`
+	} `json:"feature_views"`
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *materializationFailuresDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.CLI = providerData.CLI
+}
+
+// Metadata returns the data source type name.
+func (d *materializationFailuresDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_materialization_failures"
+}
+
+// Schema defines the schema for the data source.
+func (d *materializationFailuresDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Summarizes the failed materialization jobs of the feature views in a workspace over a recent time window, " +
+			"so that `check` blocks or external alerting can gate releases on the health of feature pipelines.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Equal to the workspace name.",
+				Computed:    true,
+			},
+			"workspace": schema.StringAttribute{
+				Description: "The name of the workspace.",
+				Required:    true,
+			},
+			"window": schema.StringAttribute{
+				Description: "How far back to look for failed jobs, as a Go duration string, e.g. \"6h\". Only jobs created within the window are counted. Defaults to \"24h\".",
+				Optional:    true,
+				Validators: []validator.String{
+					durationValidator(),
+				},
+			},
+			"failed_job_count": schema.Int64Attribute{
+				Description: "The number of failed materialization jobs in the workspace within the window.",
+				Computed:    true,
+			},
+			"feature_views": schema.ListNestedAttribute{
+				Description: "The feature views with at least one failed materialization job within the window, sorted by name.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "The name of the feature view.",
+							Computed:    true,
+						},
+						"failed_job_count": schema.Int64Attribute{
+							Description: "The number of failed materialization jobs of the feature view within the window.",
+							Computed:    true,
+						},
+						"last_failure_id": schema.StringAttribute{
+							Description: "The ID of the most recent failed job.",
+							Computed:    true,
+						},
+						"last_failure_state": schema.StringAttribute{
+							Description: "The state of the most recent failed job, e.g. \"ERROR\".",
+							Computed:    true,
+						},
+						"last_failure_time": schema.StringAttribute{
+							Description: "When the most recent failed job was created, as an RFC 3339 timestamp.",
+							Computed:    true,
+						},
+						"last_failure_run_url": schema.StringAttribute{
+							Description: "The URL of the last attempt of the most recent failed job, which links to its logs. Empty if Tecton doesn't report one.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read summarizes the failed materialization jobs in the workspace.
+func (d *materializationFailuresDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.CLI.WithCorrelationID(ctx)
+	defer d.CLI.LogOperationSummary(ctx, "data.tecton_materialization_failures", "Read")
+	var config materializationFailuresDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	window := defaultMaterializationFailuresWindow
+	if !config.Window.IsNull() {
+		var err error
+		window, err = time.ParseDuration(config.Window.ValueString())
+		if err != nil {
+			AddAttributeError(&resp.Diagnostics, path.Root("window"), ErrorCodeInvalidConfig, "Invalid Window", err.Error())
+			return
+		}
+	}
+
+	workspace := config.Workspace.ValueString()
+	since := time.Now().Add(-window).UTC().Format(time.RFC3339)
+	tflog.Info(ctx, fmt.Sprintf("Reading materialization failures in workspace '%v' since %v", workspace, since))
+	output, err := d.CLI.RunScript(ctx, "materialization_failures.py", workspace, since)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read materialization failures", err)
+		return
+	}
+	var failures materializationFailures
+	err = json.Unmarshal(output, &failures)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read materialization failures", &diagnostics.ParseError{Source: "materialization_failures.py", Output: string(output), Err: err})
+		return
+	}
+
+	config.ID = config.Workspace
+	config.FailedJobCount = types.Int64Value(0)
+	config.FeatureViews = []materializationFailuresFeatureViewModel{}
+	for _, featureView := range failures.FeatureViews {
+		config.FailedJobCount = types.Int64Value(config.FailedJobCount.ValueInt64() + featureView.FailedJobCount)
+		config.FeatureViews = append(config.FeatureViews, materializationFailuresFeatureViewModel{
+			Name:              types.StringValue(featureView.Name),
+			FailedJobCount:    types.Int64Value(featureView.FailedJobCount),
+			LastFailureID:     types.StringValue(featureView.LastFailure.ID),
+			LastFailureState:  types.StringValue(featureView.LastFailure.State),
+			LastFailureTime:   types.StringValue(featureView.LastFailure.CreatedAt),
+			LastFailureRunURL: types.StringValue(featureView.LastFailure.RunURL),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestMaterializationFailuresDataSourceRead(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"feature_views": [
+		{"name": "user_clicks", "failed_job_count": 2, "last_failure": {"id": "job2", "state": "ERROR", "created_at": "2024-01-02T00:00:00Z", "run_url": "https://example.com/run/2"}},
+		{"name": "user_transactions", "failed_job_count": 1, "last_failure": {"id": "job3", "state": "ERROR", "created_at": "2024-01-01T00:00:00Z", "run_url": ""}}
+	]}`, "")
+
+	ctx := context.Background()
+	resp := readDataSource(t, NewMaterializationFailuresDataSource(), map[string]tftypes.Value{
+		"workspace": tftypes.NewValue(tftypes.String, "prod"),
+		"window":    tftypes.NewValue(tftypes.String, "6h"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	var state materializationFailuresDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("failed to read state: %v", resp.Diagnostics)
+	}
+	if state.FailedJobCount.ValueInt64() != 3 {
+		t.Errorf("expected 3 failed jobs, got %v", state.FailedJobCount)
+	}
+	if len(state.FeatureViews) != 2 || state.FeatureViews[0].LastFailureID.ValueString() != "job2" {
+		t.Errorf("unexpected feature views: %v", state.FeatureViews)
+	}
+}
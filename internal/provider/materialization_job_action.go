@@ -0,0 +1,268 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ action.Action                   = &materializationJobAction{}
+	_ action.ActionWithConfigure      = &materializationJobAction{}
+	_ action.ActionWithValidateConfig = &materializationJobAction{}
+)
+
+// How often the state of a materialization job is polled while waiting for it to finish.
+var materializationJobPollInterval = 30 * time.Second
+
+// The default for `wait_timeout`.
+const defaultMaterializationJobWaitTimeout = time.Hour
+
+// NewMaterializationJobAction is a helper function to simplify the provider implementation.
+func NewMaterializationJobAction() action.Action {
+	return &materializationJobAction{}
+}
+
+// materializationJobAction triggers a materialization job, e.g. a backfill, for a feature view.
+type materializationJobAction struct {
+	CLI tectonclient.Client
+}
+
+// materializationJobActionModel maps the action schema data.
+type materializationJobActionModel struct {
+	Workspace   types.String `tfsdk:"workspace"`
+	FeatureView types.String `tfsdk:"feature_view"`
+	StartTime   types.String `tfsdk:"start_time"`
+	EndTime     types.String `tfsdk:"end_time"`
+	Online      types.Bool   `tfsdk:"online"`
+	Offline     types.Bool   `tfsdk:"offline"`
+	Overwrite   types.Bool   `tfsdk:"overwrite"`
+	Wait        types.Bool   `tfsdk:"wait"`
+	WaitTimeout types.String `tfsdk:"wait_timeout"`
+}
+
+// Configure adds the provider configured client to the action.
+func (a *materializationJobAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	a.CLI = providerData.CLI
+}
+
+// Metadata returns the action type name.
+func (a *materializationJobAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_materialization_job"
+}
+
+// Schema defines the schema for the action.
+func (a *materializationJobAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Triggers a materialization job for a feature view, e.g. to backfill a time range, when invoked with `terraform apply -invoke`.",
+		Attributes: map[string]schema.Attribute{
+			"workspace": schema.StringAttribute{
+				Description: "The name of the workspace containing the feature view.",
+				Required:    true,
+			},
+			"feature_view": schema.StringAttribute{
+				Description: "The name of the feature view to materialize.",
+				Required:    true,
+			},
+			"start_time": schema.StringAttribute{
+				Description: "The start of the time range to materialize, as an RFC 3339 timestamp, e.g. 2024-01-01T00:00:00Z.",
+				Required:    true,
+			},
+			"end_time": schema.StringAttribute{
+				Description: "The end of the time range to materialize, as an RFC 3339 timestamp. Must be after `start_time`.",
+				Required:    true,
+			},
+			"online": schema.BoolAttribute{
+				Description: "True to materialize to the online store. Defaults to false.",
+				Optional:    true,
+			},
+			"offline": schema.BoolAttribute{
+				Description: "True to materialize to the offline store. Defaults to false.",
+				Optional:    true,
+			},
+			"overwrite": schema.BoolAttribute{
+				Description: "True to overwrite data that was already materialized for the time range. Defaults to false.",
+				Optional:    true,
+			},
+			"wait": schema.BoolAttribute{
+				Description: "True to wait for the job to finish, failing the invocation if the job fails. Defaults to false.",
+				Optional:    true,
+			},
+			"wait_timeout": schema.StringAttribute{
+				Description: "How long to wait for the job to finish when `wait` is true, as a Go duration string, e.g. \"30m\". Defaults to \"1h\".",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// ValidateConfig checks the time range and timeout, if they are known.
+func (a *materializationJobAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var config materializationJobActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	parseMaterializationJobConfig(&config, &resp.Diagnostics)
+}
+
+// Invoke triggers the materialization job and optionally waits for it to finish.
+func (a *materializationJobAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	ctx = a.CLI.WithCorrelationID(ctx)
+	defer a.CLI.LogOperationSummary(ctx, "action.tecton_materialization_job", "Invoke")
+	var config materializationJobActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	waitTimeout := parseMaterializationJobConfig(&config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspace := config.Workspace.ValueString()
+	featureView := config.FeatureView.ValueString()
+	tflog.Info(ctx, fmt.Sprintf("Triggering materialization job for feature view '%v' in workspace '%v'", featureView, workspace))
+	args := []string{
+		workspace,
+		featureView,
+		config.StartTime.ValueString(),
+		config.EndTime.ValueString(),
+		strconv.FormatBool(config.Online.ValueBool()),
+		strconv.FormatBool(config.Offline.ValueBool()),
+		strconv.FormatBool(config.Overwrite.ValueBool()),
+	}
+	if a.CLI.SkipInDryRun(ctx, "trigger_materialization_job.py "+tectonclient.ShellJoin(args)) {
+		resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("Skipped the materialization job for feature view '%v', since the provider's `dry_run` is set", featureView)})
+		return
+	}
+	output, err := a.CLI.RunScript(ctx, "trigger_materialization_job.py", args...)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to trigger materialization job", err)
+		return
+	}
+	var job workspaceMaterializationJob
+	err = json.Unmarshal(output, &job)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to trigger materialization job", &diagnostics.ParseError{Source: "trigger_materialization_job.py", Output: string(output), Err: err})
+		return
+	}
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Triggered materialization job %v for feature view '%v'", job.ID, featureView),
+	})
+	if !config.Wait.ValueBool() {
+		return
+	}
+
+	// Poll until the job leaves the pending and running states
+	deadline := time.Now().Add(waitTimeout)
+	for {
+		output, err := a.CLI.RunScript(ctx, "materialization_job_state.py", workspace, featureView, job.ID)
+		if err != nil {
+			AddCommandError(&resp.Diagnostics, "Failed to read materialization job", err)
+			return
+		}
+		err = json.Unmarshal(output, &job)
+		if err != nil {
+			AddCommandError(&resp.Diagnostics, "Failed to read materialization job", &diagnostics.ParseError{Source: "materialization_job_state.py", Output: string(output), Err: err})
+			return
+		}
+
+		switch {
+		case strings.Contains(job.State, "SUCCESS"):
+			resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("Materialization job %v succeeded", job.ID)})
+			return
+		case !strings.Contains(job.State, "PENDING") && !strings.Contains(job.State, "RUNNING"):
+			AddError(
+				&resp.Diagnostics,
+				ErrorCodeCommandFailed,
+				"Materialization Job Failed",
+				fmt.Sprintf("Materialization job %v for feature view '%v' finished in state %v.", job.ID, featureView, job.State),
+			)
+			return
+		case time.Now().After(deadline):
+			AddError(
+				&resp.Diagnostics,
+				ErrorCodeCommandFailed,
+				"Timed Out Waiting For Materialization Job",
+				fmt.Sprintf(
+					"Materialization job %v for feature view '%v' is still %v after %v. The job has not been cancelled.",
+					job.ID,
+					featureView,
+					job.State,
+					waitTimeout,
+				),
+			)
+			return
+		}
+
+		resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("Materialization job %v is %v", job.ID, job.State)})
+		select {
+		case <-ctx.Done():
+			AddError(&resp.Diagnostics, ErrorCodeCommandFailed, "Materialization Job Wait Cancelled", ctx.Err().Error())
+			return
+		case <-time.After(materializationJobPollInterval):
+		}
+	}
+}
+
+// Validates the known time range and timeout in config, adding errors to diags. Returns the wait
+// timeout.
+func parseMaterializationJobConfig(config *materializationJobActionModel, diags *diag.Diagnostics) time.Duration {
+	var startTime, endTime time.Time
+	var err error
+	if !config.StartTime.IsUnknown() && !config.StartTime.IsNull() {
+		startTime, err = time.Parse(time.RFC3339, config.StartTime.ValueString())
+		if err != nil {
+			AddAttributeError(diags, path.Root("start_time"), ErrorCodeInvalidConfig, "Invalid Start Time", fmt.Sprintf("Expected an RFC 3339 timestamp: %v", err.Error()))
+		}
+	}
+	if !config.EndTime.IsUnknown() && !config.EndTime.IsNull() {
+		endTime, err = time.Parse(time.RFC3339, config.EndTime.ValueString())
+		if err != nil {
+			AddAttributeError(diags, path.Root("end_time"), ErrorCodeInvalidConfig, "Invalid End Time", fmt.Sprintf("Expected an RFC 3339 timestamp: %v", err.Error()))
+		}
+	}
+	if !startTime.IsZero() && !endTime.IsZero() && !endTime.After(startTime) {
+		AddAttributeError(diags, path.Root("end_time"), ErrorCodeInvalidConfig, "Invalid End Time", "end_time must be after start_time.")
+	}
+
+	waitTimeout := defaultMaterializationJobWaitTimeout
+	if !config.WaitTimeout.IsUnknown() && !config.WaitTimeout.IsNull() {
+		waitTimeout, err = time.ParseDuration(config.WaitTimeout.ValueString())
+		if err != nil || waitTimeout <= 0 {
+			AddAttributeError(diags, path.Root("wait_timeout"), ErrorCodeInvalidConfig, "Invalid Wait Timeout", "Expected a positive Go duration string, e.g. \"30m\".")
+		}
+	}
+	return waitTimeout
+}
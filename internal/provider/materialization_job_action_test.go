@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestMaterializationJobActionValidateConfig(t *testing.T) {
+	testCases := map[string]struct {
+		startTime   string
+		endTime     string
+		waitTimeout tftypes.Value
+		isError     bool
+	}{
+		"valid": {
+			startTime:   "2024-01-01T00:00:00Z",
+			endTime:     "2024-02-01T00:00:00Z",
+			waitTimeout: tftypes.NewValue(tftypes.String, "30m"),
+		},
+		"invalid start time": {
+			startTime:   "2024-01-01",
+			endTime:     "2024-02-01T00:00:00Z",
+			waitTimeout: tftypes.NewValue(tftypes.String, nil),
+			isError:     true,
+		},
+		"end before start": {
+			startTime:   "2024-02-01T00:00:00Z",
+			endTime:     "2024-01-01T00:00:00Z",
+			waitTimeout: tftypes.NewValue(tftypes.String, nil),
+			isError:     true,
+		},
+		"invalid timeout": {
+			startTime:   "2024-01-01T00:00:00Z",
+			endTime:     "2024-02-01T00:00:00Z",
+			waitTimeout: tftypes.NewValue(tftypes.String, "forever"),
+			isError:     true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := action.ValidateConfigRequest{
+				Config: actionConfig(t, NewMaterializationJobAction(), map[string]tftypes.Value{
+					"start_time":   tftypes.NewValue(tftypes.String, testCase.startTime),
+					"end_time":     tftypes.NewValue(tftypes.String, testCase.endTime),
+					"wait_timeout": testCase.waitTimeout,
+				}),
+			}
+			resp := action.ValidateConfigResponse{}
+			NewMaterializationJobAction().(action.ActionWithValidateConfig).ValidateConfig(context.Background(), req, &resp)
+			if resp.Diagnostics.HasError() != testCase.isError {
+				t.Fatalf("expected error: %v, got: %v", testCase.isError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestMaterializationJobActionInvoke(t *testing.T) {
+	pollInterval := materializationJobPollInterval
+	materializationJobPollInterval = time.Millisecond
+	t.Cleanup(func() { materializationJobPollInterval = pollInterval })
+
+	testCases := map[string]struct {
+		state   string
+		isError bool
+	}{
+		"succeeded": {
+			state: "SUCCESS",
+		},
+		"failed": {
+			state:   "ERROR",
+			isError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fakeTectonPythonCLI(t, `{"id": "job1", "state": "`+testCase.state+`"}`, "")
+			req := action.InvokeRequest{
+				Config: actionConfig(t, NewMaterializationJobAction(), map[string]tftypes.Value{
+					"workspace":    tftypes.NewValue(tftypes.String, "prod"),
+					"feature_view": tftypes.NewValue(tftypes.String, "user_transactions"),
+					"start_time":   tftypes.NewValue(tftypes.String, "2024-01-01T00:00:00Z"),
+					"end_time":     tftypes.NewValue(tftypes.String, "2024-02-01T00:00:00Z"),
+					"offline":      tftypes.NewValue(tftypes.Bool, true),
+					"wait":         tftypes.NewValue(tftypes.Bool, true),
+				}),
+			}
+			var messages []string
+			resp := action.InvokeResponse{
+				SendProgress: func(event action.InvokeProgressEvent) {
+					messages = append(messages, event.Message)
+				},
+			}
+			NewMaterializationJobAction().Invoke(context.Background(), req, &resp)
+			if resp.Diagnostics.HasError() != testCase.isError {
+				t.Fatalf("expected error: %v, got: %v", testCase.isError, resp.Diagnostics)
+			}
+			if len(messages) == 0 || messages[0] != "Triggered materialization job job1 for feature view 'user_transactions'" {
+				t.Errorf("unexpected progress messages: %v", messages)
+			}
+		})
+	}
+}
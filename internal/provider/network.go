@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"os"
+)
+
+// Parses the provider's `https_proxy`, which must be an http or https URL with a host.
+func parseProxyURL(rawUrl string) (*neturl.URL, error) {
+	proxy, err := neturl.Parse(rawUrl)
+	if err != nil {
+		return nil, fmt.Errorf("'%v' is not a valid URL: %w", rawUrl, err)
+	}
+	if (proxy.Scheme != "http" && proxy.Scheme != "https") || proxy.Host == "" {
+		return nil, fmt.Errorf("'%v' must be an http or https URL, e.g. http://proxy.example.com:3128", rawUrl)
+	}
+	return proxy, nil
+}
+
+// Returns the system's certificate pool with the PEM certificates in the file at path added, for
+// the provider's `ca_bundle_path`.
+func loadCABundle(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("'%v' contains no PEM certificates.", path)
+	}
+	return pool, nil
+}
+
+// Returns the transport for the provider's own HTTP requests, e.g. for OAuth tokens and
+// notifications, which goes through proxy and trusts the certificates in rootCAs if they're set, so
+// that it reaches the same servers as the tecton CLI. If skipVerify is true, certificates aren't
+// verified at all, for the provider's `insecure_skip_tls_verify`.
+func newHTTPTransport(proxy *neturl.URL, rootCAs *x509.CertPool, skipVerify bool) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if rootCAs != nil || skipVerify {
+		transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs, InsecureSkipVerify: skipVerify, MinVersion: tls.VersionTLS12}
+	}
+	return transport
+}
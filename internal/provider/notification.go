@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// How long to wait for the notification webhook to respond.
+const notificationTimeout = 10 * time.Second
+
+// NotificationWebhookModel maps the provider's `notification_webhook` block.
+type NotificationWebhookModel struct {
+	URL    types.String `tfsdk:"url"`
+	Format types.String `tfsdk:"format"`
+}
+
+// Notifier posts a summary of the changes made by each resource operation to a webhook.
+type Notifier struct {
+	URL string
+	// Either "generic" or "slack".
+	Format string
+	Client *http.Client
+}
+
+// Returns a notifier for the provider's `notification_webhook` block, or nil if it isn't set. The
+// notifications are sent with transport, or the default transport if it's nil.
+func NewNotifier(config *NotificationWebhookModel, transport http.RoundTripper) *Notifier {
+	if config == nil {
+		return nil
+	}
+	format := config.Format.ValueString()
+	if format == "" {
+		format = "generic"
+	}
+	return &Notifier{
+		URL:    config.URL.ValueString(),
+		Format: format,
+		Client: &http.Client{Timeout: notificationTimeout, Transport: transport},
+	}
+}
+
+// The body of a "generic" notification.
+type genericNotification struct {
+	Resource      string   `json:"resource"`
+	Changes       []string `json:"changes"`
+	Failed        bool     `json:"failed"`
+	CorrelationID string   `json:"correlation_id,omitempty"`
+}
+
+// The body of a "slack" notification.
+type slackNotification struct {
+	Text string `json:"text"`
+}
+
+// Posts the changes recorded for a resource operation to the webhook, if any were made. The
+// operation is reported as failed if diags has errors. A failure to notify only adds a warning to
+// diags, since the changes have already been made. Does nothing if the notifier is nil.
+func (n *Notifier) Notify(ctx context.Context, resource string, changes *tectonclient.ChangeRecorder, diags *diag.Diagnostics) {
+	if n == nil || len(changes.Changes()) == 0 {
+		return
+	}
+
+	var body any = genericNotification{
+		Resource:      resource,
+		Changes:       changes.Changes(),
+		Failed:        diags.HasError(),
+		CorrelationID: tectonclient.CorrelationID(ctx),
+	}
+	if n.Format == "slack" {
+		outcome := "changed"
+		if diags.HasError() {
+			outcome = "partially changed (the operation failed)"
+		}
+		body = slackNotification{
+			Text: fmt.Sprintf("Terraform %v %v:\n• %v", outcome, resource, strings.Join(changes.Changes(), "\n• ")),
+		}
+	}
+	payload, err := json.Marshal(body)
+	if err == nil {
+		tflog.Info(ctx, fmt.Sprintf("Posting %v changes to %v to the notification webhook", len(changes.Changes()), resource))
+		err = n.post(ctx, payload)
+	}
+	if err != nil {
+		AddWarning(
+			diags,
+			ErrorCodeNotificationFailed,
+			"Failed to send change notification",
+			fmt.Sprintf("The changes to %v were made, but the notification webhook failed.\nError: %v", resource, err.Error()),
+		)
+	}
+}
+
+// Posts a JSON payload to the webhook.
+func (n *Notifier) post(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if id := tectonclient.CorrelationID(ctx); id != "" {
+		req.Header.Set(tectonclient.CorrelationIDHeader, id)
+	}
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		// The error includes the URL, which is a secret for Slack webhooks
+		return fmt.Errorf("Request failed: %v", strings.ReplaceAll(err.Error(), n.URL, "<webhook url>"))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Webhook responded with status %v", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+func TestNotifierNotify(t *testing.T) {
+	testCases := map[string]struct {
+		format   string
+		status   int
+		failed   bool
+		expected string
+		warning  bool
+	}{
+		"generic": {
+			format:   "generic",
+			status:   http.StatusOK,
+			expected: `{"resource":"tecton_workspace 'prod'","changes":["Created live workspace 'prod'"],"failed":false}`,
+		},
+		"generic failed operation": {
+			format:   "generic",
+			status:   http.StatusOK,
+			failed:   true,
+			expected: `{"resource":"tecton_workspace 'prod'","changes":["Created live workspace 'prod'"],"failed":true}`,
+		},
+		"slack": {
+			format:   "slack",
+			status:   http.StatusOK,
+			expected: `{"text":"Terraform changed tecton_workspace 'prod':\n• Created live workspace 'prod'"}`,
+		},
+		"webhook error": {
+			format:   "generic",
+			status:   http.StatusInternalServerError,
+			expected: `{"resource":"tecton_workspace 'prod'","changes":["Created live workspace 'prod'"],"failed":false}`,
+			warning:  true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				body = string(data)
+				w.WriteHeader(testCase.status)
+			}))
+			defer server.Close()
+
+			notifier := NewNotifier(&NotificationWebhookModel{URL: types.StringValue(server.URL), Format: types.StringValue(testCase.format)}, nil)
+			changes := &tectonclient.ChangeRecorder{}
+			changes.Record("Created live workspace 'prod'")
+			var diags diag.Diagnostics
+			if testCase.failed {
+				diags.AddError("Failed", "")
+			}
+			notifier.Notify(context.Background(), "tecton_workspace 'prod'", changes, &diags)
+
+			var expected, actual any
+			_ = json.Unmarshal([]byte(testCase.expected), &expected)
+			if err := json.Unmarshal([]byte(body), &actual); err != nil {
+				t.Fatalf("failed to parse notification %q: %v", body, err)
+			}
+			expectedJSON, _ := json.Marshal(expected)
+			actualJSON, _ := json.Marshal(actual)
+			if string(expectedJSON) != string(actualJSON) {
+				t.Errorf("expected notification %v, got %v", string(expectedJSON), string(actualJSON))
+			}
+			if (diags.WarningsCount() > 0) != testCase.warning {
+				t.Errorf("expected warning: %v, got: %v", testCase.warning, diags)
+			}
+		})
+	}
+}
+
+func TestNotifierNotify_noChanges(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	var diags diag.Diagnostics
+	NewNotifier(&NotificationWebhookModel{URL: types.StringValue(server.URL)}, nil).Notify(context.Background(), "tecton_workspace 'prod'", &tectonclient.ChangeRecorder{}, &diags)
+	if called {
+		t.Error("expected no notification without changes")
+	}
+
+	// A nil notifier, i.e. no `notification_webhook` block, does nothing
+	var notifier *Notifier
+	notifier.Notify(context.Background(), "tecton_workspace 'prod'", &tectonclient.ChangeRecorder{}, &diags)
+}
+
+func TestNotifierNotify_correlationID(t *testing.T) {
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(tectonclient.CorrelationIDHeader)
+	}))
+	defer server.Close()
+
+	ctx := tectonclient.Client{}.WithCorrelationID(context.Background())
+	changes := &tectonclient.ChangeRecorder{}
+	changes.Record("Created live workspace 'prod'")
+	var diags diag.Diagnostics
+	NewNotifier(&NotificationWebhookModel{URL: types.StringValue(server.URL)}, nil).Notify(ctx, "tecton_workspace 'prod'", changes, &diags)
+	if header != tectonclient.CorrelationID(ctx) {
+		t.Errorf("expected header '%v', got '%v'", tectonclient.CorrelationID(ctx), header)
+	}
+}
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+var tectonVersionRegex = regexp.MustCompile(`(?m)^Version: *(\S+)`)
+
+// Checks that the environment the provider runs in can reach Tecton, printing a line per check to
+// out. This is run by the `preflight` subcommand of the provider binary to debug runner environments
+// without running a full `terraform plan`. The client is built from config the same way the provider
+// builds it, so that the checks cover its credentials and network settings. Returns true if all checks
+// passed.
+func Preflight(ctx context.Context, out io.Writer, config TectonProviderModel) bool {
+	ok := true
+	pass := func(format string, args ...interface{}) {
+		fmt.Fprintf(out, "[ OK ] "+format+"\n", args...)
+	}
+	warn := func(format string, args ...interface{}) {
+		fmt.Fprintf(out, "[WARN] "+format+"\n", args...)
+	}
+	fail := func(format string, args ...interface{}) {
+		fmt.Fprintf(out, "[FAIL] "+format+"\n", args...)
+		ok = false
+	}
+
+	// The CLI must be installed
+	tectonPath, err := exec.LookPath("tecton")
+	if err != nil {
+		fail("Didn't find 'tecton' executable on the PATH. Please install it via `pip install tecton==%v`", tectonclient.SupportedTectonVersion)
+		return false
+	}
+	pass("Found tecton CLI at %v", tectonPath)
+
+	// The CLI should be the version the provider is tested against
+	output, err := tectonclient.Client{Env: os.Environ()}.Run(ctx, "version")
+	if err != nil {
+		fail("`tecton version` failed.\nError: %v\nOutput: %v", err.Error(), string(output))
+		return false
+	}
+	matches := tectonVersionRegex.FindSubmatch(output)
+	if matches == nil {
+		warn("Could not determine the tecton CLI version from the output of `tecton version`:\n%v", string(output))
+	} else if string(matches[1]) != tectonclient.SupportedTectonVersion {
+		warn("tecton CLI version is %v, but the provider is tested against %v", string(matches[1]), tectonclient.SupportedTectonVersion)
+	} else {
+		pass("tecton CLI version is %v", string(matches[1]))
+	}
+
+	// The URL and credentials must be present and valid
+	err = requireCredentials(&config)
+	if err != nil {
+		fail("%v", err.Error())
+		return false
+	}
+	var diags diag.Diagnostics
+	ctx, client := newProviderClient(ctx, &config, &diags)
+	for _, d := range diags {
+		if d.Severity() == diag.SeverityError {
+			fail("%v: %v", d.Summary(), d.Detail())
+		} else {
+			warn("%v: %v", d.Summary(), d.Detail())
+		}
+	}
+	if diags.HasError() {
+		return false
+	}
+	pass("Tecton URL is %v", client.URL)
+	pass("Credentials configured")
+
+	// The cluster must be reachable with the credentials
+	workspaces, err := client.CLI.ListWorkspaces(ctx)
+	if err != nil {
+		fail("Failed to list workspaces (%v).\n%v", ClassifyError(err), err.Error())
+		return false
+	}
+	pass("Connected to %v and found %v live and %v development workspaces", client.URL, len(workspaces.Lives), len(workspaces.Devs))
+	return ok
+}
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const fakeTectonPreflightScript = `
+case "$1" in
+  version)
+    echo "Version: 0.7.3"
+    ;;
+  workspace)
+    if [ "$TECTON_API_KEY" != "good-key" ]; then
+      echo "Error: Unauthenticated: invalid API key" >&2
+      exit 1
+    fi
+    printf 'Live Workspaces:\n  prod\n\nDevelopment Workspaces:\n* dev\n'
+    ;;
+esac
+`
+
+// Returns the provider configuration with the given attributes, and a private CLI configuration
+// directory so that tests don't write to the user's cache.
+func subcommandConfig(t *testing.T, values map[string]any) TectonProviderModel {
+	t.Helper()
+	if _, ok := values["cli_config_dir"]; !ok && values["use_cli_login"] != true {
+		values["cli_config_dir"] = t.TempDir()
+	}
+	config, err := providerConfigFromJSON(context.Background(), values)
+	if err != nil {
+		t.Fatalf("invalid provider configuration: %v", err)
+	}
+	return config
+}
+
+func TestPreflight(t *testing.T) {
+	fakeTectonCLI(t, fakeTectonPreflightScript)
+
+	var out bytes.Buffer
+	ok := Preflight(context.Background(), &out, subcommandConfig(t, map[string]any{"url": "https://yourcluster.tecton.ai/", "api_key": "good-key"}))
+	if !ok {
+		t.Fatalf("expected preflight to pass, got:\n%v", out.String())
+	}
+	if !strings.Contains(out.String(), "found 1 live and 1 development workspaces") {
+		t.Errorf("expected workspace counts in output, got:\n%v", out.String())
+	}
+}
+
+func TestPreflight_credentialSources(t *testing.T) {
+	fakeTectonCLI(t, fakeTectonPreflightScript)
+	t.Setenv(apiKeyEnvVar, "good-key")
+
+	testCases := map[string]map[string]any{
+		"environment":     {"url": "https://yourcluster.tecton.ai"},
+		"cli login":       {"url": "https://yourcluster.tecton.ai", "use_cli_login": true},
+		"api key command": {"url": "https://yourcluster.tecton.ai", "api_key_command": []string{"echo", "good-key"}},
+	}
+	for name, values := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			if !Preflight(context.Background(), &out, subcommandConfig(t, values)) {
+				t.Fatalf("expected preflight to pass, got:\n%v", out.String())
+			}
+		})
+	}
+}
+
+func TestPreflight_failures(t *testing.T) {
+	fakeTectonCLI(t, fakeTectonPreflightScript)
+	t.Setenv(apiKeyEnvVar, "")
+
+	testCases := map[string]struct {
+		values   map[string]any
+		expected string
+	}{
+		"bad url":         {values: map[string]any{"url": "yourcluster.tecton.ai", "api_key": "good-key"}, expected: "Invalid Tecton URL"},
+		"no api key":      {values: map[string]any{"url": "https://yourcluster.tecton.ai"}, expected: "No API key provided"},
+		"bad api key":     {values: map[string]any{"url": "https://yourcluster.tecton.ai", "api_key": "bad-key"}, expected: "TECTON_AUTH_FAILED"},
+		"failing command": {values: map[string]any{"url": "https://yourcluster.tecton.ai", "api_key_command": []string{"false"}}, expected: "API key command failed"},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			ok := Preflight(context.Background(), &out, subcommandConfig(t, testCase.values))
+			if ok {
+				t.Fatalf("expected preflight to fail, got:\n%v", out.String())
+			}
+			if !strings.Contains(out.String(), testCase.expected) {
+				t.Errorf("expected output containing '%v', got:\n%v", testCase.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestProviderFlags(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "provider.json")
+	err := os.WriteFile(configPath, []byte(`{"url": "https://file.tecton.ai", "okta": {"issuer_url": "https://example.okta.com", "client_id": "abc", "refresh_token": "xyz"}}`), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	flags := flag.NewFlagSet("preflight", flag.ContinueOnError)
+	providerFlags := NewProviderFlags(flags)
+	err = flags.Parse([]string{"-config", configPath, "-url", "https://flag.tecton.ai", "-https-proxy", "http://proxy:3128", "-api-key-command", "vault kv get secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config, err := providerFlags.Config(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Url.ValueString() != "https://flag.tecton.ai" {
+		t.Errorf("expected the flag to take precedence over the file, got %v", config.Url)
+	}
+	if config.Okta == nil || config.Okta.ClientID.ValueString() != "abc" {
+		t.Errorf("expected the okta block from the file, got %v", config.Okta)
+	}
+	if config.HTTPSProxy.ValueString() != "http://proxy:3128" || len(config.ApiKeyCommand) != 4 {
+		t.Errorf("expected the proxy and API key command from the flags, got %v and %v", config.HTTPSProxy, config.ApiKeyCommand)
+	}
+	if !config.UseCliLogin.IsNull() || !config.CliConfigDir.IsNull() {
+		t.Errorf("expected unset settings to be null, got %v and %v", config.UseCliLogin, config.CliConfigDir)
+	}
+}
@@ -5,22 +5,48 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
 	"fmt"
+	"net/http"
+	neturl "net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
 )
 
 // Ensure ScaffoldingProvider satisfies various provider interfaces.
-var _ provider.Provider = &TectonProvider{}
+var (
+	_ provider.Provider                       = &TectonProvider{}
+	_ provider.ProviderWithConfigValidators   = &TectonProvider{}
+	_ provider.ProviderWithListResources      = &TectonProvider{}
+	_ provider.ProviderWithActions            = &TectonProvider{}
+	_ provider.ProviderWithEphemeralResources = &TectonProvider{}
+)
 
 // New is a helper function to simplify provider server and testing implementation.
 func New(version string) func() provider.Provider {
@@ -31,6 +57,13 @@ func New(version string) func() provider.Provider {
 	}
 }
 
+// The environment variables that `url` and `api_key` fall back to when they're not configured, e.g.
+// so that CI pipelines don't need credentials in HCL.
+const (
+	urlEnvVar    = "TECTON_URL"
+	apiKeyEnvVar = "TECTON_API_KEY"
+)
+
 // TectonProvider defines the provider implementation.
 type TectonProvider struct {
 	// version is set to the provider version on release, "dev" when the
@@ -41,21 +74,190 @@ type TectonProvider struct {
 
 // TectonProviderModel maps provider schema data to a Go type.
 type TectonProviderModel struct {
-	Url    types.String `tfsdk:"url"`
-	ApiKey types.String `tfsdk:"api_key"`
+	Url                     types.String              `tfsdk:"url"`
+	ApiKey                  types.String              `tfsdk:"api_key"`
+	ApiKeyCommand           []types.String            `tfsdk:"api_key_command"`
+	ApiKeySecret            types.String              `tfsdk:"api_key_secret"`
+	CredentialHelper        *CredentialHelperModel    `tfsdk:"credential_helper"`
+	OAuth                   *OAuthModel               `tfsdk:"oauth"`
+	Okta                    *OktaModel                `tfsdk:"okta"`
+	UseCliLogin             types.Bool                `tfsdk:"use_cli_login"`
+	AllowInsecure           types.Bool                `tfsdk:"allow_insecure"`
+	InsecureSkipTLSVerify   types.Bool                `tfsdk:"insecure_skip_tls_verify"`
+	HTTPSProxy              types.String              `tfsdk:"https_proxy"`
+	CABundlePath            types.String              `tfsdk:"ca_bundle_path"`
+	CliConfigDir            types.String              `tfsdk:"cli_config_dir"`
+	LogCommands             types.Bool                `tfsdk:"log_commands"`
+	CommandTimeout          types.String              `tfsdk:"command_timeout"`
+	ErrorOutputLimit        types.Int64               `tfsdk:"error_output_limit"`
+	MaxConcurrentOperations types.Int64               `tfsdk:"max_concurrent_operations"`
+	LockRetry               *LockRetryModel           `tfsdk:"lock_retry"`
+	Retry                   *CommandRetryModel        `tfsdk:"retry"`
+	KeyExpiryWarningWindow  types.String              `tfsdk:"key_expiry_warning_window"`
+	MinWorkspaceOwners      types.Int64               `tfsdk:"min_workspace_owners"`
+	DisableDestroy          types.Bool                `tfsdk:"disable_destroy"`
+	DryRun                  types.Bool                `tfsdk:"dry_run"`
+	DefaultRoles            []DefaultRoleModel        `tfsdk:"default_role"`
+	RoleOrder               []types.String            `tfsdk:"role_order"`
+	NotificationWebhook     *NotificationWebhookModel `tfsdk:"notification_webhook"`
 }
 
-// Workspaces stores all the workspaces we've found on the Tecton instance.
-type Workspaces struct {
-	Lives []string
-	Devs  []string
+// DefaultRoleModel maps a `default_role` block, which grants roles to a principal on every new
+// workspace.
+type DefaultRoleModel struct {
+	UserID           types.String   `tfsdk:"user_id"`
+	ServiceAccountID types.String   `tfsdk:"service_account_id"`
+	Roles            []types.String `tfsdk:"roles"`
 }
 
 // ProviderData stores all the data that datasources and resources need from
-// the provider.
+// the provider. A single *ProviderData is shared by every resource, data source,
+// list resource, action and ephemeral resource, whose operations may run
+// concurrently. Its fields are set once in Configure and must not be modified
+// afterwards. Data that changes after Configure, e.g. a cache, must be safe for
+// concurrent use on its own, like WorkspaceData.
 type ProviderData struct {
-	CommandEnv    []string
-	WorkspaceData Workspaces
+	CLI tectonclient.Client
+	// The workspaces, listed when a resource first needs them, which resources may refresh.
+	WorkspaceData      *WorkspaceCache
+	MinWorkspaceOwners int64
+	// Refuse to destroy any resource.
+	DisableDestroy bool
+	// Role grants to apply to every workspace created by the provider.
+	DefaultRoles []roleChange
+	// The roles in order of increasing power.
+	RoleOrder []string
+	// Notified of the changes made by each resource operation, if set.
+	Notifier *Notifier
+	// The rules the cluster validates names with, which are checked at plan time.
+	NameRules tectonclient.NameRules
+	// How long before an API key expires plans warn about it. Zero disables the warnings.
+	KeyExpiryWarningWindow time.Duration
+}
+
+// WorkspaceCache holds a workspace list that's shared by every resource, any of
+// which may refresh it. The list is only fetched when it's first needed, since
+// listing workspaces takes a few seconds and many configurations never need it.
+// It's safe for concurrent use, and a nil cache is empty.
+type WorkspaceCache struct {
+	mu         sync.RWMutex
+	workspaces tectonclient.Workspaces
+	loaded     bool
+	// Lists the workspaces the first time they're needed, if set.
+	load func(ctx context.Context) (tectonclient.Workspaces, error)
+	// Held while the workspaces are listed, so that concurrent readers share a single listing.
+	loadMu sync.Mutex
+}
+
+// Returns a cache that holds workspaces.
+func NewWorkspaceCache(workspaces tectonclient.Workspaces) *WorkspaceCache {
+	return &WorkspaceCache{workspaces: workspaces, loaded: true}
+}
+
+// Returns a cache that lists the workspaces with load when they're first needed.
+func NewLazyWorkspaceCache(load func(ctx context.Context) (tectonclient.Workspaces, error)) *WorkspaceCache {
+	return &WorkspaceCache{load: load}
+}
+
+// Returns the cached workspaces, which must not be modified, listing them first if they haven't
+// been yet. A failed listing isn't cached, so the next call lists them again.
+func (c *WorkspaceCache) Get(ctx context.Context) (tectonclient.Workspaces, error) {
+	if c == nil {
+		return tectonclient.Workspaces{}, nil
+	}
+	workspaces, loaded := c.cached()
+	if loaded || c.load == nil {
+		return workspaces, nil
+	}
+
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+	// Another reader may have listed them while this one waited
+	workspaces, loaded = c.cached()
+	if loaded {
+		return workspaces, nil
+	}
+	tflog.Info(ctx, "Listing workspaces, which are then shared by every resource")
+	workspaces, err := c.load(ctx)
+	if err != nil {
+		return tectonclient.Workspaces{}, err
+	}
+	c.set(workspaces)
+	return workspaces, nil
+}
+
+// Lists the workspaces again with list and replaces the cached workspaces with them. Workspaces
+// that are added or removed while they're listed wait for the listing and are then applied to it,
+// so that a list that was started before the change doesn't overwrite it.
+func (c *WorkspaceCache) Refresh(ctx context.Context, list func(ctx context.Context) (tectonclient.Workspaces, error)) (tectonclient.Workspaces, error) {
+	if c == nil {
+		return list(ctx)
+	}
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+	workspaces, err := list(ctx)
+	if err != nil {
+		return tectonclient.Workspaces{}, err
+	}
+	c.set(workspaces)
+	return workspaces, nil
+}
+
+// Returns the cached workspaces and whether they were listed yet.
+func (c *WorkspaceCache) cached() (tectonclient.Workspaces, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.workspaces, c.loaded
+}
+
+// Replaces the cached workspaces. Waits for a listing in progress, like Add and Remove. Use Refresh
+// to list them again instead, since a list that was started before an Add or Remove would undo it.
+func (c *WorkspaceCache) Set(workspaces tectonclient.Workspaces) {
+	if c == nil {
+		return
+	}
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+	c.set(workspaces)
+}
+
+// Like Set, but the caller must hold loadMu.
+func (c *WorkspaceCache) set(workspaces tectonclient.Workspaces) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.workspaces = workspaces
+	c.loaded = true
+}
+
+// Records that the workspace with the given name was created, so that later reads in the same run
+// find it without listing the workspaces again.
+func (c *WorkspaceCache) Add(name string, isLive bool) {
+	c.update(func(workspaces tectonclient.Workspaces) tectonclient.Workspaces {
+		return workspaces.With(name, isLive)
+	})
+}
+
+// Records that the workspace with the given name was deleted, so that later reads in the same run
+// don't find it.
+func (c *WorkspaceCache) Remove(name string) {
+	c.update(func(workspaces tectonclient.Workspaces) tectonclient.Workspaces {
+		return workspaces.Without(name)
+	})
+}
+
+// Applies change to the cached workspaces. Does nothing if they weren't listed yet, since listing them
+// will include the change, but waits for a listing in progress, which may have started before it.
+func (c *WorkspaceCache) update(change func(tectonclient.Workspaces) tectonclient.Workspaces) {
+	if c == nil {
+		return
+	}
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.loaded {
+		c.workspaces = change(c.workspaces)
+	}
 }
 
 // Metadata returns the provider type name.
@@ -69,24 +271,703 @@ func (p *TectonProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"url": schema.StringAttribute{
-				Description: "The URL for your Tecton Cluster. For example, https://<your_cluster>.tecton.ai",
-				Required:    true,
+				Description: "The URL for your Tecton Cluster. For example, https://<your_cluster>.tecton.ai. Must be an https URL, unless `allow_insecure` is set, and must not include the `/api` suffix, credentials or whitespace. " +
+					"Defaults to the `TECTON_URL` environment variable, and must be set one way or the other.",
+				Optional: true,
 			},
 			"api_key": schema.StringAttribute{
-				Description: "The API key for the account that will be used to query Tecton.",
-				Required:    true,
-				Sensitive:   true,
+				Description: "The API key for the account that will be used to query Tecton. Exactly one of `api_key`, `api_key_command`, `api_key_secret`, `credential_helper`, `oauth`, `okta` and `use_cli_login` must be provided. " +
+					"If none of them is, the `TECTON_API_KEY` environment variable is used, e.g. so that CI pipelines don't need to keep the API key in the configuration.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"api_key_command": schema.ListAttribute{
+				Description: "An external command that is run at configuration time and prints the API key to stdout, so the key never appears in the Terraform configuration or variable files. " +
+					"A list where the first element is the executable and the remaining elements are its arguments, e.g. [\"vault\", \"kv\", \"get\", \"-field=api_key\", \"secret/tecton\"]. " +
+					"Surrounding whitespace is trimmed from the output. Use `credential_helper` instead for commands that also report when the key expires. " +
+					"Exactly one of `api_key`, `api_key_command`, `api_key_secret`, `credential_helper`, `oauth`, `okta` and `use_cli_login` must be provided.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"api_key_secret": schema.StringAttribute{
+				Description: "A reference to a secret holding the API key, which is read at configuration time so the key never appears in the Terraform configuration or state. " +
+					"Either an AWS Secrets Manager ARN, an AWS SSM parameter ARN, or a GCP Secret Manager name of the form projects/<project>/secrets/<name>[/versions/<version>]. " +
+					"Requires the `aws` or `gcloud` CLI respectively. Exactly one of `api_key`, `api_key_command`, `api_key_secret`, `credential_helper`, `oauth`, `okta` and `use_cli_login` must be provided.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^(arn:aws[a-z-]*:(secretsmanager|ssm):|projects/)`),
+						"must be an AWS Secrets Manager ARN, an AWS SSM parameter ARN, or a GCP Secret Manager name",
+					),
+				},
+			},
+			"use_cli_login": schema.BoolAttribute{
+				Description: "If true, the provider doesn't pass an API key to the `tecton` CLI, which then uses the session of an earlier `tecton login` or a `TECTON_API_KEY` " +
+					"environment variable, e.g. for local plans by engineers who are already logged in. Must log in to the cluster at `url`. " +
+					"Exactly one of `api_key`, `api_key_command`, `api_key_secret`, `credential_helper`, `oauth`, `okta` and `use_cli_login` must be provided.",
+				Optional: true,
+			},
+			"allow_insecure": schema.BoolAttribute{
+				Description: "If true, `url` may be an http URL and the `tecton` CLI doesn't verify the cluster's TLS certificate, e.g. for internal development clusters " +
+					"that aren't behind proper TLS yet. The API key can then be intercepted, so a warning is reported whenever this is set. Never use this for production clusters. " +
+					"To keep requiring https and only skip the certificate check, use `insecure_skip_tls_verify` instead. Defaults to false.",
+				Optional: true,
+			},
+			"insecure_skip_tls_verify": schema.BoolAttribute{
+				Description: "If true, neither the `tecton` CLI nor the provider's own requests, e.g. for OAuth tokens and notifications, verify TLS certificates, " +
+					"e.g. for development and staging clusters with self-signed certificates. `url` must still be an https URL. " +
+					"The API key can then be intercepted or the cluster impersonated, so a warning is reported whenever this is set. Prefer `ca_bundle_path`, and never use this for production clusters. " +
+					"Cannot be combined with `ca_bundle_path`. Defaults to false.",
+				Optional: true,
+			},
+			"https_proxy": schema.StringAttribute{
+				Description: "The URL of a proxy, e.g. http://proxy.example.com:3128, that the `tecton` CLI and the provider's own requests, e.g. for OAuth tokens and notifications, connect to Tecton through. " +
+					"Passed to the CLI as `HTTPS_PROXY`. Defaults to the proxy configured in the environment, if any.",
+				Optional: true,
+			},
+			"ca_bundle_path": schema.StringAttribute{
+				Description: "The path of a PEM file with the certificates of a private certificate authority that the `tecton` CLI and the provider's own requests trust, " +
+					"e.g. for a cluster or proxy whose certificate is issued by a corporate CA. The CLI uses only these certificates, so the file must also contain any public CAs it needs, " +
+					"while the provider's own requests trust the system's CAs too. Passed to the CLI as `REQUESTS_CA_BUNDLE` and `SSL_CERT_FILE`. Cannot be combined with `allow_insecure` or `insecure_skip_tls_verify`.",
+				Optional: true,
+			},
+			"cli_config_dir": schema.StringAttribute{
+				Description: "A directory, created if it doesn't exist, that the `tecton` CLI keeps its configuration in instead of the home directory, " +
+					"e.g. the session of `tecton login` and the selected workspace, so that running Terraform on a shared machine neither changes nor depends on the operator's own CLI state. " +
+					"Every command is run with `HOME` set to this directory. With `use_cli_login`, log in to the cluster with `HOME=<cli_config_dir> tecton login` first. " +
+					"Defaults to a private directory in the user's cache directory, e.g. `~/.cache/terraform-provider-tecton`, that is reused by every run with the same `url`, credentials and working directory, except with `use_cli_login`, which then uses the operator's own CLI configuration.",
+				Optional: true,
+			},
+			"log_commands": schema.BoolAttribute{
+				Description: "If true, every `tecton` command run by the provider is logged at DEBUG level (e.g. with `TF_LOG=DEBUG`) along with its exit code, " +
+					"the relevant environment variables and a snippet of its output, so that failing commands can be reproduced by hand. Secrets in the environment and the API key are redacted from both the command and its output. Defaults to false.",
+				Optional: true,
+			},
+			"command_timeout": schema.StringAttribute{
+				Description: "The maximum time a single `tecton` command run by the provider may take before it's killed and the operation fails, as a Go duration string, e.g. \"5m\". " +
+					"Can be overridden by the `command_timeout` attribute of resources that run long commands. Set to \"0s\" to disable the timeout. Defaults to \"10m\".",
+				Optional: true,
+				Validators: []validator.String{
+					durationValidator(),
+				},
+			},
+			"max_concurrent_operations": schema.Int64Attribute{
+				Description: "The maximum number of `tecton` commands and scripts the provider runs at once, across all of its resources, data sources and actions. " +
+					"Terraform runs operations in parallel and every command starts a Python process that calls the Tecton API, so large configurations can otherwise run hundreds of commands at once and hit the cluster's rate limits. " +
+					"Commands beyond the limit wait for a running one to finish. Defaults to no limit.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"error_output_limit": schema.Int64Attribute{
+				Description: "The maximum number of bytes of a failed `tecton` command's output that are included in its error. Longer output is shortened to its start and end, " +
+					"and the full output is written to a file in the system's temporary directory, whose path is included in the error. Set to 0 to include the full output. Defaults to 10000.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"key_expiry_warning_window": schema.StringAttribute{
+				Description: "How long before an API key expires plans warn about it, as a Go duration string, so that keys are rotated before commands start failing. " +
+					"Applies to the provider's own API key if its `credential_helper` reports an `expiration`, and to the API keys of service accounts with a `tecton_access_policy` " +
+					"if the cluster reports their expiration. Set to \"0s\" to disable the warnings. Defaults to \"336h\" (14 days).",
+				Optional: true,
+				Validators: []validator.String{
+					durationValidator(),
+				},
+			},
+			"min_workspace_owners": schema.Int64Attribute{
+				Description: "If set, every workspace must keep at least this many owners. Plans for `tecton_access_policy` resources that would revoke owner " +
+					"from a workspace with this many owners or fewer fail, so that no workspace is left without anyone to manage it.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"disable_destroy": schema.BoolAttribute{
+				Description: "If true, plans that would destroy any resource managed by the provider fail, e.g. to protect a production state from automated destroys. " +
+					"To stop managing a resource without destroying it, remove it with a `removed` block. Defaults to false.",
+				Optional: true,
+			},
+			"dry_run": schema.BoolAttribute{
+				Description: "If true, applies don't change anything in Tecton. Instead, the `tecton` commands that would change Tecton are logged at INFO level (e.g. with `TF_LOG=INFO`), with secrets redacted, " +
+					"and the changes are reported as successful, e.g. so that a platform team can review the role changes of a pipeline before granting it a real API key. " +
+					"Commands that only read from Tecton are still run, so the provider still needs credentials that can read the cluster. " +
+					"The state then records changes that weren't made, so don't use a state that's applied with this for real applies. " +
+					"The `tecton_apply` and `tecton_materialization_job` actions are skipped the same way, except for `tecton_apply` with `plan_only`. " +
+					"The short-lived keys of `tecton_api_token` ephemeral resources are still created, since plans create them too. Defaults to false.",
+				Optional: true,
+			},
+			"role_order": schema.ListAttribute{
+				Description: "The roles in order of increasing power, which is used to sort the roles read from Tecton and to find the roles implied by a more powerful role. " +
+					"Roles that aren't in the list, e.g. custom roles, are sorted alphabetically after the ones that are. Defaults to [\"viewer\", \"operator\", \"editor\", \"owner\"].",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.UniqueValues(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"default_role": schema.ListNestedBlock{
+				Description: "Roles that are granted to a user or service account on every workspace created by a `tecton_workspace` or `tecton_workspace_bootstrap` resource, " +
+					"so that baseline access (e.g. for a platform team or a CI service account) is never forgotten. May be repeated.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"user_id": schema.StringAttribute{
+							Description: "The user ID (e.g. email) to grant the roles to. Exactly one of `user_id` and `service_account_id` must be provided.",
+							Optional:    true,
+							Validators: []validator.String{
+								stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("service_account_id")),
+							},
+						},
+						"service_account_id": schema.StringAttribute{
+							Description: "The service account ID to grant the roles to. Exactly one of `user_id` and `service_account_id` must be provided.",
+							Optional:    true,
+						},
+						"roles": schema.ListAttribute{
+							Description: "The roles to grant on every new workspace. List values must be one of (\"viewer\", \"operator\", \"editor\", \"owner\").",
+							Required:    true,
+							ElementType: types.StringType,
+							Validators: []validator.List{
+								listvalidator.SizeAtLeast(1),
+								listvalidator.ValueStringsAre(stringvalidator.OneOf(validRoles...)),
+								listvalidator.UniqueValues(),
+							},
+						},
+					},
+				},
+			},
+			"lock_retry": schema.SingleNestedBlock{
+				Description: "How `tecton` commands that Tecton rejected because another plan or apply is in progress on the workspace are retried. " +
+					"The delay between attempts starts at `initial_delay` and doubles after every attempt, up to `max_delay`. " +
+					"Without the block, such commands are retried for up to 10 minutes.",
+				Attributes: map[string]schema.Attribute{
+					"timeout": schema.StringAttribute{
+						Description: "How long to keep retrying before the operation fails, as a Go duration string. Set to \"0s\" to disable the retries. Defaults to \"10m\".",
+						Optional:    true,
+						Validators: []validator.String{
+							durationValidator(),
+						},
+					},
+					"initial_delay": schema.StringAttribute{
+						Description: "The delay before the first retry, as a Go duration string. Defaults to \"5s\".",
+						Optional:    true,
+						Validators: []validator.String{
+							durationValidator(),
+						},
+					},
+					"max_delay": schema.StringAttribute{
+						Description: "The maximum delay between retries, as a Go duration string. Defaults to \"1m\".",
+						Optional:    true,
+						Validators: []validator.String{
+							durationValidator(),
+						},
+					},
+				},
+			},
+			"retry": schema.SingleNestedBlock{
+				Description: "How `tecton` commands and scripts that failed with an error that looks transient, e.g. a network error or an unavailable cluster, are retried. " +
+					"Errors that will fail the same way every time, e.g. a permission error, and commands rejected because another plan or apply is in progress, which `lock_retry` covers, aren't retried this way. " +
+					"The delay between attempts starts at `initial_delay` and doubles after every attempt, up to `max_delay`. " +
+					"Without the block, such commands are run up to 3 times.",
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						Description: "The number of times a command is run before the operation fails, including the attempts that waited for `lock_retry`. Set to 1 to disable the retries. Defaults to 3.",
+						Optional:    true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"initial_delay": schema.StringAttribute{
+						Description: "The delay before the first retry, as a Go duration string. Defaults to \"2s\".",
+						Optional:    true,
+						Validators: []validator.String{
+							durationValidator(),
+						},
+					},
+					"max_delay": schema.StringAttribute{
+						Description: "The maximum delay between retries, as a Go duration string. Defaults to \"30s\".",
+						Optional:    true,
+						Validators: []validator.String{
+							durationValidator(),
+						},
+					},
+				},
+			},
+			"notification_webhook": schema.SingleNestedBlock{
+				Description: "A webhook that receives a summary of the workspace and access-control changes the provider made in Tecton, e.g. to feed a change-management channel. " +
+					"A notification is posted after every resource create, update or delete that changed something, including ones that failed part way through. " +
+					"A failed notification is reported as a warning.",
+				Attributes: map[string]schema.Attribute{
+					"url": schema.StringAttribute{
+						Description: "The URL that notifications are posted to, e.g. a Slack incoming webhook URL.",
+						Required:    true,
+						Sensitive:   true,
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(regexp.MustCompile(`^https?://`), "must be an http or https URL"),
+						},
+					},
+					"format": schema.StringAttribute{
+						Description: "The format of the notification body. Either \"generic\", a JSON object with `resource`, `changes`, `failed` and `correlation_id` fields, " +
+							"or \"slack\", a message for a Slack incoming webhook. Defaults to \"generic\".",
+						Optional: true,
+						Validators: []validator.String{
+							stringvalidator.OneOf("generic", "slack"),
+						},
+					},
+				},
+			},
+			"credential_helper": schema.SingleNestedBlock{
+				Description: "An external command that is run at configuration time to obtain the API key, similar to AWS's `credential_process`. " +
+					"The command must print a JSON object of the form `{\"api_key\": \"...\"}` to stdout, optionally with an `expiration` RFC 3339 timestamp, e.g. `\"2030-01-01T00:00:00Z\"`, " +
+					"which is checked against `key_expiry_warning_window`. " +
+					"Exactly one of `api_key`, `api_key_command`, `api_key_secret`, `credential_helper`, `oauth`, `okta` and `use_cli_login` must be provided.",
+				Attributes: map[string]schema.Attribute{
+					"command": schema.ListAttribute{
+						Description: "The command to run, as a list where the first element is the executable and the remaining elements are its arguments. For example, [\"vault\", \"kv\", \"get\", \"-format=json\", \"-field=data\", \"secret/tecton\"].",
+						Required:    true,
+						ElementType: types.StringType,
+						Validators: []validator.List{
+							listvalidator.SizeAtLeast(1),
+						},
+					},
+				},
+			},
+			"oauth": schema.SingleNestedBlock{
+				Description: "OAuth 2.0 client credentials that are exchanged for an access token at configuration time, for clusters that use SSO where API keys can't be minted for every pipeline. " +
+					"The token is written to the `tecton` CLI's configuration as if `tecton login` had been run, in `cli_config_dir` if it's set and otherwise in a private directory in the user's cache directory, " +
+					"and isn't refreshed, so it must stay valid for the whole plan or apply. " +
+					"Exactly one of `api_key`, `api_key_command`, `api_key_secret`, `credential_helper`, `oauth`, `okta` and `use_cli_login` must be provided.",
+				Attributes: map[string]schema.Attribute{
+					"client_id": schema.StringAttribute{
+						Description: "The OAuth client ID.",
+						Required:    true,
+					},
+					"client_secret": schema.StringAttribute{
+						Description: "The OAuth client secret.",
+						Required:    true,
+						Sensitive:   true,
+					},
+					"token_url": schema.StringAttribute{
+						Description: "The https URL of the identity provider's token endpoint, e.g. https://<your_org>.okta.com/oauth2/default/v1/token.",
+						Required:    true,
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(regexp.MustCompile(`^https://`), "must be an https URL"),
+						},
+					},
+					"scopes": schema.ListAttribute{
+						Description: "The scopes to request, if the identity provider requires any.",
+						Optional:    true,
+						ElementType: types.StringType,
+					},
+				},
+			},
+			"okta": schema.SingleNestedBlock{
+				Description: "An Okta session from `tecton login`, for organizations that sign in to Tecton with Okta and prohibit static API keys. " +
+					"The provider uses the session's refresh token to obtain access tokens, and refreshes them before running commands once they're about to expire, " +
+					"so that long applies don't fail part way through. The access token is written to the `tecton` CLI's configuration, in `cli_config_dir` if it's set and otherwise in a private directory in the user's cache directory. " +
+					"Exactly one of `api_key`, `api_key_command`, `api_key_secret`, `credential_helper`, `oauth`, `okta` and `use_cli_login` must be provided.",
+				Attributes: map[string]schema.Attribute{
+					"issuer_url": schema.StringAttribute{
+						Description: "The https URL of the Okta authorization server that issued the session, e.g. https://<your_org>.okta.com/oauth2/default.",
+						Required:    true,
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(regexp.MustCompile(`^https://`), "must be an https URL"),
+						},
+					},
+					"client_id": schema.StringAttribute{
+						Description: "The client ID of the Okta application that `tecton login` signs in with.",
+						Required:    true,
+					},
+					"refresh_token": schema.StringAttribute{
+						Description: "The refresh token of the session, e.g. the `OAUTH_REFRESH_TOKEN` that `tecton login` stores in `~/.tecton/config.tokens`. " +
+							"If Okta rotates refresh tokens, the rotated ones are only kept in memory, so a token that Okta invalidates once it's used must be replaced after every run.",
+						Required:  true,
+						Sensitive: true,
+					},
+				},
 			},
 		},
 	}
 }
 
+// ConfigValidators validates combinations of provider-level attributes.
+func (p *TectonProvider) ConfigValidators(ctx context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		credentialSourceValidator{},
+		urlValidator{},
+	}
+}
+
+// Returns the tecton CLI configuration directory of a provider configuration without
+// `cli_config_dir`, creating it if it doesn't exist. Terraform can't tell the provider when it's
+// done, so rather than a new directory every run that would be left behind with the OAuth session in
+// it, every run with the same url, credential source and working directory reuses the same one. The
+// credential source is the API key itself if it's configured or in the environment, and otherwise
+// the secret, command or OAuth or Okta client it's obtained with, so that a rotated key keeps its
+// directory. Only a hash of them is part of the path. The directory is only accessible by the
+// current user.
+func defaultCliConfigDir(url string, config *TectonProviderModel) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("Failed to find the user's cache directory, set `cli_config_dir` instead.\nError: %w", err)
+	}
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	identity := []string{url, workingDir}
+	switch {
+	case !config.ApiKey.IsNull():
+		identity = append(identity, "api_key", config.ApiKey.ValueString())
+	case !config.ApiKeySecret.IsNull():
+		identity = append(identity, "api_key_secret", config.ApiKeySecret.ValueString())
+	case config.ApiKeyCommand != nil:
+		identity = append(identity, "api_key_command", tectonclient.ShellJoin(StringValues(config.ApiKeyCommand)))
+	case config.CredentialHelper != nil:
+		identity = append(identity, "credential_helper", tectonclient.ShellJoin(StringValues(config.CredentialHelper.Command)))
+	case config.OAuth != nil:
+		identity = append(identity, "oauth", config.OAuth.TokenURL.ValueString(), config.OAuth.ClientID.ValueString())
+	case config.Okta != nil:
+		identity = append(identity, "okta", config.Okta.IssuerURL.ValueString(), config.Okta.ClientID.ValueString())
+	default:
+		identity = append(identity, apiKeyEnvVar, os.Getenv(apiKeyEnvVar))
+	}
+	hash := sha256.Sum256([]byte(strings.Join(identity, "\n")))
+	return providerCacheDir(cacheDir, hex.EncodeToString(hash[:8]))
+}
+
+// Creates the directory name in the provider's directory in cacheDir, both only accessible by the
+// user, and returns its path.
+func providerCacheDir(cacheDir string, name string) (string, error) {
+	dir := filepath.Join(cacheDir, "terraform-provider-tecton", name)
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return "", err
+	}
+	// MkdirAll keeps the permissions of directories that already exist
+	for _, created := range []string{filepath.Dir(dir), dir} {
+		err = os.Chmod(created, 0o700)
+		if err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// Returns the environment variables that make the tecton CLI skip TLS certificate verification when
+// run with env. The Python module that does it is kept in the provider's cache directory, since its
+// content is the same for every provider configuration.
+func insecureTLSEnv(env []string) ([]string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find the user's cache directory.\nError: %w", err)
+	}
+	dir, err := providerCacheDir(cacheDir, "insecure-tls")
+	if err != nil {
+		return nil, err
+	}
+	return tectonclient.InsecureTLSEnv(env, dir)
+}
+
+// Adds an error to diags and returns false if cli doesn't authenticate with an API key. reason says
+// why the operation needs one, e.g. because it calls the feature server, which doesn't accept the
+// session of `tecton login` or an OAuth access token.
+func requireAPIKey(cli tectonclient.Client, reason string, diags *diag.Diagnostics) bool {
+	if cli.HasAPIKey() {
+		return true
+	}
+	AddError(
+		diags,
+		ErrorCodeCredentialsUnavailable,
+		"API key required",
+		fmt.Sprintf(
+			"%v, but the provider isn't configured with one. Configure `api_key`, `api_key_secret`, `api_key_command` or `credential_helper`, "+
+				"or set the %v environment variable, instead of `use_cli_login`, `oauth` or `okta`.",
+			reason,
+			apiKeyEnvVar,
+		),
+	)
+	return false
+}
+
+// The client built from a provider configuration, along with the settings derived from it that
+// the rest of the provider needs.
+type providerClient struct {
+	CLI tectonclient.Client
+	// The normalized URL of the cluster.
+	URL string
+	// The transport of the provider's own HTTP requests, with the configured proxy and CA bundle.
+	Transport *http.Transport
+	// How long before the API key expires a warning is shown.
+	KeyExpiryWarningWindow time.Duration
+}
+
+// Builds the client that runs the commands of a provider configuration: resolves its URL and
+// credentials, and sets up the CLI's environment, configuration directory and network settings.
+// Used by Configure and by the `preflight` and `generate` subcommands, so that they behave the same.
+// Adds an error to diags if config is invalid. Returns ctx with the provider's secrets masked.
+func newProviderClient(ctx context.Context, config *TectonProviderModel, diags *diag.Diagnostics) (context.Context, providerClient) {
+	var err error
+	rawUrl := config.Url.ValueString()
+	if config.Url.IsNull() {
+		rawUrl = os.Getenv(urlEnvVar)
+	}
+	if rawUrl == "" {
+		AddAttributeError(
+			diags,
+			path.Root("url"),
+			ErrorCodeInvalidConfig,
+			"Missing Tecton URL",
+			fmt.Sprintf("Set `url` in the provider configuration or the %v environment variable.", urlEnvVar),
+		)
+		return ctx, providerClient{}
+	}
+
+	// Validate the URL here rather than letting every tecton command fail with a cryptic error
+	url, err := normalizeUrl(rawUrl, config.AllowInsecure.ValueBool())
+	if err != nil {
+		detail := err.Error()
+		if config.Url.IsNull() {
+			detail = fmt.Sprintf("The %v environment variable is invalid. %v", urlEnvVar, detail)
+		}
+		AddAttributeError(diags, path.Root("url"), ErrorCodeInvalidConfig, "Invalid Tecton URL", detail)
+		return ctx, providerClient{}
+	}
+	if config.AllowInsecure.ValueBool() {
+		AddAttributeWarning(
+			diags,
+			path.Root("allow_insecure"),
+			ErrorCodeInsecureConnection,
+			"Insecure Connection to Tecton",
+			fmt.Sprintf(
+				"`allow_insecure` is set, so the connection to %v isn't protected by verified TLS and the API key can be intercepted or the cluster impersonated. "+
+					"Only use this for development clusters that aren't behind proper TLS yet.",
+				url,
+			),
+		)
+	}
+
+	if config.InsecureSkipTLSVerify.ValueBool() {
+		AddAttributeWarning(
+			diags,
+			path.Root("insecure_skip_tls_verify"),
+			ErrorCodeInsecureConnection,
+			"TLS Verification Disabled",
+			fmt.Sprintf(
+				"`insecure_skip_tls_verify` is set, so the certificate of %v and of every other server the provider connects to isn't verified, and the API key can be intercepted or the cluster impersonated. "+
+					"Only use this for development and staging clusters, and prefer `ca_bundle_path` for clusters whose certificate is issued by a private CA.",
+				url,
+			),
+		)
+	}
+
+	// The proxy and CA bundle apply to the provider's own HTTP requests as well as to the CLI
+	var proxy *neturl.URL
+	if !config.HTTPSProxy.IsNull() {
+		proxy, err = parseProxyURL(config.HTTPSProxy.ValueString())
+		if err != nil {
+			AddAttributeError(diags, path.Root("https_proxy"), ErrorCodeInvalidConfig, "Invalid proxy URL", err.Error())
+			return ctx, providerClient{}
+		}
+	}
+	var rootCAs *x509.CertPool
+	if !config.CABundlePath.IsNull() {
+		if config.AllowInsecure.ValueBool() || config.InsecureSkipTLSVerify.ValueBool() {
+			AddAttributeError(
+				diags,
+				path.Root("ca_bundle_path"),
+				ErrorCodeInvalidConfig,
+				"Conflicting TLS Configuration",
+				"`ca_bundle_path` would make the `tecton` CLI verify TLS certificates again, so it cannot be combined with `allow_insecure` or `insecure_skip_tls_verify`.",
+			)
+			return ctx, providerClient{}
+		}
+		rootCAs, err = loadCABundle(config.CABundlePath.ValueString())
+		if err != nil {
+			AddAttributeError(diags, path.Root("ca_bundle_path"), ErrorCodeInvalidConfig, "Invalid CA bundle", err.Error())
+			return ctx, providerClient{}
+		}
+	}
+	transport := newHTTPTransport(proxy, rootCAs, config.InsecureSkipTLSVerify.ValueBool())
+
+	// Resolve the API key, either directly from the configuration, from a secret manager, from
+	// the credential helper, or from the environment if no source is configured
+	apiKey := config.ApiKey.ValueString()
+	if config.ApiKey.IsNull() && config.ApiKeyCommand == nil && config.ApiKeySecret.IsNull() && config.CredentialHelper == nil && config.OAuth == nil && config.Okta == nil && !config.UseCliLogin.ValueBool() {
+		tflog.Info(ctx, "Using the API key from the environment", map[string]interface{}{"env_var": apiKeyEnvVar})
+		apiKey = os.Getenv(apiKeyEnvVar)
+	}
+	if config.ApiKeySecret.ValueString() != "" {
+		apiKey, err = FetchSecretApiKey(ctx, config.ApiKeySecret.ValueString())
+		if err != nil {
+			AddAttributeError(
+				diags,
+				path.Root("api_key_secret"),
+				ErrorCodeCredentialsUnavailable,
+				"Failed to read API key secret",
+				err.Error(),
+			)
+			return ctx, providerClient{}
+		}
+	}
+	if config.ApiKeyCommand != nil {
+		apiKey, _, err = RunCredentialHelper(ctx, &CredentialHelperModel{Command: config.ApiKeyCommand, PlainText: true})
+		if err != nil {
+			AddAttributeError(
+				diags,
+				path.Root("api_key_command"),
+				ErrorCodeCredentialsUnavailable,
+				"API key command failed",
+				err.Error(),
+			)
+			return ctx, providerClient{}
+		}
+	}
+	keyExpiryWarningWindow := defaultKeyExpiryWarningWindow
+	if !config.KeyExpiryWarningWindow.IsNull() {
+		// The duration is validated in the schema
+		keyExpiryWarningWindow, _ = time.ParseDuration(config.KeyExpiryWarningWindow.ValueString())
+	}
+	if config.CredentialHelper != nil {
+		var expiresAt *time.Time
+		apiKey, expiresAt, err = RunCredentialHelper(ctx, config.CredentialHelper)
+		if err != nil {
+			AddAttributeError(
+				diags,
+				path.Root("credential_helper"),
+				ErrorCodeCredentialsUnavailable,
+				"Credential helper failed",
+				err.Error(),
+			)
+			return ctx, providerClient{}
+		}
+		if expiresAt != nil {
+			CheckKeyExpiration("The provider's API key from the `credential_helper`", *expiresAt, time.Now(), keyExpiryWarningWindow, diags)
+		}
+	}
+
+	// Commands and tracebacks may echo the API key, so it's redacted from every log entry and
+	// command error of this provider instance from here on
+	redactor := &diagnostics.Redactor{}
+	redactor.AddSecret(apiKey)
+	redactor.AddSecret(os.Getenv(apiKeyEnvVar))
+	ctx = tectonclient.Client{Redactor: redactor}.WithSecretsMasked(ctx)
+
+	// All Tecton commands for this provider must be issued with these envvars to
+	//		(1) Point to the correct Tecton instance
+	//  	(2) Properly authenticate with the Tecton instance, unless the CLI's own login is used
+	commandEnv := append(os.Environ(), fmt.Sprintf("API_SERVICE=%v/api", url))
+	if config.AllowInsecure.ValueBool() || config.InsecureSkipTLSVerify.ValueBool() {
+		insecureEnv, err := insecureTLSEnv(commandEnv)
+		if err != nil {
+			AddError(diags, ErrorCodeInvalidConfig, "Failed to disable TLS certificate verification for the tecton CLI", err.Error())
+			return ctx, providerClient{}
+		}
+		commandEnv = append(commandEnv, insecureEnv...)
+	}
+	commandEnv = append(commandEnv, tectonclient.NetworkEnv(config.HTTPSProxy.ValueString(), config.CABundlePath.ValueString())...)
+	configDir := config.CliConfigDir.ValueString()
+	var session *tectonclient.OAuthSession
+	if configDir == "" && !config.UseCliLogin.ValueBool() {
+		// Commands change the CLI's configuration, e.g. the selected workspace, and OAuth sessions
+		// are written to it, so each provider configuration gets its own instead of the operator's.
+		// Only the CLI's login needs the operator's configuration.
+		configDir, err = defaultCliConfigDir(url, config)
+		if err != nil {
+			AddError(diags, ErrorCodeInvalidConfig, "Failed to create the tecton CLI configuration directory", err.Error())
+			return ctx, providerClient{}
+		}
+		tflog.Debug(ctx, fmt.Sprintf("Using the tecton CLI configuration directory '%v'", configDir))
+	}
+	if configDir != "" {
+		isolatedEnv, err := tectonclient.IsolatedConfigEnv(ctx, commandEnv, configDir)
+		if err != nil && !config.CliConfigDir.IsNull() {
+			AddAttributeError(diags, path.Root("cli_config_dir"), ErrorCodeInvalidConfig, "Invalid tecton CLI configuration directory", err.Error())
+			return ctx, providerClient{}
+		} else if err != nil {
+			AddError(diags, ErrorCodeInvalidConfig, "Failed to create the tecton CLI configuration directory", err.Error())
+			return ctx, providerClient{}
+		}
+		commandEnv = append(commandEnv, isolatedEnv...)
+	}
+	if config.OAuth != nil {
+		token, expiresAt, err := FetchOAuthToken(ctx, config.OAuth, &http.Client{Timeout: oauthTokenTimeout, Transport: transport})
+		if err == nil {
+			err = tectonclient.WriteOAuthSession(configDir, token, expiresAt)
+		}
+		if err != nil {
+			AddAttributeError(diags, path.Root("oauth"), ErrorCodeCredentialsUnavailable, "Failed to obtain an OAuth access token", err.Error())
+			return ctx, providerClient{}
+		}
+		if expiresAt != nil {
+			tflog.Info(ctx, fmt.Sprintf("Using an OAuth access token that expires at %v", expiresAt.Format(time.RFC3339)))
+		}
+		// An API key in the environment would take precedence over the session
+		commandEnv = append(commandEnv, "TECTON_API_KEY=")
+	} else if config.Okta != nil {
+		session = &tectonclient.OAuthSession{Dir: configDir, FetchToken: newOktaTokenSource(config.Okta, &http.Client{Timeout: oauthTokenTimeout, Transport: transport}).FetchToken}
+		// Fail here rather than in the first command if the session can't be refreshed
+		err = session.Refresh(ctx)
+		if err != nil {
+			AddAttributeError(diags, path.Root("okta"), ErrorCodeCredentialsUnavailable, "Failed to obtain an Okta access token", err.Error())
+			return ctx, providerClient{}
+		}
+		commandEnv = append(commandEnv, "TECTON_API_KEY=")
+	} else if config.UseCliLogin.ValueBool() {
+		tflog.Info(ctx, "Using the tecton CLI's login instead of an API key")
+	} else {
+		commandEnv = append(commandEnv, fmt.Sprintf("TECTON_API_KEY=%v", apiKey))
+	}
+
+	cli := withCommandTimeout(tectonclient.Client{
+		Env:         commandEnv,
+		LogCommands: config.LogCommands.ValueBool(),
+		Timeout:     defaultCommandTimeout,
+		OutputLimit: defaultErrorOutputLimit,
+		LockRetry:   lockRetryPolicy(config.LockRetry),
+		Retry:       commandRetryPolicy(config.Retry),
+		Session:     session,
+		Warnings:    &tectonclient.WarningRecorder{},
+		Metrics:     &tectonclient.MetricsRecorder{},
+		Redactor:    redactor,
+	}, config.CommandTimeout)
+	if !config.ErrorOutputLimit.IsNull() {
+		cli.OutputLimit = int(config.ErrorOutputLimit.ValueInt64())
+	}
+	if config.DryRun.ValueBool() {
+		cli.DryRun = true
+		AddAttributeWarning(
+			diags,
+			path.Root("dry_run"),
+			ErrorCodeDryRun,
+			"Dry Run",
+			"`dry_run` is set, so applies don't change anything in Tecton. The commands that would change Tecton are logged at INFO level instead, and the changes are reported as successful.",
+		)
+	}
+	if !config.MaxConcurrentOperations.IsNull() {
+		// Every copy of the client shares the limiter, so it applies to all of the provider's commands
+		cli.Limiter = tectonclient.NewCommandLimiter(int(config.MaxConcurrentOperations.ValueInt64()))
+	}
+
+	return ctx, providerClient{CLI: cli, URL: url, Transport: transport, KeyExpiryWarningWindow: keyExpiryWarningWindow}
+}
+
 // Configure prepares a Tecton API client for data sources and resources.
 func (p *TectonProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	ctx = tectonclient.Client{}.WithCorrelationID(ctx)
 	// Ensure Tecton CLI is installed
 	_, err := exec.LookPath("tecton")
 	if err != nil {
-		resp.Diagnostics.AddError(
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeCliNotInstalled,
 			"Tecton CLI not installed",
 			"Didn't find 'tecton' executable, which is required to run this provider. Please install it via `pip install tecton`")
 		return
@@ -100,39 +981,66 @@ func (p *TectonProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
-	// All Tecton commands for this provider must be issued with these envvars to
-	//		(1) Point to the correct Tecton instance
-	//  	(2) Properly authenticate with the Tecton instance
-	commandEnv := append(
-		os.Environ(),
-		fmt.Sprintf("TECTON_API_KEY=%v", config.ApiKey.ValueString()),
-		fmt.Sprintf("API_SERVICE=%v/api", config.Url.ValueString()),
-	)
+	ctx, client := newProviderClient(ctx, &config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	cli := client.CLI
+	defer cli.LogOperationSummary(ctx, "provider", "Configure")
 
-	// Pre-fetch all the workspaces since they can only be fetched all at once
-	// and since each call takes a few seconds. This data should only be
-	// used during `terraform plan` (e.g. the `Read` function) and not
-	// `terraform apply` since deletions and creations will make this
-	// data stale.
-	tflog.Info(ctx, "Pre-fetching workspace list")
-	workspaces, err := ListWorkspaces(ctx, commandEnv)
+	// Detect the optional features of the CLI up front, so that resources that need an unsupported
+	// feature fail with a clear error instead of a cryptic CLI error
+	cli.Capabilities = tectonclient.DetectCapabilities(ctx, cli)
+
+	// Read the cluster's name validation rules. Without them, names are only validated by the schema.
+	nameRules, err := cli.GetNameRules(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to list Tecton workspaces",
-			fmt.Sprintf(
-				"Command to list Tecton workspaces failed.\nError: %v",
-				err,
-			),
+		tflog.Warn(ctx, fmt.Sprintf("Failed to read the cluster's name validation rules, so names are only validated by the provider's own rules: %v", err.Error()))
+	}
+
+	// Deprecation warnings are printed by most commands, so the ones printed while configuring the
+	// provider are surfaced on every plan, before the deprecated behavior is removed
+	for _, warning := range cli.Warnings.Warnings() {
+		AddWarning(
+			&resp.Diagnostics,
+			ErrorCodeCliDeprecation,
+			"Tecton CLI deprecation warning",
+			fmt.Sprintf("The tecton CLI printed a warning about a deprecated or incompatible feature, which may break the provider in a future version:\n\n%v", warning),
 		)
-		return
 	}
 
-	providerData := ProviderData{
-		commandEnv,
-		workspaces,
+	var defaultRoles []roleChange
+	for _, defaultRole := range config.DefaultRoles {
+		p := tectonclient.Principal{UserID: defaultRole.UserID.ValueString(), ServiceAccountID: defaultRole.ServiceAccountID.ValueString()}
+		for _, role := range defaultRole.Roles {
+			defaultRoles = append(defaultRoles, roleChange{p, role.ValueString(), true})
+		}
+	}
+
+	roleOrder := validRoles
+	if config.RoleOrder != nil {
+		roleOrder = StringValues(config.RoleOrder)
+	}
+
+	providerData := &ProviderData{
+		cli,
+		// All workspaces can only be listed at once, and each listing takes a few seconds, so they're
+		// listed once when a resource first needs them and shared. Workspaces created and deleted by
+		// this provider are recorded in it, but ones changed outside of it make it stale.
+		NewLazyWorkspaceCache(cli.ListWorkspaces),
+		config.MinWorkspaceOwners.ValueInt64(),
+		config.DisableDestroy.ValueBool(),
+		defaultRoles,
+		roleOrder,
+		NewNotifier(config.NotificationWebhook, client.Transport),
+		nameRules,
+		client.KeyExpiryWarningWindow,
 	}
 	resp.DataSourceData = providerData
 	resp.ResourceData = providerData
+	resp.ListResourceData = providerData
+	resp.ActionData = providerData
+	resp.EphemeralResourceData = providerData
 
 	tflog.Info(ctx, "Configured Tecton provider")
 }
@@ -142,88 +1050,156 @@ func (p *TectonProvider) Resources(ctx context.Context) []func() resource.Resour
 	return []func() resource.Resource{
 		NewWorkspaceResource,
 		NewAccessPolicyResource,
+		NewCliCommandResource,
+		NewBulkRoleAssignmentResource,
+		NewIntegrationResource,
+		NewPythonEnvironmentResource,
+		NewWorkspaceBootstrapResource,
+	}
+}
+
+// ListResources defines the list resources implemented in the provider.
+func (p *TectonProvider) ListResources(ctx context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewWorkspaceListResource,
+		NewAccessPolicyListResource,
+	}
+}
+
+// Actions defines the actions implemented in the provider.
+func (p *TectonProvider) Actions(ctx context.Context) []func() action.Action {
+	return []func() action.Action{
+		NewMaterializationJobAction,
+		NewApplyAction,
+	}
+}
+
+// EphemeralResources defines the ephemeral resources implemented in the provider.
+func (p *TectonProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewApiTokenEphemeralResource,
 	}
 }
 
 // Resources defines the resources implemented in the provider.
 func (p *TectonProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return nil
-}
-
-// Query the complete list of workspaces in the Tecton instance and parse the output.
-func ListWorkspaces(ctx context.Context, commandEnv []string) (Workspaces, error) {
-	// An example output from `tecton workspace list` is the following:
-	// Live Workspaces:
-	//   a
-	//   b
-	//
-	// Development Workspaces:
-	//   c
-	// * d
-	//   e
-	//
-	// Note: in the Tecton CLI output, the '*' character begins the line of the current "active"
-	// workspace. The concept of an active workspace is not used in this provider, but we still
-	// need to handle it in this parsing function.
-	//
-	// The expected output of this function given the above output from Tecton is the following
-	// ```
-	// Workspace{
-	//    Lives: []string{"a", "b"}
-	//    Devs:  []string{"c", "d", "e"}
-	// }
-	// ```
-	cmd := exec.Command("tecton", "workspace", "list")
-	cmd.Env = commandEnv
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		err := fmt.Errorf("%v\nOutput: %v", err.Error(), string(output))
-		return Workspaces{}, err
-	}
-
-	// Assert the output matches the expected regex
-	expectedOutputRegex := regexp.MustCompile(`Live Workspaces:\n(\*? +([^ ]+)\n?)*\nDevelopment Workspaces:\n(\*? +([^ ]+)\n?)*`)
-	matches := expectedOutputRegex.Match(output)
-	if !matches {
-		err := fmt.Errorf(
-			"`tecton workspace list` returned unexpected output.\nExpected to match regex: %v\nGot:\"%v\"",
-			expectedOutputRegex,
-			string(output),
-		)
-		return Workspaces{}, err
+	return []func() datasource.DataSource{
+		NewMaterializationFailuresDataSource,
+		NewGroupMembersDataSource,
+		NewGroupDataSource,
+		NewFeatureViewDataSource,
+		NewFeatureServiceSchemaDataSource,
+		NewAccessReportDataSource,
+		NewWorkspaceDataSource,
+		NewWorkspaceComparisonDataSource,
+		NewAssertionDataSource,
+		NewUsageDataSource,
+		NewFeatureViewUsageDataSource,
 	}
+}
 
-	lines := strings.Split(string(output), "\n")
+// Adds an error and returns false if the provider's `disable_destroy` is set. name identifies the
+// resource that would be destroyed, e.g. "tecton_workspace 'prod'".
+func CheckDestroyAllowed(disableDestroy bool, name string, diags *diag.Diagnostics) bool {
+	if !disableDestroy {
+		return true
+	}
+	AddError(
+		diags,
+		ErrorCodeUnsafeOperation,
+		"Destroy Disabled",
+		fmt.Sprintf(
+			"Refusing to destroy %v because `disable_destroy` is set on the provider. "+
+				"To stop managing it without destroying it, remove it with a `removed` block, or unset `disable_destroy`.",
+			name,
+		),
+	)
+	return false
+}
+
+// Adds an attribute error if value breaks the cluster's rule for its kind of name. Unknown and null
+// values are skipped, since they're checked once they're known.
+func CheckNameRule(rule tectonclient.NameRule, value types.String, attributePath path.Path, diags *diag.Diagnostics) {
+	if value.IsUnknown() || value.IsNull() {
+		return
+	}
+	problem := rule.Check(value.ValueString())
+	if problem == "" {
+		return
+	}
+	AddAttributeError(
+		diags,
+		attributePath,
+		ErrorCodeInvalidConfig,
+		"Invalid Name",
+		fmt.Sprintf("'%v' is rejected by the Tecton cluster: it %v.", value.ValueString(), problem),
+	)
+}
 
-	workspaces := Workspaces{}
+// urlValidator checks `url` with normalizeUrl, so that `terraform validate` reports an invalid URL
+// before any command runs. URLs from the TECTON_URL environment variable are checked in Configure.
+type urlValidator struct{}
 
-	// Iterate over the lines and populate the `lives` and `devs` fields of the `Workspaces` object.
-	var liveSection = true
-	for _, line := range lines {
-		if strings.HasPrefix(line, "Live Workspaces:") {
-			liveSection = true
-			continue
-		}
+func (v urlValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
 
-		if strings.HasPrefix(line, "Development Workspaces:") {
-			liveSection = false
-			continue
-		}
+func (v urlValidator) MarkdownDescription(_ context.Context) string {
+	return "`url` must be an https URL of a Tecton cluster, unless `allow_insecure` is set, without the `/api` suffix."
+}
 
-		// One workspace line will start with "*"
-		workspace := strings.TrimPrefix(line, "*")
-		workspace = strings.TrimSpace(workspace)
+func (v urlValidator) ValidateProvider(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var url types.String
+	var allowInsecure types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("url"), &url)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("allow_insecure"), &allowInsecure)...)
+	if resp.Diagnostics.HasError() || url.IsNull() || url.IsUnknown() || allowInsecure.IsUnknown() {
+		return
+	}
+	_, err := normalizeUrl(url.ValueString(), allowInsecure.ValueBool())
+	if err != nil {
+		AddAttributeError(&resp.Diagnostics, path.Root("url"), ErrorCodeInvalidConfig, "Invalid Tecton URL", err.Error())
+	}
+}
 
-		if workspace == "" {
-			continue
-		}
+// Validates the Tecton URL and returns it in a canonical form without any trailing slashes.
+func NormalizeUrl(rawUrl string) (string, error) {
+	return normalizeUrl(rawUrl, false)
+}
 
-		// Add the workspace name to the appropriate field of the `Workspaces` object.
-		if liveSection {
-			workspaces.Lives = append(workspaces.Lives, workspace)
-		} else {
-			workspaces.Devs = append(workspaces.Devs, workspace)
-		}
+// Like NormalizeUrl, but also accepts http URLs if allowInsecure is true, for the provider's
+// `allow_insecure`.
+func normalizeUrl(rawUrl string, allowInsecure bool) (string, error) {
+	if strings.IndexFunc(rawUrl, unicode.IsSpace) >= 0 {
+		return "", fmt.Errorf("Expected a URL without whitespace, e.g. a trailing newline from a variable file, got: %q", rawUrl)
 	}
-	return workspaces, nil
+	parsedUrl, err := neturl.Parse(rawUrl)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse URL '%v': %v", rawUrl, err.Error())
+	}
+	if parsedUrl.User != nil {
+		// The URL is logged with every command, so it must not contain credentials
+		return "", fmt.Errorf("Expected a URL without credentials, got: '%v'. Configure the API key with `api_key` or another credential source instead", parsedUrl.Redacted())
+	}
+	if parsedUrl.Scheme == "http" && !allowInsecure {
+		return "", fmt.Errorf("Expected an https URL such as https://<your_cluster>.tecton.ai, got: '%v'. Set `allow_insecure = true` to connect to a cluster without TLS", rawUrl)
+	}
+	if parsedUrl.Scheme != "https" && parsedUrl.Scheme != "http" {
+		return "", fmt.Errorf("Expected an https URL such as https://<your_cluster>.tecton.ai, got: '%v'", rawUrl)
+	}
+	if parsedUrl.Host == "" {
+		return "", fmt.Errorf("Expected a URL with a host such as https://<your_cluster>.tecton.ai, got: '%v'", rawUrl)
+	}
+	if parsedUrl.RawQuery != "" || parsedUrl.Fragment != "" {
+		return "", fmt.Errorf("Expected a URL without a query string or fragment, got: '%v'", rawUrl)
+	}
+	urlPath := strings.TrimRight(parsedUrl.Path, "/")
+	if urlPath == "/api" || strings.HasSuffix(urlPath, "/api") {
+		return "", fmt.Errorf(
+			"Expected the URL of the Tecton cluster without the '/api' suffix, which is added by the provider. Got: '%v'",
+			rawUrl,
+		)
+	}
+	parsedUrl.Path = urlPath
+	return parsedUrl.String(), nil
 }
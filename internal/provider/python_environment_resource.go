@@ -0,0 +1,386 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &pythonEnvironmentResource{}
+	_ resource.ResourceWithConfigure   = &pythonEnvironmentResource{}
+	_ resource.ResourceWithImportState = &pythonEnvironmentResource{}
+	_ resource.ResourceWithModifyPlan  = &pythonEnvironmentResource{}
+)
+
+// The statuses of a Python environment.
+const (
+	pythonEnvironmentStatusPending = "PENDING"
+	pythonEnvironmentStatusError   = "ERROR"
+)
+
+// The default for `wait_timeout`.
+const defaultPythonEnvironmentWaitTimeout = 20 * time.Minute
+
+// How often the environment is polled while waiting for it to be resolved.
+var pythonEnvironmentPollInterval = 10 * time.Second
+
+// NewPythonEnvironmentResource is a helper function to simplify the provider implementation.
+func NewPythonEnvironmentResource() resource.Resource {
+	return &pythonEnvironmentResource{}
+}
+
+// pythonEnvironmentResource is the resource implementation.
+type pythonEnvironmentResource struct {
+	CLI            tectonclient.Client
+	Notifier       *Notifier
+	DisableDestroy bool
+}
+
+// pythonEnvironmentResourceModel maps the resource schema data.
+type pythonEnvironmentResourceModel struct {
+	ID                   types.String `tfsdk:"id"`
+	LastUpdated          types.String `tfsdk:"last_updated"`
+	Name                 types.String `tfsdk:"name"`
+	Description          types.String `tfsdk:"description"`
+	Requirements         types.String `tfsdk:"requirements"`
+	WaitTimeout          types.String `tfsdk:"wait_timeout"`
+	Status               types.String `tfsdk:"status"`
+	StatusDetails        types.String `tfsdk:"status_details"`
+	ResolvedRequirements types.String `tfsdk:"resolved_requirements"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *pythonEnvironmentResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.CLI = providerData.CLI
+	r.Notifier = providerData.Notifier
+	r.DisableDestroy = providerData.DisableDestroy
+}
+
+// Metadata returns the resource type name.
+func (r *pythonEnvironmentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_python_environment"
+}
+
+// Schema defines the schema for the resource.
+func (r *pythonEnvironmentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a custom Python environment, in which realtime feature views and Rift jobs run. Tecton resolves the requirements " +
+			"asynchronously after the environment is created. Environments can't be changed, so changing anything but `wait_timeout` replaces the environment. " +
+			"Requires a tecton CLI that supports `tecton environment`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of the environment.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Description: "Timestamp of the last Terraform update of the environment.",
+				Computed:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the environment.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`), "must contain only alphanumeric characters, or characters in the set _.-"),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "A description of the environment.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"requirements": schema.StringAttribute{
+				Description: "The packages to install, in the format of a requirements.txt file, e.g. `file(\"${path.module}/requirements.txt\")`.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"wait_timeout": schema.StringAttribute{
+				Description: "After an environment is created, the provider polls it until Tecton has resolved its requirements, so that feature repositories " +
+					"applied afterwards can use it. This is how long to wait, as a Go duration string, e.g. \"30m\". If the environment is still pending, " +
+					"a warning is reported. If it fails to resolve, the apply fails and the environment is replaced on the next apply. " +
+					"Set to \"0s\" to not wait. Defaults to \"20m\".",
+				Optional: true,
+				Validators: []validator.String{
+					durationValidator(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Description: "The resolution status of the environment, e.g. \"PENDING\", \"READY\" or \"ERROR\".",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"status_details": schema.StringAttribute{
+				Description: "Why the environment failed to resolve, if it did.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"resolved_requirements": schema.StringAttribute{
+				Description: "Every package installed in the environment with its exact version, in the format of a requirements.txt file, once the environment is resolved.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// ModifyPlan fails plans that destroy the environment if the provider's `disable_destroy` is set.
+func (r *pythonEnvironmentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_python_environment", "ModifyPlan")
+	if !req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+	var name types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name"), &name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	CheckDestroyAllowed(r.DisableDestroy, fmt.Sprintf("tecton_python_environment '%v'", name.ValueString()), &resp.Diagnostics)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *pythonEnvironmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_python_environment", "Create")
+	// Retrieve values from plan
+	var plan pythonEnvironmentResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Report the changes made in Tecton to the notification webhook, even if creation fails
+	cli, changes := r.CLI.RecordingChanges()
+	defer r.Notifier.Notify(ctx, fmt.Sprintf("tecton_python_environment '%v'", plan.Name.ValueString()), changes, &resp.Diagnostics)
+
+	environment, err := cli.CreatePythonEnvironment(ctx, plan.Name.ValueString(), plan.Description.ValueString(), plan.Requirements.ValueString())
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to create Python environment", err)
+		return
+	}
+
+	// Save the environment before waiting, so that it's tracked even if waiting fails
+	setPythonEnvironment(&plan, environment)
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resolved, err := waitForPythonEnvironment(ctx, cli, environment, plan.WaitTimeout)
+	if err != nil {
+		AddWarning(
+			&resp.Diagnostics,
+			ClassifyError(err),
+			"Python environment not resolved yet",
+			fmt.Sprintf(
+				"Created Python environment '%v', but it isn't resolved yet, so feature repositories that use it may fail. Increase `wait_timeout` to wait longer.\nError: %v",
+				plan.Name.ValueString(),
+				err.Error(),
+			),
+		)
+		return
+	}
+	setPythonEnvironment(&plan, resolved)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	if resolved.Status == pythonEnvironmentStatusError {
+		// The environment is saved, so Terraform marks it as tainted and replaces it on the next apply
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeCommandFailed,
+			"Python environment failed to resolve",
+			fmt.Sprintf("Tecton failed to resolve the requirements of Python environment '%v'.\nDetails: %v", plan.Name.ValueString(), resolved.StatusDetails),
+		)
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *pythonEnvironmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_python_environment", "Read")
+	// Get current state
+	var state pythonEnvironmentResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	environment, err := r.CLI.GetPythonEnvironment(ctx, state.ID.ValueString())
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read Python environment", err)
+		return
+	}
+	if environment == nil {
+		tflog.Info(ctx, fmt.Sprintf("Python environment '%v' no longer exists, so it's removed from the state", state.ID.ValueString()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	setPythonEnvironment(&state, *environment)
+	// The requirements are only read when importing, since Tecton may format them differently than the
+	// configuration, which would replace the environment
+	if state.Requirements.IsNull() && environment.Requirements != "" {
+		state.Requirements = types.StringValue(environment.Requirements)
+	}
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success. Only `wait_timeout`
+// can change without replacing the environment, so nothing is changed in Tecton.
+func (r *pythonEnvironmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_python_environment", "Update")
+	// Retrieve values from plan
+	var plan pythonEnvironmentResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *pythonEnvironmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_python_environment", "Delete")
+	// Get current state
+	var state pythonEnvironmentResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !CheckDestroyAllowed(r.DisableDestroy, fmt.Sprintf("tecton_python_environment '%v'", state.Name.ValueString()), &resp.Diagnostics) {
+		return
+	}
+
+	cli, changes := r.CLI.RecordingChanges()
+	defer r.Notifier.Notify(ctx, fmt.Sprintf("tecton_python_environment '%v'", state.Name.ValueString()), changes, &resp.Diagnostics)
+
+	err := cli.DeletePythonEnvironment(ctx, state.ID.ValueString())
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to delete Python environment", err)
+	}
+}
+
+func (r *pythonEnvironmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Retrieve import ID and save to id attribute
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// Copies the values reported by Tecton to the model.
+func setPythonEnvironment(m *pythonEnvironmentResourceModel, environment tectonclient.PythonEnvironment) {
+	m.ID = types.StringValue(environment.ID)
+	m.Name = types.StringValue(environment.Name)
+	if environment.Description != "" || !m.Description.IsNull() {
+		m.Description = types.StringValue(environment.Description)
+	}
+	m.Status = types.StringValue(environment.Status)
+	m.StatusDetails = types.StringValue(environment.StatusDetails)
+	m.ResolvedRequirements = types.StringValue(environment.ResolvedRequirements)
+}
+
+// Polls the environment until it's no longer pending. timeout is the resource's `wait_timeout`.
+// Returns the environment as it was last read.
+func waitForPythonEnvironment(ctx context.Context, cli tectonclient.Client, environment tectonclient.PythonEnvironment, timeout types.String) (tectonclient.PythonEnvironment, error) {
+	waitTimeout := defaultPythonEnvironmentWaitTimeout
+	if !timeout.IsNull() {
+		var err error
+		waitTimeout, err = time.ParseDuration(timeout.ValueString())
+		if err != nil {
+			return environment, err
+		}
+	}
+	if waitTimeout <= 0 {
+		return environment, nil
+	}
+
+	deadline := time.Now().Add(waitTimeout)
+	for environment.Status == pythonEnvironmentStatusPending {
+		if time.Now().After(deadline) {
+			return environment, fmt.Errorf("Python environment '%v' is still pending after %v.", environment.Name, waitTimeout)
+		}
+
+		tflog.Info(ctx, fmt.Sprintf("Waiting for Python environment '%v' to be resolved", environment.Name))
+		select {
+		case <-ctx.Done():
+			return environment, ctx.Err()
+		case <-time.After(pythonEnvironmentPollInterval):
+		}
+
+		current, err := cli.GetPythonEnvironment(ctx, environment.ID)
+		if err != nil {
+			return environment, err
+		}
+		if current == nil {
+			return environment, fmt.Errorf("Python environment '%v' was deleted while waiting for it to be resolved.", environment.Name)
+		}
+		environment = *current
+	}
+	return environment, nil
+}
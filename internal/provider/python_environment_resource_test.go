@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+func TestWaitForPythonEnvironment(t *testing.T) {
+	pollInterval := pythonEnvironmentPollInterval
+	pythonEnvironmentPollInterval = time.Millisecond
+	t.Cleanup(func() { pythonEnvironmentPollInterval = pollInterval })
+
+	testCases := map[string]struct {
+		status         string
+		timeout        types.String
+		expectedStatus string
+		isError        bool
+	}{
+		"resolved": {
+			status:         "READY",
+			timeout:        types.StringNull(),
+			expectedStatus: "READY",
+		},
+		"failed to resolve": {
+			status:         "ERROR",
+			timeout:        types.StringNull(),
+			expectedStatus: "ERROR",
+		},
+		"timed out": {
+			status:         "PENDING",
+			timeout:        types.StringValue("1ms"),
+			expectedStatus: "PENDING",
+			isError:        true,
+		},
+		"disabled": {
+			status:         "READY",
+			timeout:        types.StringValue("0s"),
+			expectedStatus: "PENDING",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fakeTectonPythonCLI(t, `{"id": "abc", "name": "rift", "status": "`+testCase.status+`"}`, "")
+			created := tectonclient.PythonEnvironment{ID: "abc", Name: "rift", Status: "PENDING"}
+			environment, err := waitForPythonEnvironment(context.Background(), tectonclient.Client{}, created, testCase.timeout)
+			if (err != nil) != testCase.isError {
+				t.Fatalf("expected error: %v, got: %v", testCase.isError, err)
+			}
+			if environment.Status != testCase.expectedStatus {
+				t.Errorf("expected status %v, got %v", testCase.expectedStatus, environment.Status)
+			}
+		})
+	}
+}
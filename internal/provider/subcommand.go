@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// ProviderFlags are the command line flags of the `preflight` and `generate` subcommands of the
+// provider binary, which configure the provider the same way as its `provider` block, so that the
+// subcommands run in the same environment as Terraform would.
+type ProviderFlags struct {
+	flags      *flag.FlagSet
+	configPath *string
+}
+
+// Registers the provider's settings as flags of flags. Settings that aren't set fall back to the
+// same environment variables as the provider, e.g. TECTON_URL and TECTON_API_KEY.
+func NewProviderFlags(flags *flag.FlagSet) *ProviderFlags {
+	f := &ProviderFlags{flags: flags}
+	f.configPath = flags.String("config", "", "a JSON file with the attributes and blocks of the provider configuration, e.g. {\"url\": \"...\", \"oauth\": {...}}, for settings without a flag such as `credential_helper`, `oauth` and `okta`. Flags take precedence over it")
+	flags.String("url", "", "the URL for your Tecton cluster, e.g. https://yourcluster.tecton.ai. Defaults to $TECTON_URL")
+	flags.Bool("use-cli-login", false, "use the session of an earlier `tecton login` instead of an API key")
+	flags.String("api-key-command", "", "a command that prints the API key, e.g. \"vault kv get -field=api_key secret/tecton\". Split on whitespace")
+	flags.String("cli-config-dir", "", "the directory the tecton CLI keeps its configuration in")
+	flags.String("https-proxy", "", "the proxy to connect to Tecton through")
+	flags.String("ca-bundle-path", "", "a PEM file of the CAs to verify Tecton's TLS certificate with")
+	flags.Bool("allow-insecure", false, "allow an http URL")
+	flags.Bool("insecure-skip-tls-verify", false, "don't verify Tecton's TLS certificate")
+	// The API key has no flag so that it doesn't end up in shell history. It's read from
+	// TECTON_API_KEY, or from the config file.
+	return f
+}
+
+// Returns the provider configuration given by the parsed flags and the config file.
+func (f *ProviderFlags) Config(ctx context.Context) (TectonProviderModel, error) {
+	values := map[string]any{}
+	if *f.configPath != "" {
+		data, err := os.ReadFile(*f.configPath)
+		if err != nil {
+			return TectonProviderModel{}, err
+		}
+		err = json.Unmarshal(data, &values)
+		if err != nil {
+			return TectonProviderModel{}, fmt.Errorf("Failed to parse provider configuration file '%v': %w", *f.configPath, err)
+		}
+	}
+	f.flags.Visit(func(fl *flag.Flag) {
+		if fl.Name == "config" {
+			return
+		}
+		attribute := strings.ReplaceAll(fl.Name, "-", "_")
+		value := fl.Value.(flag.Getter).Get()
+		if attribute == "api_key_command" {
+			value = strings.Fields(fl.Value.String())
+		}
+		values[attribute] = value
+	})
+	return providerConfigFromJSON(ctx, values)
+}
+
+// Decodes values, the attributes and blocks of a provider configuration by name, with the provider's
+// schema. Attributes that aren't set are null.
+func providerConfigFromJSON(ctx context.Context, values map[string]any) (TectonProviderModel, error) {
+	var schemaResp provider.SchemaResponse
+	(&TectonProvider{}).Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	data, err := json.Marshal(values)
+	if err != nil {
+		return TectonProviderModel{}, err
+	}
+	raw, err := tftypes.ValueFromJSON(data, schemaResp.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		return TectonProviderModel{}, fmt.Errorf("Invalid provider configuration: %w", err)
+	}
+	var config TectonProviderModel
+	diags := tfsdk.Config{Schema: schemaResp.Schema, Raw: raw}.Get(ctx, &config)
+	if diags.HasError() {
+		return TectonProviderModel{}, diagnosticsError(diags)
+	}
+	return config, nil
+}
+
+// Returns an error if config has no credential source and the TECTON_API_KEY environment variable
+// that's used instead isn't set. The provider itself leaves that to the first command to report, but
+// the subcommands can't do anything without credentials.
+func requireCredentials(config *TectonProviderModel) error {
+	if config.ApiKey.IsNull() && config.ApiKeyCommand == nil && config.ApiKeySecret.IsNull() && config.CredentialHelper == nil &&
+		config.OAuth == nil && config.Okta == nil && !config.UseCliLogin.ValueBool() && os.Getenv(apiKeyEnvVar) == "" {
+		return fmt.Errorf("No API key provided. Set the %v environment variable, or configure another credential source, e.g. with -use-cli-login or -config.", apiKeyEnvVar)
+	}
+	return nil
+}
+
+// Returns the errors in diags as a single error.
+func diagnosticsError(diags diag.Diagnostics) error {
+	var messages []string
+	for _, d := range diags.Errors() {
+		messages = append(messages, fmt.Sprintf("%v: %v", d.Summary(), d.Detail()))
+	}
+	return errors.New(strings.Join(messages, "\n"))
+}
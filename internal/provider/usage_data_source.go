@@ -0,0 +1,234 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &usageDataSource{}
+	_ datasource.DataSourceWithConfigure = &usageDataSource{}
+)
+
+// The default for `window` of the usage data sources.
+const defaultUsageWindow = 30 * 24 * time.Hour
+
+// NewUsageDataSource is a helper function to simplify the provider implementation.
+func NewUsageDataSource() datasource.DataSource {
+	return &usageDataSource{}
+}
+
+// usageDataSource reports the materialization and serving usage of workspaces.
+type usageDataSource struct {
+	CLI           tectonclient.Client
+	WorkspaceData *WorkspaceCache
+}
+
+// usageDataSourceModel maps the data source schema data.
+type usageDataSourceModel struct {
+	Window                      types.String          `tfsdk:"window"`
+	Workspaces                  []types.String        `tfsdk:"workspaces"`
+	MaterializationJobCount     types.Int64           `tfsdk:"materialization_job_count"`
+	MaterializationComputeHours types.Float64         `tfsdk:"materialization_compute_hours"`
+	ServingRequestCount         types.Int64           `tfsdk:"serving_request_count"`
+	WorkspaceUsage              []workspaceUsageModel `tfsdk:"workspace_usage"`
+}
+
+// workspaceUsageModel maps an element of `workspace_usage`.
+type workspaceUsageModel struct {
+	Name                        types.String  `tfsdk:"name"`
+	Live                        types.Bool    `tfsdk:"live"`
+	MaterializationJobCount     types.Int64   `tfsdk:"materialization_job_count"`
+	MaterializationComputeHours types.Float64 `tfsdk:"materialization_compute_hours"`
+	ServingRequestCount         types.Int64   `tfsdk:"serving_request_count"`
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *usageDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.CLI = providerData.CLI
+	d.WorkspaceData = providerData.WorkspaceData
+}
+
+// Metadata returns the data source type name.
+func (d *usageDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_usage"
+}
+
+// Schema defines the schema for the data source.
+func (d *usageDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reports the materialization compute and online serving usage of workspaces over a recent time window, " +
+			"so that FinOps dashboards and budget checks can be driven from Terraform. Compute hours are the run times of materialization job attempts, " +
+			"not billed hours, so they're a relative measure of cost. " +
+			"Requires the provider to be configured with an API key rather than with `use_cli_login`, `oauth` or `okta`, since the feature views that feature services serve are read from the feature server's metadata endpoint, which only accepts API keys.",
+		Attributes: map[string]schema.Attribute{
+			"window": schema.StringAttribute{
+				Description: "How far back to report usage, as a Go duration string, e.g. \"168h\". Defaults to \"720h\", i.e. 30 days.",
+				Optional:    true,
+				Validators: []validator.String{
+					durationValidator(),
+				},
+			},
+			"workspaces": schema.ListAttribute{
+				Description: "The workspaces to report. Defaults to every workspace.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"materialization_job_count": schema.Int64Attribute{
+				Description: "The number of materialization jobs that ran within the window in all the reported workspaces.",
+				Computed:    true,
+			},
+			"materialization_compute_hours": schema.Float64Attribute{
+				Description: "The run time of materialization jobs within the window in all the reported workspaces, in hours.",
+				Computed:    true,
+			},
+			"serving_request_count": schema.Int64Attribute{
+				Description: "The number of online requests within the window to the feature services of all the reported workspaces. " +
+					"Null if the installed Tecton SDK doesn't report serving metrics.",
+				Computed: true,
+			},
+			"workspace_usage": schema.ListNestedAttribute{
+				Description: "The usage of each reported workspace, sorted by name.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "The name of the workspace.",
+							Computed:    true,
+						},
+						"live": schema.BoolAttribute{
+							Description: "True if the workspace is a live workspace.",
+							Computed:    true,
+						},
+						"materialization_job_count": schema.Int64Attribute{
+							Description: "The number of materialization jobs of the workspace that ran within the window.",
+							Computed:    true,
+						},
+						"materialization_compute_hours": schema.Float64Attribute{
+							Description: "The run time of the workspace's materialization jobs within the window, in hours.",
+							Computed:    true,
+						},
+						"serving_request_count": schema.Int64Attribute{
+							Description: "The number of online requests to the workspace's feature services within the window. " +
+								"Null if the installed Tecton SDK doesn't report serving metrics.",
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read reports the usage of the workspaces.
+func (d *usageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.CLI.WithCorrelationID(ctx)
+	defer d.CLI.LogOperationSummary(ctx, "data.tecton_usage", "Read")
+	var config usageDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !requireAPIKey(d.CLI, "Usage is read together with the feature views each feature service serves, from the feature server's metadata endpoint, which only accepts an API key", &resp.Diagnostics) {
+		return
+	}
+	since, ok := usageWindowStart(config.Window, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+	workspaceData, err := d.WorkspaceData.Get(ctx)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to list Tecton workspaces", err)
+		return
+	}
+	workspaces := usageWorkspaces(config.Workspaces, workspaceData)
+	usage, err := d.CLI.GetUsage(ctx, since, workspaces)
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read usage", err)
+		return
+	}
+
+	var jobCount int64
+	var computeHours float64
+	servingRequestCount := types.Int64Value(0)
+	config.WorkspaceUsage = []workspaceUsageModel{}
+	for _, workspace := range usage.Workspaces {
+		isLive, _ := workspaceData.Lookup(workspace.Name)
+		model := workspaceUsageModel{
+			Name:                        types.StringValue(workspace.Name),
+			Live:                        types.BoolValue(isLive),
+			MaterializationJobCount:     types.Int64Value(workspace.MaterializationJobCount()),
+			MaterializationComputeHours: types.Float64Value(workspace.MaterializationComputeHours()),
+			ServingRequestCount:         types.Int64PointerValue(workspace.ServingRequestCount()),
+		}
+		jobCount += workspace.MaterializationJobCount()
+		computeHours += workspace.MaterializationComputeHours()
+		if model.ServingRequestCount.IsNull() {
+			servingRequestCount = types.Int64Null()
+		} else if !servingRequestCount.IsNull() {
+			servingRequestCount = types.Int64Value(servingRequestCount.ValueInt64() + model.ServingRequestCount.ValueInt64())
+		}
+		config.WorkspaceUsage = append(config.WorkspaceUsage, model)
+	}
+	sort.Slice(config.WorkspaceUsage, func(i, j int) bool {
+		return config.WorkspaceUsage[i].Name.ValueString() < config.WorkspaceUsage[j].Name.ValueString()
+	})
+	config.MaterializationJobCount = types.Int64Value(jobCount)
+	config.MaterializationComputeHours = types.Float64Value(computeHours)
+	config.ServingRequestCount = servingRequestCount
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+// Returns the start of a usage data source's `window`, adding an error to diags if it's invalid.
+func usageWindowStart(window types.String, diags *diag.Diagnostics) (time.Time, bool) {
+	duration := defaultUsageWindow
+	if !window.IsNull() {
+		var err error
+		duration, err = time.ParseDuration(window.ValueString())
+		if err != nil {
+			AddAttributeError(diags, path.Root("window"), ErrorCodeInvalidConfig, "Invalid Window", err.Error())
+			return time.Time{}, false
+		}
+	}
+	return time.Now().Add(-duration), true
+}
+
+// Returns the workspaces a usage data source reports: the configured ones, or every workspace.
+func usageWorkspaces(configured []types.String, all tectonclient.Workspaces) []string {
+	if configured != nil {
+		return StringValues(configured)
+	}
+	workspaces := append(append([]string{}, all.Lives...), all.Devs...)
+	sort.Strings(workspaces)
+	return workspaces
+}
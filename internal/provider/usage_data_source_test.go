@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Reads a usage data source with the given workspaces configured, which may be nil.
+func readUsageDataSource(t *testing.T, d datasource.DataSource, workspaces []string) tfsdk.State {
+	t.Helper()
+	values := map[string]tftypes.Value{}
+	if workspaces != nil {
+		var elements []tftypes.Value
+		for _, workspace := range workspaces {
+			elements = append(elements, tftypes.NewValue(tftypes.String, workspace))
+		}
+		values["workspaces"] = tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, elements)
+	}
+
+	resp := readDataSource(t, d, values)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+	return resp.State
+}
+
+func TestUsageDataSourceRead(t *testing.T) {
+	testCases := map[string]struct {
+		output               string
+		servingRequestCount  int64
+		servingRequestsKnown bool
+	}{
+		"serving metrics": {
+			output: `{"workspaces": [
+				{"name": "staging", "feature_views": [{"name": "user_clicks", "materialization_job_count": 1, "materialization_compute_hours": 0.5}], "feature_services": []},
+				{"name": "prod", "feature_views": [
+					{"name": "user_clicks", "materialization_job_count": 4, "materialization_compute_hours": 2.5},
+					{"name": "user_transactions", "materialization_job_count": 2, "materialization_compute_hours": 1}
+				], "feature_services": [{"name": "fraud", "serving_request_count": 1000}]}
+			]}`,
+			servingRequestCount:  1000,
+			servingRequestsKnown: true,
+		},
+		"no serving metrics": {
+			output: `{"workspaces": [
+				{"name": "staging", "feature_views": [{"name": "user_clicks", "materialization_job_count": 1, "materialization_compute_hours": 0.5}], "feature_services": []},
+				{"name": "prod", "feature_views": [
+					{"name": "user_clicks", "materialization_job_count": 4, "materialization_compute_hours": 2.5},
+					{"name": "user_transactions", "materialization_job_count": 2, "materialization_compute_hours": 1}
+				], "feature_services": [{"name": "fraud", "serving_request_count": null}]}
+			]}`,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fakeTectonPythonCLI(t, testCase.output, "")
+			t.Setenv(apiKeyEnvVar, "abc")
+			d := &usageDataSource{WorkspaceData: NewWorkspaceCache(tectonclient.NewWorkspaces([]string{"prod"}, []string{"staging"}))}
+			state := readUsageDataSource(t, d, nil)
+
+			var model usageDataSourceModel
+			diags := state.Get(context.Background(), &model)
+			if diags.HasError() {
+				t.Fatalf("failed to read state: %v", diags)
+			}
+			if model.MaterializationJobCount.ValueInt64() != 7 || model.MaterializationComputeHours.ValueFloat64() != 4 {
+				t.Errorf("unexpected totals: %v jobs, %v hours", model.MaterializationJobCount, model.MaterializationComputeHours)
+			}
+			if model.ServingRequestCount.IsNull() == testCase.servingRequestsKnown || model.ServingRequestCount.ValueInt64() != testCase.servingRequestCount {
+				t.Errorf("unexpected serving request count: %v", model.ServingRequestCount)
+			}
+			if len(model.WorkspaceUsage) != 2 || model.WorkspaceUsage[0].Name.ValueString() != "prod" || !model.WorkspaceUsage[0].Live.ValueBool() {
+				t.Errorf("expected the workspaces sorted by name, got %+v", model.WorkspaceUsage)
+			}
+			if !model.WorkspaceUsage[1].ServingRequestCount.IsNull() && model.WorkspaceUsage[1].ServingRequestCount.ValueInt64() != 0 {
+				t.Errorf("expected no requests to a workspace without feature services, got %v", model.WorkspaceUsage[1].ServingRequestCount)
+			}
+		})
+	}
+}
+
+func TestUsageDataSourceRead_noAPIKey(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"workspaces": []}`, "")
+
+	// e.g. with `use_cli_login` and no TECTON_API_KEY in the environment
+	d := &usageDataSource{
+		CLI:           tectonclient.Client{Env: []string{}},
+		WorkspaceData: NewWorkspaceCache(tectonclient.NewWorkspaces([]string{"prod"}, nil)),
+	}
+	resp := readDataSource(t, d, nil)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "[TECTON_CREDENTIALS_UNAVAILABLE]") {
+		t.Fatalf("expected a missing API key error, got: %v", resp.Diagnostics)
+	}
+}
+
+func TestUsageWorkspaces(t *testing.T) {
+	all := tectonclient.NewWorkspaces([]string{"prod"}, []string{"dev", "staging"})
+	if workspaces := usageWorkspaces(nil, all); len(workspaces) != 3 || workspaces[0] != "dev" {
+		t.Errorf("expected every workspace sorted, got %v", workspaces)
+	}
+	if workspaces := usageWorkspaces([]types.String{types.StringValue("prod")}, all); len(workspaces) != 1 || workspaces[0] != "prod" {
+		t.Errorf("expected the configured workspaces, got %v", workspaces)
+	}
+}
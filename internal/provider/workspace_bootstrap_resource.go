@@ -0,0 +1,458 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &workspaceBootstrapResource{}
+	_ resource.ResourceWithConfigure  = &workspaceBootstrapResource{}
+	_ resource.ResourceWithModifyPlan = &workspaceBootstrapResource{}
+)
+
+// NewWorkspaceBootstrapResource is a helper function to simplify the provider implementation.
+func NewWorkspaceBootstrapResource() resource.Resource {
+	return &workspaceBootstrapResource{}
+}
+
+// workspaceBootstrapResource creates a workspace together with its role grants.
+type workspaceBootstrapResource struct {
+	CLI            tectonclient.Client
+	WorkspaceData  *WorkspaceCache
+	DefaultRoles   []roleChange
+	Notifier       *Notifier
+	DisableDestroy bool
+	NameRules      tectonclient.NameRules
+}
+
+// workspaceBootstrapResourceModel maps the resource schema data.
+type workspaceBootstrapResourceModel struct {
+	ID              types.String                   `tfsdk:"id"`
+	LastUpdated     types.String                   `tfsdk:"last_updated"`
+	Name            types.String                   `tfsdk:"name"`
+	Live            types.Bool                     `tfsdk:"live"`
+	SkipSafetyCheck types.Bool                     `tfsdk:"skip_safety_check"`
+	DeleteTimeout   types.String                   `tfsdk:"delete_timeout"`
+	Grants          []workspaceBootstrapGrantModel `tfsdk:"grant"`
+}
+
+// workspaceBootstrapGrantModel maps a `grant` block.
+type workspaceBootstrapGrantModel struct {
+	Roles             []types.String `tfsdk:"roles"`
+	UserIDs           []types.String `tfsdk:"user_ids"`
+	ServiceAccountIDs []types.String `tfsdk:"service_account_ids"`
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *workspaceBootstrapResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.CLI = providerData.CLI
+	r.WorkspaceData = providerData.WorkspaceData
+	r.DefaultRoles = providerData.DefaultRoles
+	r.Notifier = providerData.Notifier
+	r.DisableDestroy = providerData.DisableDestroy
+	r.NameRules = providerData.NameRules
+}
+
+// Metadata returns the resource type name.
+func (r *workspaceBootstrapResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_bootstrap"
+}
+
+// Schema defines the schema for the resource.
+func (r *workspaceBootstrapResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Creates a workspace and grants a standard set of roles on it in a single step, e.g. to onboard a new team. " +
+			"If any of the grants fails, the new workspace is deleted again and the apply fails, so that a workspace never exists without the access it needs. " +
+			"The provider's `default_role` blocks are granted as well. Later changes to the `grant` blocks are applied like a `tecton_bulk_role_assignment`'s, " +
+			"and principals that lost any of their roles are granted them again on the next apply. Roles can't be granted to principal groups, " +
+			"so grant roles to a group's members instead, e.g. with the `users` of a `tecton_group_members` data source.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier for this workspace. Equal to the workspace name.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Description: "Timestamp of the last Terraform update of the workspace or its grants.",
+				Computed:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the workspace. Also checked at plan time against the naming rules the Tecton cluster reports, if any. Changing it replaces the workspace.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^[a-zA-Z0-9-_]+$`),
+						"must contain only alphanumeric characters, hyphens, or dashes",
+					),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"live": schema.BoolAttribute{
+				Description: "True if this workspace is a live workspace. False otherwise (i.e. it is a development workspace). Changing it replaces the workspace.",
+				Required:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"delete_timeout": schema.StringAttribute{
+				Description: "How long to wait for the workspace to disappear from the workspace list after it's deleted, like `tecton_workspace`'s `delete_timeout`. " +
+					"Must be applied before a destroy to take effect. Defaults to \"10m\".",
+				Optional: true,
+				Validators: []validator.String{
+					durationValidator(),
+				},
+			},
+			"skip_safety_check": schema.BoolAttribute{
+				Description: "Before a live workspace is deleted, the provider checks that it has no feature services and no pending or running materialization jobs, " +
+					"and refuses to delete it otherwise. Set to true to skip this check. Must be applied before the destroy to take effect. Defaults to false.",
+				Optional: true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"grant": schema.ListNestedBlock{
+				Description: "Roles granted on the workspace to users and service accounts, e.g. owner for the team's leads and editor for its members. May be repeated.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"roles": schema.SetAttribute{
+							Description: "The roles granted to every principal of the block. Set values must be one of (\"viewer\", \"operator\", \"editor\", \"owner\").",
+							Required:    true,
+							ElementType: types.StringType,
+							Validators: []validator.Set{
+								setvalidator.SizeAtLeast(1),
+								setvalidator.ValueStringsAre(
+									stringvalidator.OneOf(validRoles...),
+								),
+							},
+						},
+						"user_ids": schema.SetAttribute{
+							Description: "The user IDs (e.g. emails) to which the roles are granted. At least one of `user_ids` and `service_account_ids` must be provided.",
+							Optional:    true,
+							ElementType: types.StringType,
+							Validators: []validator.Set{
+								setvalidator.AtLeastOneOf(path.MatchRelative().AtParent().AtName("service_account_ids")),
+								setvalidator.ValueStringsAre(
+									stringvalidator.RegexMatches(
+										regexp.MustCompile(`^[a-zA-Z0-9_.@-]+$`),
+										"must contain only alphanumeric characters, or characters in the set _.@-",
+									),
+								),
+							},
+						},
+						"service_account_ids": schema.SetAttribute{
+							Description: "The service account IDs to which the roles are granted. At least one of `user_ids` and `service_account_ids` must be provided.",
+							Optional:    true,
+							ElementType: types.StringType,
+							Validators: []validator.Set{
+								setvalidator.ValueStringsAre(
+									stringvalidator.RegexMatches(
+										regexp.MustCompile(`^[a-zA-Z0-9]+$`),
+										"must contain only alphanumeric characters",
+									),
+								),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Create creates the workspace and grants the roles on it, and deletes the workspace again if any
+// grant fails.
+func (r *workspaceBootstrapResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_workspace_bootstrap", "Create")
+	// Retrieve values from plan
+	var plan workspaceBootstrapResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	name := plan.Name.ValueString()
+
+	// Report the changes made in Tecton to the notification webhook, even if creation fails
+	cli, changes := r.CLI.RecordingChanges()
+	defer r.Notifier.Notify(ctx, fmt.Sprintf("tecton_workspace_bootstrap '%v'", name), changes, &resp.Diagnostics)
+
+	err := cli.CreateWorkspace(ctx, name, plan.Live.ValueBool())
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to create Tecton workspace", err)
+		return
+	}
+	err = waitForWorkspace(ctx, cli, name, types.StringNull())
+	if err != nil {
+		AddWarning(
+			&resp.Diagnostics,
+			ClassifyError(err),
+			"Tecton workspace not visible yet",
+			fmt.Sprintf("Created workspace '%v', but it isn't visible yet, so granting roles on it may fail.\nError: %v", name, err.Error()),
+		)
+	}
+
+	// The grants are part of the workspace, so a failed grant rolls back the workspace instead of leaving
+	// it without the access it needs
+	grants := append(plan.roleChanges(), r.DefaultRoles...)
+	tflog.Info(ctx, fmt.Sprintf("Granting %v roles on workspace '%v'", len(grants), name))
+	err = ApplyRoleChanges(ctx, cli, name, grants)
+	if err != nil {
+		detail := fmt.Sprintf("Created workspace '%v', but failed to grant roles on it, so the workspace was deleted again.\nError: %v", name, err.Error())
+		tflog.Warn(ctx, fmt.Sprintf("Deleting workspace '%v' since granting roles on it failed", name))
+		deleteErr := cli.DeleteWorkspace(ctx, name, plan.Live.ValueBool())
+		if deleteErr != nil {
+			detail = fmt.Sprintf(
+				"Created workspace '%v', but failed to grant roles on it, and then failed to delete the workspace again. "+
+					"The workspace must be deleted by hand before applying again.\nError: %v\nDeletion error: %v",
+				name,
+				err.Error(),
+				deleteErr.Error(),
+			)
+		}
+		AddError(&resp.Diagnostics, ClassifyError(err), "Failed to bootstrap Tecton workspace", detail)
+		return
+	}
+
+	if !cli.DryRun {
+		r.WorkspaceData.Add(name, plan.Live.ValueBool())
+	}
+
+	// Generated computed values
+	plan.ID = plan.Name
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *workspaceBootstrapResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_workspace_bootstrap", "Read")
+	// Get current state
+	var state workspaceBootstrapResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspace := &workspaceResource{CLI: r.CLI, WorkspaceData: r.WorkspaceData}
+	isLive, err := workspace.FindWorkspace(ctx, state.Name.ValueString())
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Error Reading Workspace", err)
+		return
+	}
+	state.Live = types.BoolValue(isLive)
+
+	// As with bulk role assignments, principals that are missing any of their roles are dropped from
+	// the state, so that the next plan grants them the roles again
+	assignment := &bulkRoleAssignmentResource{CLI: r.CLI}
+	for i, grant := range state.Grants {
+		model := grant.bulkRoleAssignment(state.Name)
+		complete, err := assignment.PrincipalsWithAllRoles(ctx, &model)
+		if err != nil {
+			AddCommandError(&resp.Diagnostics, "Failed to read Tecton roles", err)
+			return
+		}
+		state.Grants[i].UserIDs = filterPrincipals(grant.UserIDs, complete, func(id string) tectonclient.Principal { return tectonclient.Principal{UserID: id} })
+		state.Grants[i].ServiceAccountIDs = filterPrincipals(grant.ServiceAccountIDs, complete, func(id string) tectonclient.Principal { return tectonclient.Principal{ServiceAccountID: id} })
+	}
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update grants the roles that were added to the `grant` blocks and revokes the ones that were
+// removed. The name and live setting can't change, since changing them replaces the workspace.
+func (r *workspaceBootstrapResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_workspace_bootstrap", "Update")
+	// Retrieve values from plan
+	var plan workspaceBootstrapResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Also retrieve current state
+	var state workspaceBootstrapResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cli, changes := r.CLI.RecordingChanges()
+	defer r.Notifier.Notify(ctx, fmt.Sprintf("tecton_workspace_bootstrap '%v'", plan.Name.ValueString()), changes, &resp.Diagnostics)
+
+	err := UpdateWorkspaceGrants(ctx, cli, plan.Name.ValueString(), plan.roleChanges(), state.roleChanges())
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Role Assignment Failure", err)
+		return
+	}
+
+	plan.ID = state.ID
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// ModifyPlan checks the name against the cluster's rules, and fails plans that destroy the workspace if
+// the provider's `disable_destroy` is set.
+func (r *workspaceBootstrapResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_workspace_bootstrap", "ModifyPlan")
+	if !req.Plan.Raw.IsNull() {
+		var name types.String
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+		CheckNameRule(r.NameRules.WorkspaceName, name, path.Root("name"), &resp.Diagnostics)
+		return
+	}
+	if req.State.Raw.IsNull() {
+		return
+	}
+	var name types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name"), &name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	CheckDestroyAllowed(r.DisableDestroy, fmt.Sprintf("tecton_workspace_bootstrap '%v'", name.ValueString()), &resp.Diagnostics)
+}
+
+// Delete deletes the workspace, which also revokes every role granted on it.
+func (r *workspaceBootstrapResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_workspace_bootstrap", "Delete")
+	// Get current state
+	var state workspaceBootstrapResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !CheckDestroyAllowed(r.DisableDestroy, fmt.Sprintf("tecton_workspace_bootstrap '%v'", state.Name.ValueString()), &resp.Diagnostics) {
+		return
+	}
+
+	cli, changes := r.CLI.RecordingChanges()
+	defer r.Notifier.Notify(ctx, fmt.Sprintf("tecton_workspace_bootstrap '%v'", state.Name.ValueString()), changes, &resp.Diagnostics)
+	DeleteWorkspace(ctx, cli, state.Name.ValueString(), state.Live.ValueBool(), state.SkipSafetyCheck.ValueBool(), state.DeleteTimeout, &resp.Diagnostics)
+	if !resp.Diagnostics.HasError() && !cli.DryRun {
+		r.WorkspaceData.Remove(state.Name.ValueString())
+	}
+}
+
+// Returns the grants of the `grant` blocks, one for every principal and role. A role that's granted to
+// a principal by several blocks is only included once.
+func (m *workspaceBootstrapResourceModel) roleChanges() []roleChange {
+	var changes []roleChange
+	seen := make(map[roleChange]bool)
+	for _, grant := range m.Grants {
+		model := grant.bulkRoleAssignment(m.Name)
+		for _, p := range model.Principals() {
+			for _, role := range grant.Roles {
+				change := roleChange{p, role.ValueString(), true}
+				if !seen[change] {
+					seen[change] = true
+					changes = append(changes, change)
+				}
+			}
+		}
+	}
+	return changes
+}
+
+// Returns the `grant` block as a bulk role assignment on the workspace, to share its logic.
+func (g workspaceBootstrapGrantModel) bulkRoleAssignment(workspace types.String) bulkRoleAssignmentResourceModel {
+	return bulkRoleAssignmentResourceModel{
+		Workspace:         workspace,
+		Roles:             g.Roles,
+		UserIDs:           g.UserIDs,
+		ServiceAccountIDs: g.ServiceAccountIDs,
+	}
+}
+
+// Grants the roles that are in plan but not in state, and revokes the ones that are in state but not in
+// plan. Both are lists of grants. Changes that Tecton already matches are skipped, and grants are all
+// applied before any revocations, like in bulk role assignments.
+func UpdateWorkspaceGrants(ctx context.Context, cli tectonclient.Client, workspace string, plan []roleChange, state []roleChange) error {
+	var grants, revocations []roleChange
+	for _, change := range plan {
+		if !slices.Contains(state, change) {
+			grants = append(grants, change)
+		}
+	}
+	for _, change := range state {
+		if !slices.Contains(plan, change) {
+			revocations = append(revocations, roleChange{change.Principal, change.Role, false})
+		}
+	}
+	if len(grants) == 0 && len(revocations) == 0 {
+		return nil
+	}
+
+	var principals []tectonclient.Principal
+	for _, change := range append(append([]roleChange{}, grants...), revocations...) {
+		if !containsPrincipal(principals, change.Principal) {
+			principals = append(principals, change.Principal)
+		}
+	}
+	granted, err := (&bulkRoleAssignmentResource{CLI: cli}).GetWorkspaceRoles(ctx, principals, workspace)
+	if err != nil {
+		return err
+	}
+	grants, revocations = pendingRoleChanges(ctx, grants, granted), pendingRoleChanges(ctx, revocations, granted)
+	for _, changes := range [][]roleChange{grants, revocations} {
+		err := ApplyRoleChanges(ctx, cli, workspace, changes)
+		if err != nil {
+			return err
+		}
+	}
+	tflog.Info(ctx, fmt.Sprintf("Applied %v role grants and %v role revocations on workspace '%v'", len(grants), len(revocations), workspace))
+	return nil
+}
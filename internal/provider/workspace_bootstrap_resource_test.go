@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Creates a bootstrap resource for team-a with the given grant.
+func createWorkspaceBootstrap(t *testing.T, grant workspaceBootstrapGrantModel) *fwresource.CreateResponse {
+	t.Helper()
+	ctx := context.Background()
+	r := &workspaceBootstrapResource{
+		CLI:          tectonclient.Client{},
+		DefaultRoles: []roleChange{{tectonclient.Principal{ServiceAccountID: "platform"}, "owner", true}},
+	}
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	model := workspaceBootstrapResourceModel{
+		ID:          types.StringUnknown(),
+		LastUpdated: types.StringUnknown(),
+		Name:        types.StringValue("team-a"),
+		Live:        types.BoolValue(false),
+		Grants:      []workspaceBootstrapGrantModel{grant},
+	}
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	if diags := state.Set(ctx, &model); diags.HasError() {
+		t.Fatalf("failed to build plan: %v", diags)
+	}
+	resp := &fwresource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan{Schema: schemaResp.Schema, Raw: state.Raw}}, resp)
+	return resp
+}
+
+func TestWorkspaceBootstrapCreate(t *testing.T) {
+	fakeTectonCluster(t, `{}`)
+	resp := createWorkspaceBootstrap(t, workspaceBootstrapGrantModel{
+		Roles:   []types.String{types.StringValue("editor")},
+		UserIDs: []types.String{types.StringValue("alice"), types.StringValue("bob")},
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	ctx := context.Background()
+	workspaces, err := tectonclient.Client{}.ListWorkspaces(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Contains(workspaces.Devs, "team-a") {
+		t.Errorf("expected workspace team-a to be created, got %v", workspaces)
+	}
+	principals := []tectonclient.Principal{{UserID: "alice"}, {UserID: "bob"}, {ServiceAccountID: "platform"}}
+	granted, err := (&bulkRoleAssignmentResource{}).GetWorkspaceRoles(ctx, principals, "team-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !granted[principals[0]]["editor"] || !granted[principals[1]]["editor"] || !granted[principals[2]]["owner"] {
+		t.Errorf("expected the grants and the default role to be applied, got %v", granted)
+	}
+}
+
+func TestWorkspaceBootstrapCreate_rollback(t *testing.T) {
+	fakeTectonCluster(t, `{"failures": {"access-control assign-role --role editor --workspace team-a --user bob": "User bob not found"}}`)
+	resp := createWorkspaceBootstrap(t, workspaceBootstrapGrantModel{
+		Roles:   []types.String{types.StringValue("editor")},
+		UserIDs: []types.String{types.StringValue("alice"), types.StringValue("bob")},
+	})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "the workspace was deleted again") {
+		t.Fatalf("expected the workspace to be rolled back, got: %v", resp.Diagnostics)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Errorf("expected no state, got %v", resp.State.Raw)
+	}
+
+	workspaces, err := tectonclient.Client{}.ListWorkspaces(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if slices.Contains(workspaces.Devs, "team-a") {
+		t.Errorf("expected workspace team-a to be deleted, got %v", workspaces)
+	}
+}
+
+func TestWorkspaceBootstrapRoleChanges(t *testing.T) {
+	model := workspaceBootstrapResourceModel{
+		Name: types.StringValue("team-a"),
+		Grants: []workspaceBootstrapGrantModel{
+			{Roles: []types.String{types.StringValue("viewer")}, UserIDs: []types.String{types.StringValue("alice")}, ServiceAccountIDs: []types.String{types.StringValue("ci")}},
+			{Roles: []types.String{types.StringValue("viewer"), types.StringValue("owner")}, UserIDs: []types.String{types.StringValue("alice")}},
+		},
+	}
+	expected := []roleChange{
+		{tectonclient.Principal{UserID: "alice"}, "viewer", true},
+		{tectonclient.Principal{ServiceAccountID: "ci"}, "viewer", true},
+		{tectonclient.Principal{UserID: "alice"}, "owner", true},
+	}
+	if actual := model.roleChanges(); !slices.Equal(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &workspaceComparisonDataSource{}
+	_ datasource.DataSourceWithConfigure = &workspaceComparisonDataSource{}
+)
+
+// NewWorkspaceComparisonDataSource is a helper function to simplify the provider implementation.
+func NewWorkspaceComparisonDataSource() datasource.DataSource {
+	return &workspaceComparisonDataSource{}
+}
+
+// workspaceComparisonDataSource compares the objects of two workspaces.
+type workspaceComparisonDataSource struct {
+	CLI tectonclient.Client
+}
+
+// workspaceComparisonDataSourceModel maps the data source schema data.
+type workspaceComparisonDataSourceModel struct {
+	ID              types.String           `tfsdk:"id"`
+	SourceWorkspace types.String           `tfsdk:"source_workspace"`
+	TargetWorkspace types.String           `tfsdk:"target_workspace"`
+	InSync          types.Bool             `tfsdk:"in_sync"`
+	OnlyInSource    *workspaceObjectsModel `tfsdk:"only_in_source"`
+	OnlyInTarget    *workspaceObjectsModel `tfsdk:"only_in_target"`
+}
+
+// workspaceObjectsModel maps `only_in_source` and `only_in_target`.
+type workspaceObjectsModel struct {
+	FeatureViews    []types.String `tfsdk:"feature_views"`
+	FeatureServices []types.String `tfsdk:"feature_services"`
+	Entities        []types.String `tfsdk:"entities"`
+	DataSources     []types.String `tfsdk:"data_sources"`
+	Transformations []types.String `tfsdk:"transformations"`
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *workspaceComparisonDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.CLI = providerData.CLI
+}
+
+// Metadata returns the data source type name.
+func (d *workspaceComparisonDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_comparison"
+}
+
+// Schema defines the schema for the data source.
+func (d *workspaceComparisonDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	objectsAttributes := func(workspace string) map[string]schema.Attribute {
+		listAttribute := func(kind string) schema.ListAttribute {
+			return schema.ListAttribute{
+				Description: fmt.Sprintf("The names of the %v that are only in the %v workspace, sorted.", kind, workspace),
+				ElementType: types.StringType,
+				Computed:    true,
+			}
+		}
+		return map[string]schema.Attribute{
+			"feature_views":    listAttribute("feature views"),
+			"feature_services": listAttribute("feature services"),
+			"entities":         listAttribute("entities"),
+			"data_sources":     listAttribute("data sources"),
+			"transformations":  listAttribute("transformations"),
+		}
+	}
+	resp.Schema = schema.Schema{
+		Description: "Compares the objects of two workspaces, e.g. staging and prod, and reports the ones that are only in one of them, " +
+			"so that promotion pipelines can assert that environments are in sync. Objects are compared by name, so an object whose definition " +
+			"differs between the workspaces isn't reported.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The source and target workspace names, separated by a colon.",
+				Computed:    true,
+			},
+			"source_workspace": schema.StringAttribute{
+				Description: "The name of the workspace that objects are promoted from, e.g. staging.",
+				Required:    true,
+			},
+			"target_workspace": schema.StringAttribute{
+				Description: "The name of the workspace that objects are promoted to, e.g. prod.",
+				Required:    true,
+			},
+			"in_sync": schema.BoolAttribute{
+				Description: "True if both workspaces have objects with the same names.",
+				Computed:    true,
+			},
+			"only_in_source": schema.SingleNestedAttribute{
+				Description: "The objects that are in the source workspace but not in the target workspace, e.g. ones that haven't been promoted yet.",
+				Computed:    true,
+				Attributes:  objectsAttributes("source"),
+			},
+			"only_in_target": schema.SingleNestedAttribute{
+				Description: "The objects that are in the target workspace but not in the source workspace, e.g. ones that were removed from the source.",
+				Computed:    true,
+				Attributes:  objectsAttributes("target"),
+			},
+		},
+	}
+}
+
+// Read reads the objects of both workspaces and compares them.
+func (d *workspaceComparisonDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.CLI.WithCorrelationID(ctx)
+	defer d.CLI.LogOperationSummary(ctx, "data.tecton_workspace_comparison", "Read")
+	var config workspaceComparisonDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspaces := []string{config.SourceWorkspace.ValueString(), config.TargetWorkspace.ValueString()}
+	summaries := make([]workspaceSummary, len(workspaces))
+	err := runParallel(len(workspaces), func(i int) error {
+		var err error
+		summaries[i], err = GetWorkspaceSummary(ctx, d.CLI, workspaces[i])
+		return err
+	})
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read workspace contents", err)
+		return
+	}
+
+	config.ID = types.StringValue(workspaces[0] + ":" + workspaces[1])
+	config.OnlyInSource = workspaceObjectsDifference(summaries[0], summaries[1])
+	config.OnlyInTarget = workspaceObjectsDifference(summaries[1], summaries[0])
+	config.InSync = types.BoolValue(config.OnlyInSource.isEmpty() && config.OnlyInTarget.isEmpty())
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+// Returns the objects of workspace a that aren't in workspace b.
+func workspaceObjectsDifference(a, b workspaceSummary) *workspaceObjectsModel {
+	difference := func(a, b []string) []types.String {
+		names := []types.String{}
+		for _, name := range a {
+			if !slices.Contains(b, name) {
+				names = append(names, types.StringValue(name))
+			}
+		}
+		return names
+	}
+	return &workspaceObjectsModel{
+		FeatureViews:    difference(a.FeatureViews, b.FeatureViews),
+		FeatureServices: difference(a.FeatureServices, b.FeatureServices),
+		Entities:        difference(a.Entities, b.Entities),
+		DataSources:     difference(a.DataSources, b.DataSources),
+		Transformations: difference(a.Transformations, b.Transformations),
+	}
+}
+
+// Returns true if there are no objects.
+func (m *workspaceObjectsModel) isEmpty() bool {
+	return len(m.FeatureViews) == 0 && len(m.FeatureServices) == 0 && len(m.Entities) == 0 && len(m.DataSources) == 0 && len(m.Transformations) == 0
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestWorkspaceComparisonDataSourceRead(t *testing.T) {
+	fakeTectonCluster(t, `{"workspaces": {
+		"staging": {"live": true, "feature_views": ["user_stats", "user_clicks"], "entities": ["user"], "transformations": ["clicks"]},
+		"prod": {"live": true, "feature_views": ["user_stats", "legacy"], "entities": ["user"]},
+		"prod-copy": {"live": true, "feature_views": ["legacy", "user_stats"], "entities": ["user"]}
+	}}`)
+	testCases := map[string]struct {
+		source       string
+		target       string
+		inSync       bool
+		onlyInSource []string
+		onlyInTarget []string
+	}{
+		"promotion pending": {source: "staging", target: "prod", onlyInSource: []string{"user_clicks", "clicks"}, onlyInTarget: []string{"legacy"}},
+		"in sync":           {source: "prod", target: "prod-copy", inSync: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			resp := readDataSource(t, NewWorkspaceComparisonDataSource(), map[string]tftypes.Value{
+				"source_workspace": tftypes.NewValue(tftypes.String, testCase.source),
+				"target_workspace": tftypes.NewValue(tftypes.String, testCase.target),
+			})
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+
+			var state workspaceComparisonDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("failed to read state: %v", resp.Diagnostics)
+			}
+			if state.InSync.ValueBool() != testCase.inSync {
+				t.Errorf("expected in_sync to be %v, got %v", testCase.inSync, state.InSync)
+			}
+			onlyInSource := append(StringValues(state.OnlyInSource.FeatureViews), StringValues(state.OnlyInSource.Transformations)...)
+			onlyInTarget := append(StringValues(state.OnlyInTarget.FeatureViews), StringValues(state.OnlyInTarget.Transformations)...)
+			if fmt.Sprint(onlyInSource) != fmt.Sprint(testCase.onlyInSource) || fmt.Sprint(onlyInTarget) != fmt.Sprint(testCase.onlyInTarget) {
+				t.Errorf("expected %v only in the source and %v only in the target, got %v and %v", testCase.onlyInSource, testCase.onlyInTarget, onlyInSource, onlyInTarget)
+			}
+		})
+	}
+}
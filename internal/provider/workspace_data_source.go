@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &workspaceDataSource{}
+	_ datasource.DataSourceWithConfigure = &workspaceDataSource{}
+)
+
+// NewWorkspaceDataSource is a helper function to simplify the provider implementation.
+func NewWorkspaceDataSource() datasource.DataSource {
+	return &workspaceDataSource{}
+}
+
+// workspaceDataSource looks up a workspace and summarizes its contents.
+type workspaceDataSource struct {
+	CLI           tectonclient.Client
+	WorkspaceData *WorkspaceCache
+}
+
+// workspaceDataSourceModel maps the data source schema data.
+type workspaceDataSourceModel struct {
+	Name            types.String   `tfsdk:"name"`
+	Live            types.Bool     `tfsdk:"live"`
+	IsEmpty         types.Bool     `tfsdk:"is_empty"`
+	HasLiveServing  types.Bool     `tfsdk:"has_live_serving"`
+	FeatureViews    []types.String `tfsdk:"feature_views"`
+	FeatureServices []types.String `tfsdk:"feature_services"`
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *workspaceDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.CLI = providerData.CLI
+	d.WorkspaceData = providerData.WorkspaceData
+}
+
+// Metadata returns the data source type name.
+func (d *workspaceDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace"
+}
+
+// Schema defines the schema for the data source.
+func (d *workspaceDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up a workspace and summarizes its contents, e.g. to guard destructive changes with preconditions on whether it's empty or serving traffic.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: "The name of the workspace.",
+				Required:    true,
+			},
+			"live": schema.BoolAttribute{
+				Description: "True if the workspace is a live workspace, false if it's a development workspace.",
+				Computed:    true,
+			},
+			"is_empty": schema.BoolAttribute{
+				Description: "True if the workspace has no feature views and no feature services.",
+				Computed:    true,
+			},
+			"has_live_serving": schema.BoolAttribute{
+				Description: "True if the workspace is live and has feature services, i.e. it may be serving online traffic.",
+				Computed:    true,
+			},
+			"feature_views": schema.ListAttribute{
+				Description: "The names of the feature views in the workspace, sorted.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"feature_services": schema.ListAttribute{
+				Description: "The names of the feature services in the workspace, sorted.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Read looks up the workspace and reads its contents.
+func (d *workspaceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.CLI.WithCorrelationID(ctx)
+	defer d.CLI.LogOperationSummary(ctx, "data.tecton_workspace", "Read")
+	var config workspaceDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspaces := &workspaceResource{CLI: d.CLI, WorkspaceData: d.WorkspaceData}
+	isLive, err := workspaces.FindWorkspace(ctx, config.Name.ValueString())
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read workspace", err)
+		return
+	}
+	summary, err := GetWorkspaceSummary(ctx, d.CLI, config.Name.ValueString())
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to read workspace contents", err)
+		return
+	}
+
+	config.Live = types.BoolValue(isLive)
+	config.IsEmpty = types.BoolValue(summary.IsEmpty())
+	config.HasLiveServing = types.BoolValue(summary.HasLiveServing(isLive))
+	config.FeatureViews = []types.String{}
+	for _, name := range summary.FeatureViews {
+		config.FeatureViews = append(config.FeatureViews, types.StringValue(name))
+	}
+	config.FeatureServices = []types.String{}
+	for _, name := range summary.FeatureServices {
+		config.FeatureServices = append(config.FeatureServices, types.StringValue(name))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestWorkspaceDataSourceRead(t *testing.T) {
+	fakeTectonCluster(t, `{"workspaces": {
+		"prod": {"live": true, "feature_views": ["user_stats"], "feature_services": ["fraud"]},
+		"staging": {"live": true, "feature_views": ["user_stats"]},
+		"dev": {"live": false}
+	}}`)
+	testCases := map[string]struct {
+		isLive         bool
+		isEmpty        bool
+		hasLiveServing bool
+		isError        bool
+	}{
+		"prod":    {isLive: true, hasLiveServing: true},
+		"staging": {isLive: true},
+		"dev":     {isEmpty: true},
+		"missing": {isError: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			resp := readDataSource(t, NewWorkspaceDataSource(), map[string]tftypes.Value{
+				"name": tftypes.NewValue(tftypes.String, name),
+			})
+			if resp.Diagnostics.HasError() != testCase.isError {
+				t.Fatalf("expected error: %v, got: %v", testCase.isError, resp.Diagnostics)
+			}
+			if testCase.isError {
+				return
+			}
+
+			var state workspaceDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("failed to read state: %v", resp.Diagnostics)
+			}
+			if state.Live.ValueBool() != testCase.isLive || state.IsEmpty.ValueBool() != testCase.isEmpty || state.HasLiveServing.ValueBool() != testCase.hasLiveServing {
+				t.Errorf("unexpected state: %+v", state)
+			}
+		})
+	}
+}
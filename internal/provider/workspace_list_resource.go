@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ list.ListResource              = &workspaceListResource{}
+	_ list.ListResourceWithConfigure = &workspaceListResource{}
+)
+
+// NewWorkspaceListResource is a helper function to simplify the provider implementation.
+func NewWorkspaceListResource() list.ListResource {
+	return &workspaceListResource{}
+}
+
+// workspaceListResource lists existing workspaces so that they can be imported with `terraform query`.
+type workspaceListResource struct {
+	WorkspaceData *WorkspaceCache
+}
+
+// workspaceListResourceModel maps the list resource config schema data.
+type workspaceListResourceModel struct {
+	Live      types.Bool   `tfsdk:"live"`
+	NameRegex types.String `tfsdk:"name_regex"`
+}
+
+// Configure adds the provider configured client to the list resource.
+func (r *workspaceListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.WorkspaceData = providerData.WorkspaceData
+}
+
+// Metadata returns the resource type name.
+func (r *workspaceListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace"
+}
+
+// ListResourceConfigSchema defines the filters accepted by the list resource.
+func (r *workspaceListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"live": schema.BoolAttribute{
+				Description: "If set, only list live workspaces (true) or development workspaces (false).",
+				Optional:    true,
+			},
+			"name_regex": schema.StringAttribute{
+				Description: "If set, only list workspaces whose name matches this regular expression.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// List streams the workspaces matching the filters from the shared workspace data.
+func (r *workspaceListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	ctx = tectonclient.Client{}.WithCorrelationID(ctx)
+	var config workspaceListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	var nameRegex *regexp.Regexp
+	if !config.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(config.NameRegex.ValueString())
+		if err != nil {
+			AddAttributeError(
+				&diags,
+				path.Root("name_regex"),
+				ErrorCodeInvalidConfig,
+				"Invalid Workspace Name Regex",
+				fmt.Sprintf("Could not compile name_regex: %v", err.Error()),
+			)
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+	}
+
+	var workspaces []workspaceResourceModel
+	appendWorkspaces := func(names []string, isLive bool) {
+		if !config.Live.IsNull() && config.Live.ValueBool() != isLive {
+			return
+		}
+		for _, name := range names {
+			if nameRegex != nil && !nameRegex.MatchString(name) {
+				continue
+			}
+			workspaces = append(workspaces, workspaceResourceModel{
+				ID:       types.StringValue(name),
+				Name:     types.StringValue(name),
+				Live:     types.BoolValue(isLive),
+				Timeouts: nullTimeouts(),
+			})
+		}
+	}
+	data, err := r.WorkspaceData.Get(ctx)
+	if err != nil {
+		AddCommandError(&diags, "Failed to list Tecton workspaces", err)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	appendWorkspaces(data.Lives, true)
+	appendWorkspaces(data.Devs, false)
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		for i, workspace := range workspaces {
+			if req.Limit > 0 && int64(i) >= req.Limit {
+				return
+			}
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = workspace.Name.ValueString()
+			result.Diagnostics.Append(result.Identity.Set(ctx, workspaceResourceIdentityModel{Name: workspace.Name})...)
+			if req.IncludeResource {
+				result.Diagnostics.Append(result.Resource.Set(ctx, workspace)...)
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
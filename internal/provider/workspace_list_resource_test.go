@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
+)
+
+func TestWorkspaceListResourceList(t *testing.T) {
+	ctx := context.Background()
+	workspaces := tectonclient.Workspaces{
+		Lives: []string{"prod", "staging"},
+		Devs:  []string{"dev-alice", "dev-bob"},
+	}
+
+	var listSchemaResp list.ListResourceSchemaResponse
+	NewWorkspaceListResource().ListResourceConfigSchema(ctx, list.ListResourceSchemaRequest{}, &listSchemaResp)
+	var schemaResp fwresource.SchemaResponse
+	NewWorkspaceResource().Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	var identitySchemaResp fwresource.IdentitySchemaResponse
+	NewWorkspaceResource().(fwresource.ResourceWithIdentity).IdentitySchema(ctx, fwresource.IdentitySchemaRequest{}, &identitySchemaResp)
+
+	testCases := map[string]struct {
+		live      tftypes.Value
+		nameRegex tftypes.Value
+		limit     int64
+		expected  []string
+		isError   bool
+	}{
+		"all": {
+			live:      tftypes.NewValue(tftypes.Bool, nil),
+			nameRegex: tftypes.NewValue(tftypes.String, nil),
+			expected:  []string{"prod", "staging", "dev-alice", "dev-bob"},
+		},
+		"live only": {
+			live:      tftypes.NewValue(tftypes.Bool, true),
+			nameRegex: tftypes.NewValue(tftypes.String, nil),
+			expected:  []string{"prod", "staging"},
+		},
+		"dev only with regex": {
+			live:      tftypes.NewValue(tftypes.Bool, false),
+			nameRegex: tftypes.NewValue(tftypes.String, "bob$"),
+			expected:  []string{"dev-bob"},
+		},
+		"limit": {
+			live:      tftypes.NewValue(tftypes.Bool, nil),
+			nameRegex: tftypes.NewValue(tftypes.String, nil),
+			limit:     3,
+			expected:  []string{"prod", "staging", "dev-alice"},
+		},
+		"invalid regex": {
+			live:      tftypes.NewValue(tftypes.Bool, nil),
+			nameRegex: tftypes.NewValue(tftypes.String, "("),
+			isError:   true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config := tfsdk.Config{
+				Schema: listSchemaResp.Schema,
+				Raw: tftypes.NewValue(listSchemaResp.Schema.Type().TerraformType(ctx), map[string]tftypes.Value{
+					"live":       testCase.live,
+					"name_regex": testCase.nameRegex,
+				}),
+			}
+			req := list.ListRequest{
+				Config:                 config,
+				IncludeResource:        true,
+				Limit:                  testCase.limit,
+				ResourceSchema:         schemaResp.Schema,
+				ResourceIdentitySchema: identitySchemaResp.IdentitySchema,
+			}
+			r := &workspaceListResource{WorkspaceData: NewWorkspaceCache(workspaces)}
+			stream := list.ListResultsStream{}
+			r.List(ctx, req, &stream)
+
+			var names []string
+			for result := range stream.Results {
+				if result.Diagnostics.HasError() {
+					if !testCase.isError {
+						t.Fatalf("unexpected error: %v", result.Diagnostics)
+					}
+					return
+				}
+
+				var identity workspaceResourceIdentityModel
+				result.Diagnostics.Append(result.Identity.Get(ctx, &identity)...)
+				var model workspaceResourceModel
+				result.Diagnostics.Append(result.Resource.Get(ctx, &model)...)
+				if result.Diagnostics.HasError() {
+					t.Fatalf("unexpected error: %v", result.Diagnostics)
+				}
+				if identity.Name.ValueString() != result.DisplayName || model.ID.ValueString() != result.DisplayName {
+					t.Errorf("result for %v has identity %v and id %v", result.DisplayName, identity.Name, model.ID)
+				}
+				names = append(names, result.DisplayName)
+			}
+			if testCase.isError {
+				t.Fatal("expected an error")
+			}
+			if len(names) != len(testCase.expected) {
+				t.Fatalf("expected %v, got %v", testCase.expected, names)
+			}
+			for i := range names {
+				if names[i] != testCase.expected[i] {
+					t.Fatalf("expected %v, got %v", testCase.expected, names)
+				}
+			}
+		})
+	}
+}
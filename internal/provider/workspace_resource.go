@@ -2,20 +2,29 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os/exec"
 	"regexp"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -23,8 +32,26 @@ var (
 	_ resource.Resource                = &workspaceResource{}
 	_ resource.ResourceWithConfigure   = &workspaceResource{}
 	_ resource.ResourceWithImportState = &workspaceResource{}
+	_ resource.ResourceWithIdentity    = &workspaceResource{}
+	_ resource.ResourceWithModifyPlan  = &workspaceResource{}
 )
 
+// The default for `wait_timeout`.
+const defaultWorkspaceWaitTimeout = 2 * time.Minute
+
+// The values of `destroy_behavior`.
+const (
+	workspaceDestroyBehaviorDelete  = "delete"
+	workspaceDestroyBehaviorAbandon = "abandon"
+)
+
+// The default for `delete_timeout`.
+const defaultWorkspaceDeleteTimeout = 10 * time.Minute
+
+// How often the workspace list is polled while waiting for a new workspace to be visible or for a
+// deleted workspace to disappear.
+var workspaceWaitPollInterval = 5 * time.Second
+
 // NewWorkspaceResource is a helper function to simplify the provider implementation.
 func NewWorkspaceResource() resource.Resource {
 	return &workspaceResource{}
@@ -32,16 +59,73 @@ func NewWorkspaceResource() resource.Resource {
 
 // workspaceResource is the resource implementation.
 type workspaceResource struct {
-	CommandEnv    []string
-	WorkspaceData Workspaces
+	CLI            tectonclient.Client
+	WorkspaceData  *WorkspaceCache
+	DefaultRoles   []roleChange
+	Notifier       *Notifier
+	DisableDestroy bool
+	NameRules      tectonclient.NameRules
 }
 
 // workspaceResourceModel maps the resource schema data.
 type workspaceResourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	LastUpdated types.String `tfsdk:"last_updated"`
-	Name        types.String `tfsdk:"name"`
-	Live        types.Bool   `tfsdk:"live"`
+	ID              types.String   `tfsdk:"id"`
+	LastUpdated     types.String   `tfsdk:"last_updated"`
+	Name            types.String   `tfsdk:"name"`
+	Live            types.Bool     `tfsdk:"live"`
+	SkipSafetyCheck types.Bool     `tfsdk:"skip_safety_check"`
+	CopyGrantsFrom  types.String   `tfsdk:"copy_grants_from"`
+	CloneFrom       types.String   `tfsdk:"clone_from"`
+	CommandTimeout  types.String   `tfsdk:"command_timeout"`
+	AdoptExisting   types.Bool     `tfsdk:"adopt_existing"`
+	WaitTimeout     types.String   `tfsdk:"wait_timeout"`
+	DeleteTimeout   types.String   `tfsdk:"delete_timeout"`
+	DestroyBehavior types.String   `tfsdk:"destroy_behavior"`
+	RefreshContents types.Bool     `tfsdk:"refresh_contents"`
+	IsEmpty         types.Bool     `tfsdk:"is_empty"`
+	HasLiveServing  types.Bool     `tfsdk:"has_live_serving"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
+}
+
+// workspaceResourceIdentityModel maps the resource identity schema data.
+type workspaceResourceIdentityModel struct {
+	Name types.String `tfsdk:"name"`
+}
+
+// The JSON output of the `workspace_summary.py` script.
+type workspaceSummary struct {
+	FeatureViews              []string                      `json:"feature_views"`
+	FeatureServices           []string                      `json:"feature_services"`
+	Entities                  []string                      `json:"entities"`
+	DataSources               []string                      `json:"data_sources"`
+	Transformations           []string                      `json:"transformations"`
+	ActiveMaterializationJobs []workspaceMaterializationJob `json:"active_materialization_jobs"`
+}
+
+// Returns true if the workspace has no feature views and no feature services.
+func (s workspaceSummary) IsEmpty() bool {
+	return len(s.FeatureViews) == 0 && len(s.FeatureServices) == 0
+}
+
+// Returns true if the workspace may be serving online traffic, i.e. it's live and has feature
+// services.
+func (s workspaceSummary) HasLiveServing(live bool) bool {
+	return live && len(s.FeatureServices) > 0
+}
+
+// A pending or running materialization job in the output of the `workspace_summary.py` script.
+type workspaceMaterializationJob struct {
+	FeatureView string `json:"feature_view"`
+	ID          string `json:"id"`
+	State       string `json:"state"`
+}
+
+// A role granted directly to a user or service account in the JSON output of the
+// `workspace_grants.py` script.
+type workspaceGrant struct {
+	UserID           string   `json:"user_id"`
+	ServiceAccountID string   `json:"service_account_id"`
+	Roles            []string `json:"roles"`
 }
 
 // Configure adds the provider configured client to the resource.
@@ -50,19 +134,25 @@ func (r *workspaceResource) Configure(_ context.Context, req resource.ConfigureR
 		return
 	}
 
-	providerData, ok := req.ProviderData.(ProviderData)
+	providerData, ok := req.ProviderData.(*ProviderData)
 
 	if !ok {
-		resp.Diagnostics.AddError(
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeProviderBug,
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.CommandEnv = providerData.CommandEnv
+	r.CLI = providerData.CLI
 	r.WorkspaceData = providerData.WorkspaceData
+	r.DefaultRoles = providerData.DefaultRoles
+	r.Notifier = providerData.Notifier
+	r.DisableDestroy = providerData.DisableDestroy
+	r.NameRules = providerData.NameRules
 }
 
 // Metadata returns the resource type name.
@@ -71,7 +161,7 @@ func (r *workspaceResource) Metadata(_ context.Context, req resource.MetadataReq
 }
 
 // Schema defines the schema for the resource.
-func (r *workspaceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *workspaceResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -85,7 +175,7 @@ func (r *workspaceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Computed: true,
 			},
 			"name": schema.StringAttribute{
-				Description: "The name of the workspace.",
+				Description: "The name of the workspace. Also checked at plan time against the naming rules the Tecton cluster reports, if any.",
 				Required:    true,
 				Validators: []validator.String{
 					stringvalidator.RegexMatches(
@@ -98,12 +188,120 @@ func (r *workspaceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Description: "True if this workspace is a live workspace. False otherwise (i.e. it is a development workspace)",
 				Required:    true,
 			},
+			"copy_grants_from": schema.StringAttribute{
+				Description: "The name of an existing workspace whose role grants are copied to this workspace when it is created, e.g. the workspace this one replaces. " +
+					"Every role granted directly to a user or service account on that workspace is also granted on this one, so that access doesn't break when a workspace is replaced under a new name. " +
+					"The old workspace must still exist when this one is created. Changing this after creation has no effect.",
+				Optional: true,
+			},
+			"clone_from": schema.StringAttribute{
+				Description: "The name of an existing workspace whose feature repo is applied to this workspace when it is created, so that it starts with the same " +
+					"feature views, feature services and other objects, e.g. to roll out a templated workspace per team or environment. The repo that was last applied " +
+					"to that workspace is restored with `tecton restore` and applied with `tecton apply`. A failed clone is reported as a warning, since the workspace " +
+					"was created. Changing this after creation has no effect.",
+				Optional: true,
+			},
+			"adopt_existing": schema.BoolAttribute{
+				Description: "If true, creating this resource when a workspace with the same name and the same `live` setting already exists adopts that workspace " +
+					"instead of failing, as an alternative to `terraform import`. `copy_grants_from`, `clone_from` and the provider's `default_role` blocks aren't applied to an adopted workspace. " +
+					"Creation still fails if the existing workspace's `live` setting differs. Only affects creation. Defaults to false.",
+				Optional: true,
+			},
+			"command_timeout": schema.StringAttribute{
+				Description: "Overrides the provider's `command_timeout` for the commands that create and delete this workspace, e.g. because deleting a large workspace takes longer. " +
+					"Must be applied before a destroy to take effect.",
+				Optional: true,
+				Validators: []validator.String{
+					durationValidator(),
+				},
+			},
+			"wait_timeout": schema.StringAttribute{
+				Description: "After a workspace is created, the provider polls the workspace list until the workspace is visible, " +
+					"so that resources that depend on it in the same apply, e.g. access policies, don't race its creation. " +
+					"This is how long to wait, as a Go duration string, e.g. \"5m\". If the workspace still isn't visible, a warning is reported. " +
+					"Set to \"0s\" to not wait. Defaults to \"2m\".",
+				Optional: true,
+				Validators: []validator.String{
+					durationValidator(),
+				},
+			},
+			"delete_timeout": schema.StringAttribute{
+				Description: "The tecton CLI can return before a large workspace is fully deleted, so after deleting a workspace the provider polls the workspace list " +
+					"until the workspace is gone before removing it from the state. This is how long to wait, as a Go duration string, e.g. \"30m\". " +
+					"If the workspace is still listed, the destroy fails and the workspace stays in the state. Set to \"0s\" to not wait. Defaults to \"10m\". " +
+					"Must be applied before a destroy to take effect.",
+				Optional: true,
+				Validators: []validator.String{
+					durationValidator(),
+				},
+			},
+			"destroy_behavior": schema.StringAttribute{
+				Description: "What happens to the workspace when this resource is destroyed, e.g. because it was removed from the configuration. " +
+					"\"delete\" deletes the workspace. \"abandon\" only removes the resource from the state and leaves the workspace in Tecton, " +
+					"e.g. to move the workspace to another Terraform state, and isn't blocked by the provider's `disable_destroy`. " +
+					"Must be applied before the destroy to take effect. Defaults to \"delete\".",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(workspaceDestroyBehaviorDelete, workspaceDestroyBehaviorAbandon),
+				},
+			},
+			"refresh_contents": schema.BoolAttribute{
+				Description: "If true, `is_empty` and `has_live_serving` are refreshed on every read. Reading a workspace's contents runs a Tecton SDK script that reads every " +
+					"feature view in the workspace, so this slows down plans in proportion to the size of the workspace. Defaults to false, in which case both attributes are null. " +
+					"The `tecton_workspace` data source reads them on demand instead.",
+				Optional: true,
+			},
+			"is_empty": schema.BoolAttribute{
+				Description: "True if the workspace has no feature views and no feature services, e.g. to guard destructive changes with a precondition. " +
+					"Only set if `refresh_contents` is true. Null if the workspace's contents couldn't be read, which is reported as a warning.",
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"has_live_serving": schema.BoolAttribute{
+				Description: "True if the workspace is live and has feature services, i.e. it may be serving online traffic. " +
+					"Only set if `refresh_contents` is true. Null if the workspace's contents couldn't be read, which is reported as a warning.",
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"skip_safety_check": schema.BoolAttribute{
+				Description: "Before a live workspace is deleted, the provider checks that it has no feature services and no pending or running materialization jobs, " +
+					"and refuses to delete it otherwise. Set to true to skip this check. Must be applied before the destroy to take effect. Defaults to false.",
+				Optional: true,
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create:            true,
+				Read:              true,
+				Update:            true,
+				Delete:            true,
+				CreateDescription: "How long creating the resource may take. Defaults to \"30m\".",
+				ReadDescription:   "How long reading the resource may take. Defaults to \"30m\".",
+				UpdateDescription: "How long updating the resource may take. Defaults to \"30m\".",
+				DeleteDescription: "How long deleting the resource may take. Defaults to \"30m\".",
+			}),
+		},
+	}
+}
+
+// IdentitySchema defines the identity of the resource, which is used to import and list workspaces.
+func (r *workspaceResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"name": identityschema.StringAttribute{
+				Description:       "The name of the workspace.",
+				RequiredForImport: true,
+			},
 		},
 	}
 }
 
 // Create creates the resource and sets the initial Terraform state.
 func (r *workspaceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_workspace", "Create")
 	// Retrieve values from plan
 	var plan workspaceResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -112,34 +310,86 @@ func (r *workspaceResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	// Create new workspace. The name should already be validated.
-	var liveArg string
-	if plan.Live.ValueBool() {
-		liveArg = "--live"
-	} else {
-		liveArg = "--no-live"
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := withOperationTimeout(ctx, "create", createTimeout)
+	defer cancel()
+
+	// Adopt the workspace instead of creating it if it already exists
+	if plan.AdoptExisting.ValueBool() && r.AdoptWorkspace(ctx, &plan, &resp.Diagnostics) {
+		plan.ID = plan.Name
+		refreshWorkspaceUsage(ctx, r.CLI, &plan, &resp.Diagnostics)
+		plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		setWorkspaceIdentity(ctx, resp.Identity, plan.Name, &resp.Diagnostics)
+		return
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Report the changes made in Tecton to the notification webhook, even if creation fails
+	cli, changes := withCommandTimeout(r.CLI, plan.CommandTimeout).RecordingChanges()
+	defer r.Notifier.Notify(ctx, fmt.Sprintf("tecton_workspace '%v'", plan.Name.ValueString()), changes, &resp.Diagnostics)
+
 	// This will automatically make the TF service account an owner of the workspace, but that's fine since it's an admin anyway.
-	var cmd = exec.Command("tecton", "workspace", "create", plan.Name.ValueString(), liveArg)
-	cmd.Env = r.CommandEnv
-	tflog.Info(ctx, fmt.Sprintf("Creating workspace '%v'", plan.Name.ValueString()))
+	err := cli.CreateWorkspace(ctx, plan.Name.ValueString(), plan.Live.ValueBool())
+	if err != nil {
+		AddCommandError(&resp.Diagnostics, "Failed to create Tecton workspace", err)
+		return
+	}
+	if !cli.DryRun {
+		r.WorkspaceData.Add(plan.Name.ValueString(), plan.Live.ValueBool())
+	}
 
-	output, err := cmd.CombinedOutput()
+	// The workspace exists now, so a workspace that doesn't become visible is only a warning
+	err = waitForWorkspace(ctx, cli, plan.Name.ValueString(), plan.WaitTimeout)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to create Tecton workspace",
+		AddWarning(
+			&resp.Diagnostics,
+			ClassifyError(err),
+			"Tecton workspace not visible yet",
 			fmt.Sprintf(
-				"Command to create Tecton workspace '%v' failed.\nError: %v\nOutput: %v",
+				"Created workspace '%v', but it isn't visible yet, so resources that depend on it may fail. Increase `wait_timeout` to wait longer.\nError: %v",
 				plan.Name.ValueString(),
 				err.Error(),
-				string(output),
 			),
 		)
-		return
 	}
 
-	// Generated computed values
+	// Generated computed values. A new workspace has no contents yet, unless it's cloned.
 	plan.ID = plan.Name
+	plan.IsEmpty, plan.HasLiveServing = types.BoolNull(), types.BoolNull()
+	if plan.RefreshContents.ValueBool() {
+		plan.IsEmpty = types.BoolValue(true)
+		plan.HasLiveServing = types.BoolValue(false)
+	}
+	if plan.CloneFrom.ValueString() != "" {
+		// The workspace exists now, so a failure to clone it is only a warning, like a failure to copy grants
+		err = cli.CloneWorkspace(ctx, plan.CloneFrom.ValueString(), plan.Name.ValueString())
+		if err != nil {
+			AddWarning(
+				&resp.Diagnostics,
+				ClassifyError(err),
+				"Failed to clone Tecton workspace",
+				fmt.Sprintf(
+					"Created workspace '%v', but failed to apply the feature repo of workspace '%v' to it. The repo must be applied by hand.\nError: %v",
+					plan.Name.ValueString(),
+					plan.CloneFrom.ValueString(),
+					err.Error(),
+				),
+			)
+		}
+		if !cli.DryRun {
+			refreshWorkspaceUsage(ctx, cli, &plan, &resp.Diagnostics)
+		}
+	}
 	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850)) // Time format copy-pasted from Hashicorp tutorial
 
 	// Set state to fully populated data
@@ -148,10 +398,136 @@ func (r *workspaceResource) Create(ctx context.Context, req resource.CreateReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	setWorkspaceIdentity(ctx, resp.Identity, plan.Name, &resp.Diagnostics)
+
+	// The workspace exists now, so a failure to copy grants is only a warning. Failing would taint the
+	// workspace and replace it on the next apply.
+	if plan.CopyGrantsFrom.ValueString() != "" {
+		err = CopyWorkspaceGrants(ctx, cli, plan.CopyGrantsFrom.ValueString(), plan.Name.ValueString())
+		if err != nil {
+			AddWarning(
+				&resp.Diagnostics,
+				ClassifyError(err),
+				"Failed to copy Tecton workspace grants",
+				fmt.Sprintf(
+					"Created workspace '%v', but failed to copy role grants from workspace '%v'. The missing grants must be added by hand.\nError: %v",
+					plan.Name.ValueString(),
+					plan.CopyGrantsFrom.ValueString(),
+					err.Error(),
+				),
+			)
+		}
+	}
+	if len(r.DefaultRoles) > 0 {
+		tflog.Info(ctx, fmt.Sprintf("Granting %v default roles on workspace '%v'", len(r.DefaultRoles), plan.Name.ValueString()))
+		err = ApplyRoleChanges(ctx, cli, plan.Name.ValueString(), r.DefaultRoles)
+		if err != nil {
+			AddWarning(
+				&resp.Diagnostics,
+				ClassifyError(err),
+				"Failed to grant default roles",
+				fmt.Sprintf(
+					"Created workspace '%v', but failed to grant the provider's default roles on it. The missing grants must be added by hand.\nError: %v",
+					plan.Name.ValueString(),
+					err.Error(),
+				),
+			)
+		}
+	}
+}
+
+// Polls the workspace list until the workspace is visible. timeout is the resource's `wait_timeout`.
+func waitForWorkspace(ctx context.Context, cli tectonclient.Client, name string, timeout types.String) error {
+	return pollWorkspaceList(ctx, cli, name, timeout, defaultWorkspaceWaitTimeout, true)
+}
+
+// Polls the workspace list until the workspace is no longer listed. timeout is the resource's
+// `delete_timeout`.
+func waitForWorkspaceDeletion(ctx context.Context, cli tectonclient.Client, name string, timeout types.String) error {
+	return pollWorkspaceList(ctx, cli, name, timeout, defaultWorkspaceDeleteTimeout, false)
+}
+
+// Polls the workspace list until whether the workspace is listed matches listed. A failed listing
+// counts as not matching. Returns immediately in dry-run mode, since the workspace wasn't changed.
+func pollWorkspaceList(ctx context.Context, cli tectonclient.Client, name string, timeout types.String, defaultTimeout time.Duration, listed bool) error {
+	if cli.DryRun {
+		return nil
+	}
+	waitTimeout := defaultTimeout
+	if !timeout.IsNull() {
+		var err error
+		waitTimeout, err = time.ParseDuration(timeout.ValueString())
+		if err != nil {
+			return err
+		}
+	}
+	if waitTimeout <= 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(waitTimeout)
+	for {
+		workspaces, err := cli.ListWorkspaces(ctx)
+		if err == nil {
+			_, err = GetWorkspace(ctx, workspaces, name)
+			if (err == nil) == listed {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			if listed {
+				return diagnostics.Wrap(err, "Workspace '%v' is still not visible after %v", name, waitTimeout)
+			} else if err == nil {
+				return fmt.Errorf("Workspace '%v' is still listed %v after it was deleted.", name, waitTimeout)
+			}
+			return diagnostics.Wrap(err, "Failed to check whether workspace '%v' was deleted after %v", name, waitTimeout)
+		}
+
+		if listed {
+			tflog.Info(ctx, fmt.Sprintf("Waiting for workspace '%v' to be visible", name))
+		} else {
+			tflog.Info(ctx, fmt.Sprintf("Waiting for deleted workspace '%v' to disappear", name))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(workspaceWaitPollInterval):
+		}
+	}
+}
+
+// Returns true if the workspace in plan already exists and can be adopted. Adds an error to diags
+// if it exists but its live setting doesn't match plan, since that can't be changed.
+func (r *workspaceResource) AdoptWorkspace(ctx context.Context, plan *workspaceResourceModel, diags *diag.Diagnostics) bool {
+	isLive, err := r.FindWorkspace(ctx, plan.Name.ValueString())
+	if err != nil {
+		if !errors.Is(err, diagnostics.ErrWorkspaceNotFound) {
+			AddCommandError(diags, "Error Reading Workspace", err)
+		}
+		return false
+	}
+	if isLive != plan.Live.ValueBool() {
+		AddAttributeError(
+			diags,
+			path.Root("live"),
+			ErrorCodeUnsupportedChange,
+			"Cannot Adopt Workspace",
+			fmt.Sprintf(
+				"Workspace '%v' already exists with `live = %v`, and Tecton does not support changing whether a workspace is live or development.",
+				plan.Name.ValueString(),
+				isLive,
+			),
+		)
+		return false
+	}
+	tflog.Info(ctx, fmt.Sprintf("Adopting existing workspace '%v'", plan.Name.ValueString()))
+	return true
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (r *workspaceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_workspace", "Read")
 	// Get current state
 	var state workspaceResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -160,18 +536,27 @@ func (r *workspaceResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withOperationTimeout(ctx, "read", readTimeout)
+	defer cancel()
+
 	// If we imported this workspace the name will be empty.
 	if state.Name.ValueString() == "" {
 		state.Name = state.ID
 	}
 
-	// Get workspace values from prefetched list
-	isLive, err := GetWorkspace(ctx, r.WorkspaceData, state.Name.ValueString())
+	// Get workspace values from the shared list
+	isLive, err := r.FindWorkspace(ctx, state.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error Reading Workspace", err.Error())
+		AddCommandError(&resp.Diagnostics, "Error Reading Workspace", err)
 		return
 	}
 	state.Live = types.BoolValue(isLive)
+	refreshWorkspaceUsage(ctx, r.CLI, &state, &resp.Diagnostics)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -179,10 +564,13 @@ func (r *workspaceResource) Read(ctx context.Context, req resource.ReadRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	setWorkspaceIdentity(ctx, resp.Identity, state.Name, &resp.Diagnostics)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *workspaceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_workspace", "Update")
 	// Retrieve values from plan
 	var plan workspaceResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -191,6 +579,14 @@ func (r *workspaceResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withOperationTimeout(ctx, "update", updateTimeout)
+	defer cancel()
+
 	// Also retrieve current state
 	var state workspaceResourceModel
 	diags = req.State.Get(ctx, &state)
@@ -202,7 +598,9 @@ func (r *workspaceResource) Update(ctx context.Context, req resource.UpdateReque
 	// Tecton does not support renaming a workspace or changing it between live/dev. So if anything is different
 	// we need to fail.
 	if state.Name != plan.Name {
-		resp.Diagnostics.AddError(
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeUnsupportedChange,
 			"Error Updating Workspace",
 			fmt.Sprintf(
 				"Tecton does not support renaming workspaces, so cannot rename workspace '%v' to '%v'",
@@ -213,7 +611,9 @@ func (r *workspaceResource) Update(ctx context.Context, req resource.UpdateReque
 	}
 
 	if state.Live != plan.Live {
-		resp.Diagnostics.AddError(
+		AddError(
+			&resp.Diagnostics,
+			ErrorCodeUnsupportedChange,
 			"Error Updating Workspace",
 			fmt.Sprintf(
 				"Tecton does not support updating whether a workspace is live or development, so cannot change `live` field from '%v' to '%v'",
@@ -222,10 +622,52 @@ func (r *workspaceResource) Update(ctx context.Context, req resource.UpdateReque
 			),
 		)
 	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only provider-side settings such as `skip_safety_check` can change, so there is nothing to do
+	// in Tecton
+	plan.ID = state.ID
+	plan.IsEmpty = state.IsEmpty
+	plan.HasLiveServing = state.HasLiveServing
+	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	setWorkspaceIdentity(ctx, resp.Identity, plan.Name, &resp.Diagnostics)
+}
+
+// ModifyPlan checks the name against the cluster's rules, and fails plans that destroy the workspace if
+// the provider's `disable_destroy` is set, unless the workspace is abandoned.
+func (r *workspaceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_workspace", "ModifyPlan")
+	if !req.Plan.Raw.IsNull() {
+		var name types.String
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+		CheckNameRule(r.NameRules.WorkspaceName, name, path.Root("name"), &resp.Diagnostics)
+		return
+	}
+	if req.State.Raw.IsNull() {
+		return
+	}
+	var name, destroyBehavior types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("destroy_behavior"), &destroyBehavior)...)
+	if resp.Diagnostics.HasError() || destroyBehavior.ValueString() == workspaceDestroyBehaviorAbandon {
+		return
+	}
+	CheckDestroyAllowed(r.DisableDestroy, fmt.Sprintf("tecton_workspace '%v'", name.ValueString()), &resp.Diagnostics)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *workspaceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.CLI.WithCorrelationID(ctx)
+	defer r.CLI.LogOperationSummary(ctx, "tecton_workspace", "Delete")
 	// Get current state
 	var state workspaceResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -234,46 +676,206 @@ func (r *workspaceResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := withOperationTimeout(ctx, "delete", deleteTimeout)
+	defer cancel()
+
+	if state.DestroyBehavior.ValueString() == workspaceDestroyBehaviorAbandon {
+		tflog.Info(ctx, fmt.Sprintf("Removing workspace '%v' from the state without deleting it, since `destroy_behavior` is \"abandon\"", state.Name.ValueString()))
+		return
+	}
+
+	if !CheckDestroyAllowed(r.DisableDestroy, fmt.Sprintf("tecton_workspace '%v'", state.Name.ValueString()), &resp.Diagnostics) {
+		return
+	}
+
+	cli, changes := withCommandTimeout(r.CLI, state.CommandTimeout).RecordingChanges()
+	defer r.Notifier.Notify(ctx, fmt.Sprintf("tecton_workspace '%v'", state.Name.ValueString()), changes, &resp.Diagnostics)
+
+	DeleteWorkspace(ctx, cli, state.Name.ValueString(), state.Live.ValueBool(), state.SkipSafetyCheck.ValueBool(), state.DeleteTimeout, &resp.Diagnostics)
+	if !resp.Diagnostics.HasError() && !cli.DryRun {
+		r.WorkspaceData.Remove(state.Name.ValueString())
+	}
+}
+
+func (r *workspaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Retrieve import ID and save to id attribute
+	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("name"), req, resp)
+}
+
+// Deletes a workspace and waits until it's no longer listed. Unless skipSafetyCheck is set, a live
+// workspace that may be serving traffic isn't deleted. Failures are added to diags. deleteTimeout is
+// the resource's `delete_timeout`.
+func DeleteWorkspace(ctx context.Context, cli tectonclient.Client, name string, live bool, skipSafetyCheck bool, deleteTimeout types.String, diags *diag.Diagnostics) {
+	// Refuse to tear down serving infrastructure unless explicitly asked to
+	if live && !skipSafetyCheck {
+		tflog.Info(ctx, fmt.Sprintf("Checking that live workspace '%v' is safe to delete", name))
+		summary, err := GetWorkspaceSummary(ctx, cli, name)
+		if err != nil {
+			AddError(
+				diags,
+				ClassifyError(err),
+				"Failed to check Tecton workspace",
+				fmt.Sprintf(
+					"Failed to check whether live workspace '%v' is safe to delete. Set `skip_safety_check = true` to skip this check.\nError: %v",
+					name,
+					err.Error(),
+				),
+			)
+			return
+		}
+		if len(summary.FeatureServices) > 0 || len(summary.ActiveMaterializationJobs) > 0 {
+			var jobs []string
+			for _, job := range summary.ActiveMaterializationJobs {
+				jobs = append(jobs, fmt.Sprintf("%v (%v, %v)", job.FeatureView, job.ID, job.State))
+			}
+			AddError(
+				diags,
+				ErrorCodeUnsafeOperation,
+				"Live Workspace Is In Use",
+				fmt.Sprintf(
+					"Refusing to delete live workspace '%v' since it may be serving traffic. "+
+						"Set `skip_safety_check = true` and apply before destroying to delete it anyway.\n"+
+						"Feature services: [%v]\nActive materialization jobs: [%v]",
+					name,
+					strings.Join(summary.FeatureServices, ", "),
+					strings.Join(jobs, ", "),
+				),
+			)
+			return
+		}
+	}
+
 	// Delete workspace
-	var cmd = exec.Command("tecton", "workspace", "delete", "--yes", state.Name.ValueString())
-	cmd.Env = r.CommandEnv
-	tflog.Info(ctx, fmt.Sprintf("Deleting workspace '%v'", state.Name.ValueString()))
+	err := cli.DeleteWorkspace(ctx, name, live)
+	if err != nil {
+		AddCommandError(diags, "Failed to delete Tecton workspace", err)
+		return
+	}
 
-	output, err := cmd.CombinedOutput()
+	// Keep the workspace in the state until the deletion has finished
+	err = waitForWorkspaceDeletion(ctx, cli, name, deleteTimeout)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to delete Tecton workspace",
-			fmt.Sprintf("Command to delete Tecton workspace '%v' failed.\nError: %v\nOutput: %v", state.Name.ValueString(), err.Error(), string(output)),
+		AddError(
+			diags,
+			ClassifyError(err),
+			"Tecton workspace deletion not finished",
+			fmt.Sprintf(
+				"Deleted workspace '%v', but it is still listed, so it is kept in the state. Increase `delete_timeout` to wait longer.\nError: %v",
+				name,
+				err.Error(),
+			),
 		)
+	}
+}
+
+// Sets the identity of a workspace, if the Terraform version supports resource identities.
+func setWorkspaceIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, name types.String, diags *diag.Diagnostics) {
+	if identity == nil {
 		return
 	}
+	diags.Append(identity.Set(ctx, workspaceResourceIdentityModel{Name: name})...)
 }
 
-func (r *workspaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+// Summarizes the feature views, feature services and active materialization jobs in a workspace.
+func GetWorkspaceSummary(ctx context.Context, cli tectonclient.Client, workspaceName string) (workspaceSummary, error) {
+	output, err := cli.RunScript(ctx, "workspace_summary.py", workspaceName)
+	if err != nil {
+		return workspaceSummary{}, err
+	}
+	var summary workspaceSummary
+	err = json.Unmarshal(output, &summary)
+	if err != nil {
+		return workspaceSummary{}, &diagnostics.ParseError{Source: "workspace_summary.py", Output: string(output), Err: err}
+	}
+	return summary, nil
 }
 
-// Scans prefetched workspace data for a particular workspace. Returns (isLive, error) where isLive is true
-// if the workspace is a live workspace, and false if it is a development workspace. If error != nil, then
-// the value of isLive is undefined.
-func GetWorkspace(ctx context.Context, workspaces Workspaces, workspaceName string) (bool, error) {
-	var workspaceFound = false
-	var isLive = false
-	for _, ws := range workspaces.Lives {
-		if ws == workspaceName {
-			isLive = true
-			workspaceFound = true
+// Sets `is_empty` and `has_live_serving` from the workspace's current contents, or to null unless
+// `refresh_contents` is set. If they can't be read, e.g. because the Tecton SDK isn't importable, a
+// warning is added and the previous values are kept, so that a refresh doesn't fail because of the
+// attributes.
+func refreshWorkspaceUsage(ctx context.Context, cli tectonclient.Client, model *workspaceResourceModel, diags *diag.Diagnostics) {
+	if !model.RefreshContents.ValueBool() {
+		model.IsEmpty, model.HasLiveServing = types.BoolNull(), types.BoolNull()
+		return
+	}
+	summary, err := GetWorkspaceSummary(ctx, cli, model.Name.ValueString())
+	if err != nil {
+		AddWarning(
+			diags,
+			ClassifyError(err),
+			"Failed to read Tecton workspace contents",
+			fmt.Sprintf("Failed to read the contents of workspace '%v', so `is_empty` and `has_live_serving` weren't refreshed.\nError: %v", model.Name.ValueString(), err.Error()),
+		)
+		if model.IsEmpty.IsUnknown() {
+			model.IsEmpty = types.BoolNull()
 		}
+		if model.HasLiveServing.IsUnknown() {
+			model.HasLiveServing = types.BoolNull()
+		}
+		return
+	}
+	model.IsEmpty = types.BoolValue(summary.IsEmpty())
+	model.HasLiveServing = types.BoolValue(summary.HasLiveServing(model.Live.ValueBool()))
+}
+
+// Grants every role granted directly to a user or service account on workspace from to the same
+// user or service account on workspace to.
+func CopyWorkspaceGrants(ctx context.Context, cli tectonclient.Client, from string, to string) error {
+	tflog.Info(ctx, fmt.Sprintf("Copying role grants from workspace '%v' to workspace '%v'", from, to))
+	output, err := cli.RunScript(ctx, "workspace_grants.py", from)
+	if err != nil {
+		return err
 	}
-	for _, ws := range workspaces.Devs {
-		if ws == workspaceName {
-			isLive = false
-			workspaceFound = true
+	var grants []workspaceGrant
+	err = json.Unmarshal(output, &grants)
+	if err != nil {
+		return &diagnostics.ParseError{Source: "workspace_grants.py", Output: string(output), Err: err}
+	}
+
+	var changes []roleChange
+	for _, grant := range grants {
+		for _, role := range grant.Roles {
+			changes = append(changes, roleChange{tectonclient.Principal{UserID: grant.UserID, ServiceAccountID: grant.ServiceAccountID}, role, true})
 		}
 	}
-	if !workspaceFound {
-		return false, fmt.Errorf("Tecton workspace with name '%v' does not exist.", workspaceName)
+	return ApplyRoleChanges(ctx, cli, to, changes)
+}
+
+// Like GetWorkspace, but if the workspace isn't in the shared workspace data, e.g. because it was
+// created after the workspaces were listed, the workspaces are listed again once before reporting
+// the workspace as missing. The new list is shared with every other resource.
+func (r *workspaceResource) FindWorkspace(ctx context.Context, workspaceName string) (bool, error) {
+	workspaces, err := r.WorkspaceData.Get(ctx)
+	if err != nil {
+		return false, err
+	}
+	isLive, err := GetWorkspace(ctx, workspaces, workspaceName)
+	if err == nil {
+		return isLive, nil
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Workspace '%v' is not in the shared workspace list, so listing workspaces again", workspaceName))
+	workspaces, err = r.WorkspaceData.Refresh(ctx, r.CLI.ListWorkspaces)
+	if err != nil {
+		return false, err
+	}
+	return GetWorkspace(ctx, workspaces, workspaceName)
+}
+
+// Looks up a particular workspace in listed workspace data. Returns (isLive, error) where isLive is true
+// if the workspace is a live workspace, and false if it is a development workspace. If error != nil, then
+// the value of isLive is undefined. Returns a *diagnostics.WorkspaceNotFoundError if the workspace
+// isn't listed.
+func GetWorkspace(ctx context.Context, workspaces tectonclient.Workspaces, workspaceName string) (bool, error) {
+	isLive, found := workspaces.Lookup(workspaceName)
+	if !found {
+		return false, &diagnostics.WorkspaceNotFoundError{Name: workspaceName}
 	}
 	return isLive, nil
 }
@@ -1,10 +1,23 @@
 package provider
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/tectonclient"
 )
 
 func TestAccWorkspaceResource(t *testing.T) {
@@ -43,7 +56,26 @@ resource "tecton_workspace" "tf_provider_acc_test_dev" {
 				ImportStateVerify: true,
 				// The last_updated attribute does not exist in the HashiCups
 				// API, therefore there is no value for it during import.
-				ImportStateVerifyIgnore: []string{"last_updated"},
+				ImportStateVerifyIgnore: []string{"last_updated", "skip_safety_check"},
+			},
+			// Update skip_safety_check
+			{
+				Config: providerConfig + `
+resource "tecton_workspace" "tf_provider_acc_test_live" {
+	name = "tf-provider-acc-test-live"
+	live = true
+	skip_safety_check = true
+}
+
+resource "tecton_workspace" "tf_provider_acc_test_dev" {
+	name = "tf-provider-acc-test-dev"
+	live = false
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tecton_workspace.tf_provider_acc_test_live", "skip_safety_check", "true"),
+					resource.TestCheckResourceAttr("tecton_workspace.tf_provider_acc_test_live", "live", "true"),
+				),
 			},
 			// Update name fails
 			{
@@ -89,3 +121,285 @@ resource "tecton_workspace" "tf_provider_acc_invalid_name" {
 		},
 	})
 }
+
+func TestCopyWorkspaceGrants(t *testing.T) {
+	calls := fakeTectonPythonCLI(
+		t,
+		`[{"user_id": "alice@example.com", "roles": ["editor", "viewer"]}, {"service_account_id": "abc123", "roles": ["owner"]}]`,
+		"",
+	)
+
+	err := CopyWorkspaceGrants(context.Background(), tectonclient.Client{}, "old", "new")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatalf("failed to read calls: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	sort.Strings(lines)
+	expected := []string{
+		"access-control assign-role --role editor --workspace new --user alice@example.com",
+		"access-control assign-role --role owner --workspace new --service-account abc123",
+		"access-control assign-role --role viewer --workspace new --user alice@example.com",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected commands:\n%v\ngot:\n%v", strings.Join(expected, "\n"), strings.Join(lines, "\n"))
+	}
+}
+
+func TestWorkspaceResourceFindWorkspace(t *testing.T) {
+	fakeTectonCLI(t, `printf 'Live Workspaces:\n  prod\n  new-prod\n\nDevelopment Workspaces:\n  dev\n'`)
+	r := &workspaceResource{WorkspaceData: NewWorkspaceCache(tectonclient.Workspaces{Lives: []string{"prod"}, Devs: []string{"dev"}})}
+	ctx := context.Background()
+
+	isLive, err := r.FindWorkspace(ctx, "new-prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !isLive {
+		t.Error("expected new-prod to be live")
+	}
+	if workspaces, _ := r.WorkspaceData.Get(context.Background()); len(workspaces.Lives) != 2 {
+		t.Errorf("expected the workspace data to be refreshed, got %v", workspaces)
+	}
+
+	_, err = r.FindWorkspace(ctx, "missing")
+	if !errors.Is(err, diagnostics.ErrWorkspaceNotFound) {
+		t.Errorf("expected a workspace not found error, got: %v", err)
+	}
+}
+
+func TestWorkspaceResourceCreate_adoptExisting(t *testing.T) {
+	testCases := map[string]struct {
+		live          bool
+		adoptExisting bool
+		isError       bool
+		expectCreate  bool
+	}{
+		"adopt": {
+			live:          true,
+			adoptExisting: true,
+		},
+		"live mismatch": {
+			live:          false,
+			adoptExisting: true,
+			isError:       true,
+		},
+		"without adopt_existing": {
+			live:          true,
+			adoptExisting: false,
+			isError:       true,
+			expectCreate:  true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			calls := filepath.Join(t.TempDir(), "calls")
+			fakeTectonCLI(t, `echo "$@" >> `+calls+`
+case "$2" in
+  list) printf 'Live Workspaces:\n  prod\n\nDevelopment Workspaces:\n' ;;
+  *) echo "Workspace prod already exists"; exit 1 ;;
+esac`)
+			plan := resourcePlan(t, NewWorkspaceResource(), map[string]tftypes.Value{
+				"name":           tftypes.NewValue(tftypes.String, "prod"),
+				"live":           tftypes.NewValue(tftypes.Bool, testCase.live),
+				"adopt_existing": tftypes.NewValue(tftypes.Bool, testCase.adoptExisting),
+			})
+			req := fwresource.CreateRequest{Plan: plan}
+			resp := fwresource.CreateResponse{
+				State: tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)},
+			}
+			NewWorkspaceResource().Create(context.Background(), req, &resp)
+			if resp.Diagnostics.HasError() != testCase.isError {
+				t.Fatalf("expected error: %v, got: %v", testCase.isError, resp.Diagnostics)
+			}
+			data, _ := os.ReadFile(calls)
+			if strings.Contains(string(data), "workspace create") != testCase.expectCreate {
+				t.Errorf("expected create: %v, got commands:\n%v", testCase.expectCreate, string(data))
+			}
+			if !testCase.isError && resp.State.Raw.IsNull() {
+				t.Error("expected the adopted workspace to be saved to state")
+			}
+		})
+	}
+}
+
+func TestWorkspaceResource_disableDestroy(t *testing.T) {
+	calls := filepath.Join(t.TempDir(), "calls")
+	fakeTectonCLI(t, `echo "$@" >> `+calls)
+	plan := resourcePlan(t, NewWorkspaceResource(), map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "prod"),
+		"live": tftypes.NewValue(tftypes.Bool, false),
+	})
+	state := tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}
+	r := &workspaceResource{DisableDestroy: true}
+
+	planResp := fwresource.ModifyPlanResponse{}
+	r.ModifyPlan(context.Background(), fwresource.ModifyPlanRequest{
+		State: state,
+		Plan:  tfsdk.Plan{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)},
+	}, &planResp)
+	if !planResp.Diagnostics.HasError() {
+		t.Error("expected the plan to fail")
+	}
+
+	deleteResp := fwresource.DeleteResponse{}
+	r.Delete(context.Background(), fwresource.DeleteRequest{State: state}, &deleteResp)
+	if !deleteResp.Diagnostics.HasError() {
+		t.Error("expected the delete to fail")
+	}
+	if data, _ := os.ReadFile(calls); len(data) > 0 {
+		t.Errorf("expected no commands, got:\n%v", string(data))
+	}
+}
+
+func TestWaitForWorkspace(t *testing.T) {
+	workspaceWaitPollInterval = time.Millisecond
+	testCases := map[string]struct {
+		timeout types.String
+		isError bool
+	}{
+		"visible after polling": {timeout: types.StringNull()},
+		"timed out":             {timeout: types.StringValue("1ns"), isError: true},
+		"disabled":              {timeout: types.StringValue("0s")},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// The workspace only shows up in the second listing
+			calls := filepath.Join(t.TempDir(), "calls")
+			fakeTectonCLI(t, `echo "$@" >> `+calls+`
+if [ "$(wc -l < `+calls+`)" -ge 2 ]; then
+  printf 'Live Workspaces:\n  prod\n\nDevelopment Workspaces:\n'
+else
+  printf 'Live Workspaces:\n\nDevelopment Workspaces:\n'
+fi`)
+			err := waitForWorkspace(context.Background(), tectonclient.Client{}, "prod", testCase.timeout)
+			if (err != nil) != testCase.isError {
+				t.Errorf("expected error: %v, got: %v", testCase.isError, err)
+			}
+		})
+	}
+}
+
+func TestWaitForWorkspaceDeletion(t *testing.T) {
+	workspaceWaitPollInterval = time.Millisecond
+	testCases := map[string]struct {
+		timeout types.String
+		isError bool
+	}{
+		"gone after polling": {timeout: types.StringNull()},
+		"timed out":          {timeout: types.StringValue("1ns"), isError: true},
+		"disabled":           {timeout: types.StringValue("0s")},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// The workspace is only gone from the second listing
+			calls := filepath.Join(t.TempDir(), "calls")
+			fakeTectonCLI(t, `echo "$@" >> `+calls+`
+if [ "$(wc -l < `+calls+`)" -ge 2 ]; then
+  printf 'Live Workspaces:\n\nDevelopment Workspaces:\n'
+else
+  printf 'Live Workspaces:\n  prod\n\nDevelopment Workspaces:\n'
+fi`)
+			err := waitForWorkspaceDeletion(context.Background(), tectonclient.Client{}, "prod", testCase.timeout)
+			if (err != nil) != testCase.isError {
+				t.Errorf("expected error: %v, got: %v", testCase.isError, err)
+			}
+		})
+	}
+}
+
+func TestWorkspaceResource_destroyBehaviorAbandon(t *testing.T) {
+	calls := filepath.Join(t.TempDir(), "calls")
+	fakeTectonCLI(t, `echo "$@" >> `+calls)
+	plan := resourcePlan(t, NewWorkspaceResource(), map[string]tftypes.Value{
+		"name":             tftypes.NewValue(tftypes.String, "prod"),
+		"live":             tftypes.NewValue(tftypes.Bool, true),
+		"destroy_behavior": tftypes.NewValue(tftypes.String, "abandon"),
+	})
+	state := tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}
+	// Abandoning the workspace doesn't destroy anything, so it's allowed even if destroys are disabled
+	r := &workspaceResource{DisableDestroy: true}
+
+	planResp := fwresource.ModifyPlanResponse{}
+	r.ModifyPlan(context.Background(), fwresource.ModifyPlanRequest{
+		State: state,
+		Plan:  tfsdk.Plan{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)},
+	}, &planResp)
+	if planResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected plan error: %v", planResp.Diagnostics)
+	}
+
+	deleteResp := fwresource.DeleteResponse{}
+	r.Delete(context.Background(), fwresource.DeleteRequest{State: state}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected delete error: %v", deleteResp.Diagnostics)
+	}
+	if data, _ := os.ReadFile(calls); len(data) > 0 {
+		t.Errorf("expected no commands, got:\n%v", string(data))
+	}
+}
+
+func TestWorkspaceResourceModifyPlan_nameRules(t *testing.T) {
+	plan := resourcePlan(t, NewWorkspaceResource(), map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "Prod_Live"),
+		"live": tftypes.NewValue(tftypes.Bool, true),
+	})
+	r := &workspaceResource{NameRules: tectonclient.NameRules{
+		WorkspaceName: tectonclient.NameRule{Pattern: "[a-z0-9-]+", MaxLength: 40},
+	}}
+
+	resp := fwresource.ModifyPlanResponse{}
+	r.ModifyPlan(context.Background(), fwresource.ModifyPlanRequest{
+		State: tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)},
+		Plan:  plan,
+	}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for a name the cluster rejects")
+	}
+	if !strings.Contains(resp.Diagnostics[0].Detail(), "[a-z0-9-]+") {
+		t.Errorf("expected the error to mention the pattern, got: %v", resp.Diagnostics[0].Detail())
+	}
+}
+
+func TestWorkspaceResourceRead_usage(t *testing.T) {
+	fakeTectonCluster(t, `{"workspaces": {"prod": {"live": true, "feature_services": ["fraud"]}}}`)
+	ctx := context.Background()
+	r := &workspaceResource{CLI: tectonclient.Client{}}
+	plan := resourcePlan(t, r, map[string]tftypes.Value{
+		"id":   tftypes.NewValue(tftypes.String, "prod"),
+		"name": tftypes.NewValue(tftypes.String, "prod"),
+		"live": tftypes.NewValue(tftypes.Bool, true),
+	})
+	state := tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}
+	resp := fwresource.ReadResponse{State: state}
+	r.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+	var model workspaceResourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &model)...)
+	if !model.IsEmpty.IsNull() || !model.HasLiveServing.IsNull() {
+		t.Errorf("expected the contents not to be read unless refresh_contents is set, got is_empty = %v, has_live_serving = %v", model.IsEmpty, model.HasLiveServing)
+	}
+
+	plan = resourcePlan(t, r, map[string]tftypes.Value{
+		"id":               tftypes.NewValue(tftypes.String, "prod"),
+		"name":             tftypes.NewValue(tftypes.String, "prod"),
+		"live":             tftypes.NewValue(tftypes.Bool, true),
+		"refresh_contents": tftypes.NewValue(tftypes.Bool, true),
+	})
+	state = tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}
+	resp = fwresource.ReadResponse{State: state}
+	r.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+	resp.Diagnostics.Append(resp.State.Get(ctx, &model)...)
+	if model.IsEmpty.ValueBool() || !model.HasLiveServing.ValueBool() {
+		t.Errorf("expected a non-empty workspace with live serving, got is_empty = %v, has_live_serving = %v", model.IsEmpty, model.HasLiveServing)
+	}
+}
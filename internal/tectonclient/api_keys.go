@@ -0,0 +1,49 @@
+package tectonclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+)
+
+// APIKey is a Tecton API key, from the output of the `api_key.py` script.
+type APIKey struct {
+	ID string `json:"id" required:"true"`
+	// The secret key. Never log it.
+	Key string `json:"key" required:"true"`
+}
+
+// Creates a new API key with the given description. The key is only returned once, when it's
+// created.
+func (c Client) CreateAPIKey(ctx context.Context, description string) (APIKey, error) {
+	tflog.Info(ctx, fmt.Sprintf("Creating API key '%v'", description))
+	output, err := c.RunScript(ctx, "api_key.py", "create", description)
+	if err != nil {
+		return APIKey{}, err
+	}
+	var key APIKey
+	err = parseJSON("api_key.py", output, &key)
+	if err != nil {
+		// The output contains the secret key, so it's left out of the error
+		var parseErr *diagnostics.ParseError
+		var schemaErr *diagnostics.SchemaError
+		if errors.As(err, &parseErr) {
+			parseErr.Output = "<redacted>"
+		} else if errors.As(err, &schemaErr) {
+			schemaErr.Output = "<redacted>"
+		}
+		return APIKey{}, err
+	}
+	tflog.Info(ctx, fmt.Sprintf("Created API key with ID '%v'", key.ID))
+	return key, nil
+}
+
+// Deletes an API key, which revokes it immediately.
+func (c Client) DeleteAPIKey(ctx context.Context, id string) error {
+	tflog.Info(ctx, fmt.Sprintf("Deleting API key with ID '%v'", id))
+	_, err := c.RunScript(ctx, "api_key.py", "delete", id)
+	return err
+}
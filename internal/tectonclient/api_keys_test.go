@@ -0,0 +1,29 @@
+package tectonclient
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"id": "abc", "key": "secret"}`)
+	key, err := Client{}.CreateAPIKey(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key.ID != "abc" || key.Key != "secret" {
+		t.Errorf("unexpected key: %+v", key)
+	}
+}
+
+func TestCreateAPIKey_parseErrorRedactsKey(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"id": "abc", "key": "secret"`)
+	_, err := Client{}.CreateAPIKey(context.Background(), "test")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("expected the key to be redacted, got: %v", err)
+	}
+}
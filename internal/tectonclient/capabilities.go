@@ -0,0 +1,99 @@
+package tectonclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+)
+
+// Capabilities are the optional features supported by the installed tecton CLI and the cluster it
+// talks to. They are detected once when the provider is configured.
+type Capabilities struct {
+	// `tecton access-control get-roles --json-out`, which access policies are read with.
+	JSONOutput bool `json:"json_output"`
+	// Principal groups.
+	Groups bool `json:"groups"`
+	// `tecton secrets`.
+	Secrets bool `json:"secrets"`
+	// `tecton server-group`.
+	ServerGroups bool `json:"server_groups"`
+	// `tecton environment`, i.e. custom Python environments.
+	Environments bool `json:"environments"`
+	// The internal modules of the Tecton SDK that some scripts use, i.e. `tecton._internals` and the
+	// generated `tecton_proto` modules.
+	InternalAPI bool `json:"internal_api"`
+	// The version of the installed Tecton SDK, or "" if it's unknown.
+	Version string `json:"version"`
+}
+
+// Detects the capabilities of the tecton CLI. Returns nil if they couldn't be detected, in which
+// case every feature is assumed to be supported and unsupported ones fail with the CLI's own error.
+func DetectCapabilities(ctx context.Context, cli Client) *Capabilities {
+	output, err := cli.RunScript(ctx, "capabilities.py")
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Failed to detect the capabilities of the tecton CLI, so all features are assumed to be supported: %v", err.Error()))
+		return nil
+	}
+	var capabilities Capabilities
+	err = json.Unmarshal(output, &capabilities)
+	if err != nil {
+		err = &diagnostics.ParseError{Source: "capabilities.py", Output: string(output), Err: err}
+		tflog.Warn(ctx, fmt.Sprintf("Failed to detect the capabilities of the tecton CLI, so all features are assumed to be supported: %v", err.Error()))
+		return nil
+	}
+	tflog.Info(ctx, fmt.Sprintf("Detected tecton CLI capabilities: %+v", capabilities))
+	return &capabilities
+}
+
+// Returns an error if the client's capabilities were detected and supported returns false for them.
+// feature describes the feature in the error, e.g. "Reading roles as JSON".
+func (c Client) RequireCapability(supported func(Capabilities) bool, feature string) error {
+	if c.Capabilities == nil || supported(*c.Capabilities) {
+		return nil
+	}
+	return fmt.Errorf(
+		"%v is not supported on your cluster version. The installed tecton CLI doesn't support it; the provider is tested against tecton %v.",
+		feature,
+		SupportedTectonVersion,
+	)
+}
+
+// Matches the imports of the internal modules of the Tecton SDK in a script.
+var internalAPIImportRegex = regexp.MustCompile(`(?m)^(from|import) (tecton\._internals|tecton_proto)\b`)
+
+// Returns an error if the client's capabilities were detected and the script with the given source
+// imports internal modules of the Tecton SDK that the installed SDK doesn't provide, or that may have
+// changed because it isn't the minor version the provider is tested against. The modules aren't a
+// public API and change between releases without notice, so the script could otherwise fail with a
+// cryptic error or misread a changed response.
+func (c Client) requireInternalAPI(script string, source []byte) error {
+	if c.Capabilities == nil || !internalAPIImportRegex.Match(source) {
+		return nil
+	}
+	version := c.Capabilities.Version
+	if c.Capabilities.InternalAPI && (version == "" || minorVersion(version) == minorVersion(SupportedTectonVersion)) {
+		return nil
+	}
+	if version == "" {
+		version = "unknown"
+	}
+	return fmt.Errorf(
+		"'%v' reads data that the tecton CLI doesn't expose through internal modules of the Tecton SDK, which change between releases without notice, "+
+			"so it's only supported with tecton %v.x. The installed version is %v. Install tecton==%v to use this feature.",
+		script,
+		minorVersion(SupportedTectonVersion),
+		version,
+		SupportedTectonVersion,
+	)
+}
+
+// Returns the major and minor version of version, e.g. "0.7" for "0.7.3".
+func minorVersion(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	return strings.Join(parts[:min(len(parts), 2)], ".")
+}
@@ -0,0 +1,92 @@
+package tectonclient
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDetectCapabilities(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"json_output": true, "groups": false, "secrets": true, "server_groups": false}`)
+	capabilities := DetectCapabilities(context.Background(), Client{})
+	expected := Capabilities{JSONOutput: true, Secrets: true}
+	if capabilities == nil || *capabilities != expected {
+		t.Errorf("expected %+v, got %+v", expected, capabilities)
+	}
+
+	fakeTectonPythonCLI(t, "not json")
+	if capabilities := DetectCapabilities(context.Background(), Client{}); capabilities != nil {
+		t.Errorf("expected no capabilities for unparseable output, got %+v", capabilities)
+	}
+}
+
+func TestRequireCapability(t *testing.T) {
+	secrets := func(c Capabilities) bool { return c.Secrets }
+	testCases := map[string]struct {
+		capabilities *Capabilities
+		isError      bool
+	}{
+		"not detected": {capabilities: nil},
+		"supported":    {capabilities: &Capabilities{Secrets: true}},
+		"unsupported":  {capabilities: &Capabilities{}, isError: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := Client{Capabilities: testCase.capabilities}.RequireCapability(secrets, "Secrets")
+			if (err != nil) != testCase.isError {
+				t.Fatalf("expected error: %v, got: %v", testCase.isError, err)
+			}
+			if err != nil && !strings.Contains(err.Error(), "not supported on your cluster version") {
+				t.Errorf("expected an unsupported feature error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestRequireInternalAPI(t *testing.T) {
+	internal := []byte("import json\n\nfrom tecton._internals import metadata_service\n")
+	public := []byte("import json\n\nimport tecton\n")
+	testCases := map[string]struct {
+		capabilities *Capabilities
+		source       []byte
+		isError      bool
+	}{
+		"not detected":          {capabilities: nil, source: internal},
+		"supported":             {capabilities: &Capabilities{InternalAPI: true, Version: "0.7.0"}, source: internal},
+		"unknown version":       {capabilities: &Capabilities{InternalAPI: true}, source: internal},
+		"missing modules":       {capabilities: &Capabilities{Version: "0.7.3"}, source: internal, isError: true},
+		"other minor version":   {capabilities: &Capabilities{InternalAPI: true, Version: "0.8.1"}, source: internal, isError: true},
+		"public API only":       {capabilities: &Capabilities{Version: "0.8.1"}, source: public},
+		"indented import":       {capabilities: &Capabilities{Version: "0.8.1"}, source: []byte("try:\n    from tecton_proto.auth import principal_pb2\n")},
+		"prefixed module names": {capabilities: &Capabilities{Version: "0.8.1"}, source: []byte("from tecton_protocol import x\n")},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := Client{Capabilities: testCase.capabilities}.requireInternalAPI("group.py", testCase.source)
+			if (err != nil) != testCase.isError {
+				t.Fatalf("expected error: %v, got: %v", testCase.isError, err)
+			}
+			if err != nil && !strings.Contains(err.Error(), "only supported with tecton 0.7.x") {
+				t.Errorf("expected an unsupported version error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestRunScript_internalAPIUnsupported(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"id": "group1"}`)
+	client := Client{Capabilities: &Capabilities{InternalAPI: true, Version: "0.9.0"}}
+	_, err := client.RunScript(context.Background(), "group.py", "group1")
+	if err == nil || !strings.Contains(err.Error(), "The installed version is 0.9.0") {
+		t.Errorf("expected an unsupported version error, got: %v", err)
+	}
+}
+
+func TestGetRoles_unsupported(t *testing.T) {
+	fakeTectonCLI(t, "echo '[]'")
+	client := Client{Capabilities: &Capabilities{}}
+	_, err := client.GetRoles(context.Background(), Principal{UserID: "alice@example.com"})
+	if err == nil || !strings.Contains(err.Error(), "not supported on your cluster version") {
+		t.Errorf("expected an unsupported feature error, got: %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package tectonclient
+
+import "sync"
+
+// ChangeRecorder collects the changes a single resource operation made in Tecton, so that they can
+// be reported once the operation finishes. It's safe for concurrent use.
+type ChangeRecorder struct {
+	mu      sync.Mutex
+	changes []string
+}
+
+// Records a change that was made in Tecton. Does nothing if the recorder is nil.
+func (r *ChangeRecorder) Record(change string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes = append(r.changes, change)
+}
+
+// Returns the changes recorded so far.
+func (r *ChangeRecorder) Changes() []string {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.changes...)
+}
@@ -0,0 +1,298 @@
+// Package tectonclient runs `tecton` CLI commands and the provider's embedded Tecton SDK scripts,
+// and wraps the commands the provider uses in typed methods, so that every resource shares the
+// same parsing, logging, retries and error messages.
+package tectonclient
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+)
+
+// The tecton CLI version the provider is developed and tested against.
+const SupportedTectonVersion = "0.7.3"
+
+// How long to wait for a timed out command's output to be closed after the command is killed, e.g.
+// if it started child processes that inherited its stdout.
+const commandWaitDelay = 5 * time.Second
+
+// The maximum number of bytes of command output that are included in command logs.
+const commandLogOutputLimit = 2000
+
+// The ID of the objects that methods of a client in dry-run mode return as created, since nothing is.
+const DryRunID = "dry-run"
+
+// Environment variables whose values are replaced with "<redacted>" in command logs.
+var secretEnvRegex = regexp.MustCompile(`(?i)(KEY|TOKEN|SECRET|PASSWORD)`)
+
+// The module that makes Python skip TLS certificate verification, see InsecureTLSEnv.
+//
+//go:embed python/sitecustomize.py
+var insecureSiteCustomize []byte
+
+// Returns the environment variables that make the tecton CLI and the provider's scripts skip TLS
+// certificate verification, for development clusters with self-signed certificates. Python has no
+// environment variable for that, so a sitecustomize module that disables verification in the standard
+// library and in requests is written to dir, which is prepended to the PYTHONPATH in env. CA bundles
+// in env are cleared, since the scripts that call Tecton's HTTP API verify certificates with them
+// explicitly. dir is created if it doesn't exist.
+func InsecureTLSEnv(env []string, dir string) ([]string, error) {
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create '%v': %w", dir, err)
+	}
+	// Other provider instances may be starting Python with the module at the same time, so it's
+	// replaced atomically
+	file, err := os.CreateTemp(dir, "sitecustomize-*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	_, err = file.Write(insecureSiteCustomize)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filepath.Join(dir, "sitecustomize.py"))
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return nil, fmt.Errorf("Failed to write the sitecustomize module to '%v': %w", dir, err)
+	}
+
+	pythonPath := dir
+	for _, kv := range env {
+		if value, ok := strings.CutPrefix(kv, "PYTHONPATH="); ok {
+			pythonPath = dir
+			if value != "" {
+				pythonPath += string(os.PathListSeparator) + value
+			}
+		}
+	}
+	return []string{"PYTHONPATH=" + pythonPath, "REQUESTS_CA_BUNDLE=", "SSL_CERT_FILE="}, nil
+}
+
+// Returns the environment variables that make the tecton CLI's Python HTTP clients connect through
+// the proxy at httpsProxy and verify TLS certificates with the CA bundle at caBundlePath instead of
+// the default one. Either may be empty to keep the default. requests reads HTTPS_PROXY and
+// REQUESTS_CA_BUNDLE, and the scripts that call Tecton's HTTP API with urllib pass them explicitly.
+func NetworkEnv(httpsProxy string, caBundlePath string) []string {
+	var env []string
+	if httpsProxy != "" {
+		env = append(env, "HTTPS_PROXY="+httpsProxy, "https_proxy="+httpsProxy)
+	}
+	if caBundlePath != "" {
+		env = append(env, "REQUESTS_CA_BUNDLE="+caBundlePath, "SSL_CERT_FILE="+caBundlePath)
+	}
+	return env
+}
+
+// Client runs `tecton` commands against the Tecton instance configured in the provider.
+type Client struct {
+	// The environment every command is run with, including the credentials and API URL.
+	Env []string
+	// If true, every command is logged at DEBUG level with its arguments, environment and output.
+	LogCommands bool
+	// The directory commands are run in, e.g. a feature repo. Defaults to the provider's working
+	// directory.
+	Dir string
+	// The maximum time a single command may run before it's killed. Zero means no limit.
+	Timeout time.Duration
+	// If set, the changes made in Tecton are recorded here for the notification webhook.
+	Changes *ChangeRecorder
+	// The optional features the CLI supports, or nil if they weren't detected.
+	Capabilities *Capabilities
+	// How commands that were rejected because another plan or apply is in progress are retried. The
+	// zero value doesn't retry them.
+	LockRetry LockRetry
+	// How commands that failed with a retriable error, e.g. a network error, are retried. Defaults to
+	// DefaultCommandRetry if nil.
+	Retry *CommandRetry
+	// The maximum number of bytes of a failed command's output that are included in its error. Longer
+	// output is truncated and written to a file in full. Zero means no limit.
+	OutputLimit int
+	// If set, the deprecation and compatibility warnings that commands print are recorded here.
+	Warnings *WarningRecorder
+	// If set, the commands run by each resource operation are counted and timed here.
+	Metrics *MetricsRecorder
+	// If set, the OAuth access token the CLI authenticates with is refreshed before commands are run
+	// once it's about to expire.
+	Session *OAuthSession
+	// If set, limits how many commands run at once across every client that shares the limiter.
+	Limiter *CommandLimiter
+	// If true, the methods that change Tecton log the commands they would run instead of running them,
+	// and succeed. Commands that only read from Tecton are still run.
+	DryRun bool
+	// The secrets of the provider instance, e.g. its API key, which are redacted from the errors and
+	// logs of commands. If nil, only values assigned to TECTON_API_KEY are redacted.
+	Redactor *diagnostics.Redactor
+}
+
+// Runs `tecton` with the given arguments and returns its combined stdout and stderr. Failures that
+// look transient, e.g. network errors, are retried as configured by Retry, and commands that were
+// rejected because another plan or apply holds the workspace's lock are retried as configured by
+// LockRetry. Commands are run without a terminal and with the flags that skip confirmation prompts,
+// and a command that prompts anyway fails instead of waiting for an answer.
+func (c Client) Run(ctx context.Context, args ...string) ([]byte, error) {
+	args = nonInteractiveArgs(args)
+	return c.retry(ctx, "tecton "+ShellJoin(args), func() ([]byte, error) {
+		cmdCtx, cancel := c.commandContext(ctx)
+		cmd := exec.CommandContext(cmdCtx, "tecton", args...)
+		cmd.Env = c.commandEnv(ctx)
+		cmd.Dir = c.Dir
+		cmd.WaitDelay = commandWaitDelay
+		start := time.Now()
+		output, err := runNonInteractive(cmd)
+		c.Metrics.recordCommand(ctx, time.Since(start))
+		err = c.timeoutError(cmdCtx, err)
+		cancel()
+		if c.LogCommands {
+			c.logCommand(ctx, cmd.Env, args, output, err)
+		}
+		c.recordWarnings(ctx, "tecton "+ShellJoin(args), output)
+		return output, err
+	})
+}
+
+// Returns true if the client is in dry-run mode, after logging command, which changes Tecton, as the
+// command that would have been run. Callers must then skip command and succeed.
+func (c Client) SkipInDryRun(ctx context.Context, command string) bool {
+	if !c.DryRun {
+		return false
+	}
+	tflog.Info(ctx, fmt.Sprintf("Dry run: not running '%v'", c.Redactor.Redact(command)), map[string]interface{}{"dry_run": true})
+	return true
+}
+
+// Returns a copy of the client whose commands are killed after timeout. Zero means no limit.
+func (c Client) WithTimeout(timeout time.Duration) Client {
+	c.Timeout = timeout
+	return c
+}
+
+// Returns a copy of the client that records the changes it makes in a new recorder, along with the
+// recorder.
+func (c Client) RecordingChanges() (Client, *ChangeRecorder) {
+	c.Changes = &ChangeRecorder{}
+	return c, c.Changes
+}
+
+// Returns the context a single command is run with, which is cancelled after the client's timeout.
+func (c Client) commandContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, c.Timeout, fmt.Errorf("%w after %v", diagnostics.ErrTimedOut, c.Timeout))
+}
+
+// Replaces the error of a command that was killed because it ran longer than the client's timeout, or
+// because the deadline of ctx passed, e.g. a resource's `timeouts`, with the cause of the deadline.
+// The replacement isn't an *exec.ExitError, so timed out commands aren't retried.
+func (c Client) timeoutError(cmdCtx context.Context, err error) error {
+	if err != nil && cmdCtx.Err() == context.DeadlineExceeded {
+		if cause := context.Cause(cmdCtx); errors.Is(cause, diagnostics.ErrTimedOut) {
+			return cause
+		}
+		return fmt.Errorf("%w after %v", diagnostics.ErrTimedOut, c.Timeout)
+	}
+	return err
+}
+
+// Returns the environment a command is run with, which includes the non-interactive settings and
+// the correlation ID of the operation ctx belongs to, if any.
+func (c Client) commandEnv(ctx context.Context) []string {
+	env := c.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	env = append(slices.Clone(env), nonInteractiveEnv...)
+	if id := CorrelationID(ctx); id != "" {
+		env = append(env, CorrelationIDEnv+"="+id)
+	}
+	return env
+}
+
+// Returns true if commands are run with a non-empty TECTON_API_KEY. Scripts that call Tecton's HTTP
+// API directly instead of through the SDK can only authenticate with an API key, not with the
+// session of `tecton login` or an OAuth access token.
+func (c Client) HasAPIKey() bool {
+	env := c.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	apiKey := ""
+	for _, kv := range env {
+		if value, ok := strings.CutPrefix(kv, "TECTON_API_KEY="); ok {
+			apiKey = value
+		}
+	}
+	return apiKey != ""
+}
+
+// Logs a command in a form that can be copy-pasted to reproduce it, with secrets redacted from it and
+// its output.
+func (c Client) logCommand(ctx context.Context, env []string, args []string, output []byte, err error) {
+	env = redactEnv(tectonEnv(env))
+	exitCode := 0
+	if cmd, ok := err.(*exec.ExitError); ok {
+		exitCode = cmd.ExitCode()
+	} else if err != nil {
+		exitCode = -1
+	}
+	snippet := c.Redactor.Redact(string(output))
+	if len(snippet) > commandLogOutputLimit {
+		snippet = snippet[:commandLogOutputLimit] + "... (truncated)"
+	}
+	tflog.Debug(ctx, "Ran tecton command", map[string]interface{}{
+		"command":   c.Redactor.Redact(fmt.Sprintf("%v tecton %v", strings.Join(env, " "), ShellJoin(args))),
+		"exit_code": exitCode,
+		"output":    snippet,
+	})
+}
+
+// Returns only the environment variables that affect the tecton CLI, i.e. the ones that are
+// needed to reproduce a command.
+func tectonEnv(env []string) []string {
+	var filtered []string
+	for _, kv := range env {
+		if strings.HasPrefix(kv, "TECTON_") || strings.HasPrefix(kv, "API_SERVICE=") {
+			filtered = append(filtered, kv)
+		}
+	}
+	return filtered
+}
+
+// Replaces the values of secret-looking environment variables with "<redacted>".
+func redactEnv(env []string) []string {
+	redacted := make([]string, 0, len(env))
+	for _, kv := range env {
+		key, _, found := strings.Cut(kv, "=")
+		if found && secretEnvRegex.MatchString(key) {
+			kv = key + "=<redacted>"
+		}
+		redacted = append(redacted, kv)
+	}
+	return redacted
+}
+
+// ShellJoin joins arguments into a single shell-safe string, quoting arguments where needed.
+func ShellJoin(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\$`|&;<>()*?[]{}~#!") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		quoted = append(quoted, arg)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,298 @@
+package tectonclient
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+)
+
+// Installs a fake `tecton` executable on the PATH for the duration of the test. The executable is a
+// shell script with the given body, which can inspect its arguments via "$@".
+func fakeTectonCLI(t *testing.T, body string) {
+	t.Helper()
+	dir := t.TempDir()
+	script := "#!/bin/sh\n" + body + "\n"
+	err := os.WriteFile(filepath.Join(dir, "tecton"), []byte(script), 0o755)
+	if err != nil {
+		t.Fatalf("failed to write fake tecton CLI: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// Installs a fake `tecton` entrypoint whose Python interpreter prints scriptOutput when running an
+// embedded script.
+func fakeTectonPythonCLI(t *testing.T, scriptOutput string) {
+	t.Helper()
+	dir := t.TempDir()
+	interpreter := filepath.Join(dir, "python")
+	err := os.WriteFile(interpreter, []byte("#!/bin/sh\necho '"+scriptOutput+"'\n"), 0o755)
+	if err != nil {
+		t.Fatalf("failed to write fake python: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "tecton"), []byte("#!"+interpreter+"\n"), 0o755)
+	if err != nil {
+		t.Fatalf("failed to write fake tecton CLI: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// Installs a fake `tecton` entrypoint whose Python interpreter is the real python3, so that the
+// embedded scripts actually run. Skips the test if python3 isn't installed.
+func fakeTectonRealPythonCLI(t *testing.T) {
+	t.Helper()
+	interpreter, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 is required to run the embedded scripts")
+	}
+	dir := t.TempDir()
+	err = os.WriteFile(filepath.Join(dir, "tecton"), []byte("#!"+interpreter+"\n"), 0o755)
+	if err != nil {
+		t.Fatalf("failed to write fake tecton CLI: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// Returns a fake feature server with a self-signed certificate, whose metadata endpoint describes a
+// feature service with a single join key.
+func fakeFeatureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/feature-service/metadata" || r.Header.Get("Authorization") != "Tecton-key abc" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"inputJoinKeys": [{"name": "user_id", "dataType": {"type": "string"}}]}`))
+	}))
+	// Rejected handshakes are expected
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestInsecureTLSEnv(t *testing.T) {
+	fakeTectonRealPythonCLI(t)
+	server := fakeFeatureServer(t)
+	env := append(os.Environ(), "API_SERVICE="+server.URL+"/api", "TECTON_API_KEY=abc", "PYTHONPATH=/opt/site")
+	cli := Client{Env: env, Retry: &CommandRetry{MaxAttempts: 1}}
+
+	_, err := cli.RunScript(context.Background(), "feature_service_schema.py", "prod", "fraud")
+	if err == nil || !strings.Contains(err.Error(), "CERTIFICATE_VERIFY_FAILED") {
+		t.Fatalf("expected the self-signed certificate to be rejected, got: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "insecure-tls")
+	insecureEnv, err := InsecureTLSEnv(env, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "PYTHONPATH=" + dir + string(os.PathListSeparator) + "/opt/site"
+	if !slices.Equal(insecureEnv, []string{expected, "REQUESTS_CA_BUNDLE=", "SSL_CERT_FILE="}) {
+		t.Errorf("expected %v, got %v", expected, insecureEnv)
+	}
+	// A CA bundle in the environment doesn't turn verification back on
+	cli.Env = append(append(env, "REQUESTS_CA_BUNDLE=/nonexistent/ca.pem"), insecureEnv...)
+	output, err := cli.RunScript(context.Background(), "feature_service_schema.py", "prod", "fraud")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), `"name": "user_id"`) {
+		t.Errorf("expected the feature service's join key, got: %v", string(output))
+	}
+}
+
+func TestNetworkEnv(t *testing.T) {
+	env := NetworkEnv("http://proxy:3128", "/etc/ca.pem")
+	expected := "HTTPS_PROXY=http://proxy:3128 https_proxy=http://proxy:3128 REQUESTS_CA_BUNDLE=/etc/ca.pem SSL_CERT_FILE=/etc/ca.pem"
+	if strings.Join(env, " ") != expected {
+		t.Errorf("expected '%v', got '%v'", expected, strings.Join(env, " "))
+	}
+	if env := NetworkEnv("", ""); len(env) != 0 {
+		t.Errorf("expected no variables, got %v", env)
+	}
+}
+
+func TestHasAPIKey(t *testing.T) {
+	testCases := map[string]struct {
+		env      []string
+		expected bool
+	}{
+		"api key":        {env: []string{"TECTON_API_KEY=abc"}, expected: true},
+		"cleared":        {env: []string{"TECTON_API_KEY=abc", "TECTON_API_KEY="}, expected: false},
+		"no api key":     {env: []string{"API_SERVICE=https://example.tecton.ai/api"}, expected: false},
+		"prefixed names": {env: []string{"MY_TECTON_API_KEY=abc"}, expected: false},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if actual := (Client{Env: testCase.env}).HasAPIKey(); actual != testCase.expected {
+				t.Errorf("expected %v, got %v", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+// The feature server's certificate is only trusted through the CA bundle, and it's only reachable
+// through the proxy, since the scripts that call it use urllib rather than the SDK's requests.
+func TestNetworkEnv_scripts(t *testing.T) {
+	fakeTectonRealPythonCLI(t)
+	server := fakeFeatureServer(t)
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(caBundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write CA bundle: %v", err)
+	}
+	var proxied atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		proxied.Add(1)
+		upstream, err := net.Dial("tcp", server.Listener.Addr().String())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer upstream.Close()
+		w.WriteHeader(http.StatusOK)
+		conn, buffered, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		go io.Copy(upstream, buffered)
+		io.Copy(conn, upstream)
+	}))
+	t.Cleanup(proxy.Close)
+	for _, name := range []string{"NO_PROXY", "no_proxy", "HTTPS_PROXY", "https_proxy", "SSL_CERT_FILE", "REQUESTS_CA_BUNDLE"} {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+
+	// The test certificate is valid for example.com, which only the proxy routes to the feature server
+	env := append(os.Environ(), "API_SERVICE=https://example.com/api", "TECTON_API_KEY=abc")
+	env = append(env, NetworkEnv(proxy.URL, caBundle)...)
+	cli := Client{Env: env, Retry: &CommandRetry{MaxAttempts: 1}}
+	output, err := cli.RunScript(context.Background(), "feature_service_schema.py", "prod", "fraud")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(output), `"name": "user_id"`) || proxied.Load() != 1 {
+		t.Errorf("expected the feature service's join key through the proxy, got %v requests and: %v", proxied.Load(), string(output))
+	}
+}
+
+func TestRedactEnv(t *testing.T) {
+	env := redactEnv(tectonEnv([]string{
+		"HOME=/root",
+		"TECTON_API_KEY=abc123",
+		"API_SERVICE=https://yourcluster.tecton.ai/api",
+	}))
+	expected := "TECTON_API_KEY=<redacted> API_SERVICE=https://yourcluster.tecton.ai/api"
+	if strings.Join(env, " ") != expected {
+		t.Errorf("expected '%v', got '%v'", expected, strings.Join(env, " "))
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	testCases := map[string]struct {
+		args     []string
+		expected string
+	}{
+		"plain": {
+			args:     []string{"workspace", "create", "my-ws", "--live"},
+			expected: "workspace create my-ws --live",
+		},
+		"spaces": {
+			args:     []string{"workspace", "create", "my ws"},
+			expected: "workspace create 'my ws'",
+		},
+		"quotes": {
+			args:     []string{"it's"},
+			expected: `'it'\''s'`,
+		},
+		"empty": {
+			args:     []string{""},
+			expected: "''",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := ShellJoin(testCase.args)
+			if actual != testCase.expected {
+				t.Errorf("expected '%v', got '%v'", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	fakeTectonCLI(t, "exec sleep 5")
+	start := time.Now()
+	_, err := Client{Timeout: 100 * time.Millisecond}.Run(context.Background(), "workspace", "list")
+	if err == nil || err.Error() != "Command timed out after 100ms" {
+		t.Fatalf("expected a timeout error, got: %v", err)
+	}
+	if time.Since(start) > 4*time.Second {
+		t.Errorf("expected the command to be killed after the timeout, took %v", time.Since(start))
+	}
+}
+
+func TestNonInteractiveArgs(t *testing.T) {
+	testCases := map[string]struct {
+		args     []string
+		expected []string
+	}{
+		"apply":            {args: []string{"apply", "--workspace", "prod"}, expected: []string{"apply", "--workspace", "prod", "--yes"}},
+		"already has flag": {args: []string{"apply", "--yes"}, expected: []string{"apply", "--yes"}},
+		"workspace delete": {args: []string{"workspace", "delete", "prod"}, expected: []string{"workspace", "delete", "prod", "--yes"}},
+		"workspace list":   {args: []string{"workspace", "list"}, expected: []string{"workspace", "list"}},
+		"empty":            {args: nil, expected: nil},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := nonInteractiveArgs(testCase.args)
+			if !slices.Equal(actual, testCase.expected) {
+				t.Errorf("expected '%v', got '%v'", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRunWaitingForInput(t *testing.T) {
+	// A click confirmation that read the end of stdin
+	fakeTectonCLI(t, "printf 'Are you sure? [y/N]: \\nAborted!\\n'; exit 1")
+	_, err := Client{Timeout: time.Minute}.Run(context.Background(), "login")
+	if !errors.Is(err, diagnostics.ErrWaitingForInput) {
+		t.Fatalf("expected a waiting for input error, got: %v", err)
+	}
+}
+
+func TestRunQuietCommand(t *testing.T) {
+	// A long apply prints a line that looks like a prompt and then nothing for a while, which mustn't
+	// be mistaken for waiting for input
+	fakeTectonCLI(t, "printf 'Applying changes:'; sleep 1; echo ' done'; [ \"$PYTHONUNBUFFERED\" = 1 ]")
+	output, err := Client{}.Run(context.Background(), "apply")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(output) != "Applying changes: done\n" {
+		t.Errorf("unexpected output: %q", output)
+	}
+}
@@ -0,0 +1,131 @@
+package tectonclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Returns the environment variables that make the tecton CLI keep its configuration, e.g. the session
+// of `tecton login` and the selected workspace, in dir instead of the user's home directory, so that
+// the provider neither changes nor depends on the operator's own CLI state. dir is created if it
+// doesn't exist. env is the environment the CLI would otherwise run with.
+//
+// The CLI finds its configuration through the home directory, so HOME (and USERPROFILE on Windows) is
+// replaced. Python finds packages installed with `pip install --user` through the home directory too,
+// so the user base is pinned to the one of the real home directory.
+func IsolatedConfigEnv(ctx context.Context, env []string, dir string) ([]string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid directory '%v': %w", dir, err)
+	}
+	err = os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create the tecton CLI configuration directory: %w", err)
+	}
+	isolated := []string{"HOME=" + dir, "USERPROFILE=" + dir}
+
+	for _, kv := range env {
+		if strings.HasPrefix(kv, "PYTHONUSERBASE=") {
+			return isolated, nil
+		}
+	}
+	userBase, err := pythonUserBase(env)
+	if err != nil {
+		// The CLI may still work, e.g. if it isn't installed in the user base
+		tflog.Warn(ctx, fmt.Sprintf("Failed to find the Python user base, so packages installed with `pip install --user` may not be found: %v", err.Error()))
+		return isolated, nil
+	}
+	return append(isolated, "PYTHONUSERBASE="+userBase), nil
+}
+
+// Returns the directory that the tecton CLI's Python interpreter installs `pip install --user`
+// packages into when run with env.
+func pythonUserBase(env []string) (string, error) {
+	interpreter, err := tectonPythonInterpreter()
+	if err != nil {
+		return "", err
+	}
+	cmd := exec.Command(interpreter, "-c", "import site; print(site.getuserbase())")
+	cmd.Env = env
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Failed to run '%v': %w", interpreter, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Writes an OAuth access token to the tecton CLI's configuration in dir, which must be the home
+// directory the CLI runs with, e.g. from IsolatedConfigEnv, the same way `tecton login` stores its
+// session. expiresAt may be nil if the token's expiration isn't known. The file is only readable by
+// the current user, since it holds the token.
+func WriteOAuthSession(dir string, token string, expiresAt *time.Time) error {
+	session := map[string]interface{}{"OAUTH_ACCESS_TOKEN": token}
+	if expiresAt != nil {
+		session["OAUTH_ACCESS_TOKEN_EXPIRATION"] = expiresAt.Unix()
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	configDir := filepath.Join(dir, ".tecton")
+	err = os.MkdirAll(configDir, 0o700)
+	if err != nil {
+		return fmt.Errorf("Failed to create the tecton CLI configuration directory: %w", err)
+	}
+	err = os.WriteFile(filepath.Join(configDir, "config.tokens"), data, 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed to write the OAuth session: %w", err)
+	}
+	return nil
+}
+
+// How long before its access token expires an OAuthSession refreshes it, so that the token doesn't
+// expire while a command runs.
+const oauthRefreshMargin = 10 * time.Minute
+
+// OAuthSession keeps an OAuth access token in the tecton CLI's configuration fresh, e.g. an Okta
+// session, by refreshing it before commands are run once it's about to expire. It's safe for
+// concurrent use.
+type OAuthSession struct {
+	// The home directory the CLI runs with, which the token is written to.
+	Dir string
+	// Returns a new access token and when it expires.
+	FetchToken func(ctx context.Context) (string, time.Time, error)
+
+	mu sync.Mutex
+	// When the token in the CLI's configuration expires, or zero if none was written yet.
+	expiresAt time.Time
+}
+
+// Refreshes the access token if it expires within oauthRefreshMargin, or if none was written yet.
+// Does nothing if the session is nil, e.g. for clients that authenticate with an API key.
+func (s *OAuthSession) Refresh(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Until(s.expiresAt) > oauthRefreshMargin {
+		return nil
+	}
+	token, expiresAt, err := s.FetchToken(ctx)
+	if err != nil {
+		return err
+	}
+	err = WriteOAuthSession(s.Dir, token, &expiresAt)
+	if err != nil {
+		return err
+	}
+	tflog.Info(ctx, fmt.Sprintf("Refreshed the OAuth access token, which now expires at %v", expiresAt.Format(time.RFC3339)))
+	s.expiresAt = expiresAt
+	return nil
+}
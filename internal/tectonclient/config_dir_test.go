@@ -0,0 +1,116 @@
+package tectonclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsolatedConfigEnv(t *testing.T) {
+	fakeTectonPythonCLI(t, "/home/me/.local")
+	dir := filepath.Join(t.TempDir(), "tecton")
+	env, err := IsolatedConfigEnv(context.Background(), []string{"HOME=/home/me"}, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"HOME=" + dir, "USERPROFILE=" + dir, "PYTHONUSERBASE=/home/me/.local"}
+	if !reflect.DeepEqual(env, expected) {
+		t.Errorf("expected %q, got %q", expected, env)
+	}
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() || info.Mode().Perm() != 0o700 {
+		t.Errorf("expected a private directory to be created, got: %v, %v", info, err)
+	}
+
+	// A user base that's already set is kept
+	env, err = IsolatedConfigEnv(context.Background(), []string{"PYTHONUSERBASE=/opt/python"}, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"HOME=" + dir, "USERPROFILE=" + dir}; !reflect.DeepEqual(env, expected) {
+		t.Errorf("expected %q, got %q", expected, env)
+	}
+}
+
+func TestWriteOAuthSession(t *testing.T) {
+	dir := t.TempDir()
+	expiresAt := time.Unix(1900000000, 0)
+	err := WriteOAuthSession(dir, "token123", &expiresAt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path := filepath.Join(dir, ".tecton", "config.tokens")
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected a private session file, got: %v, %v", info, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read session: %v", err)
+	}
+	var session map[string]interface{}
+	if err := json.Unmarshal(data, &session); err != nil {
+		t.Fatalf("failed to parse session: %v", err)
+	}
+	expected := map[string]interface{}{"OAUTH_ACCESS_TOKEN": "token123", "OAUTH_ACCESS_TOKEN_EXPIRATION": float64(1900000000)}
+	if !reflect.DeepEqual(session, expected) {
+		t.Errorf("expected %v, got %v", expected, session)
+	}
+}
+
+func TestOAuthSessionRefresh(t *testing.T) {
+	fakeTectonCLI(t, "echo ok")
+	dir := t.TempDir()
+	fetches := 0
+	expiresIn := time.Hour
+	session := &OAuthSession{
+		Dir: dir,
+		FetchToken: func(ctx context.Context) (string, time.Time, error) {
+			fetches++
+			return fmt.Sprintf("token%v", fetches), time.Now().Add(expiresIn), nil
+		},
+	}
+	cli := Client{Session: session}
+	for range 2 {
+		_, err := cli.Run(context.Background(), "workspace", "list")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("expected the token to be fetched once, got %v fetches", fetches)
+	}
+
+	// A token that's about to expire is refreshed before the next command
+	session.expiresAt = time.Now().Add(time.Minute)
+	expiresIn = time.Minute
+	for range 2 {
+		_, err := cli.Run(context.Background(), "workspace", "list")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if fetches != 3 {
+		t.Errorf("expected the token to be refreshed before every command, got %v fetches", fetches)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".tecton", "config.tokens"))
+	if err != nil || !strings.Contains(string(data), `"OAUTH_ACCESS_TOKEN":"token3"`) {
+		t.Errorf("expected the latest token to be written, got %s, %v", data, err)
+	}
+
+	// Commands aren't run if the session can't be refreshed
+	session.FetchToken = func(ctx context.Context) (string, time.Time, error) {
+		return "", time.Time{}, errors.New("refresh token expired")
+	}
+	_, err = cli.Run(context.Background(), "workspace", "list")
+	if err == nil || err.Error() != "refresh token expired" {
+		t.Errorf("expected the refresh error, got: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package tectonclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+)
+
+// The environment variable every command of an operation is run with, so that the requests it makes
+// can be matched against Tecton's audit logs.
+const CorrelationIDEnv = "TECTON_CORRELATION_ID"
+
+// The HTTP header the correlation ID is sent in, for APIs the provider calls directly.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+type correlationIDKey struct{}
+
+// Returns a context for a single resource operation, e.g. a Create, with a new correlation ID. The
+// ID is added to every log entry written with the context and passed to every command run with it.
+// The client's secrets are masked in the log entries, as by WithSecretsMasked.
+func (c Client) WithCorrelationID(ctx context.Context) context.Context {
+	id := newCorrelationID()
+	ctx = context.WithValue(ctx, correlationIDKey{}, id)
+	return c.WithSecretsMasked(tflog.SetField(ctx, "correlation_id", id))
+}
+
+// Returns a context whose log entries have the secrets of the client's Redactor, e.g. the provider's
+// API key, and any value assigned to TECTON_API_KEY masked.
+func (c Client) WithSecretsMasked(ctx context.Context) context.Context {
+	ctx = tflog.MaskLogRegexes(ctx, diagnostics.APIKeyAssignmentRegex)
+	if secrets := c.Redactor.Secrets(); len(secrets) > 0 {
+		ctx = tflog.MaskLogStrings(ctx, secrets...)
+	}
+	return ctx
+}
+
+// Returns the correlation ID of the operation ctx belongs to, or "" if it doesn't have one.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// Returns a random 128-bit ID as hex.
+func newCorrelationID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
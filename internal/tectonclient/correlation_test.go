@@ -0,0 +1,35 @@
+package tectonclient
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestWithCorrelationID(t *testing.T) {
+	ctx := Client{}.WithCorrelationID(context.Background())
+	id := CorrelationID(ctx)
+	if len(id) != 32 {
+		t.Errorf("expected a 32 character ID, got '%v'", id)
+	}
+	if other := CorrelationID(Client{}.WithCorrelationID(context.Background())); other == id {
+		t.Errorf("expected a new ID for every operation, got '%v' twice", id)
+	}
+	if CorrelationID(context.Background()) != "" {
+		t.Error("expected no ID for a context without one")
+	}
+}
+
+func TestRunPassesCorrelationID(t *testing.T) {
+	fakeTectonCLI(t, `echo "$TECTON_CORRELATION_ID"; exit 1`)
+	ctx := Client{}.WithCorrelationID(context.Background())
+	output, _ := Client{}.Run(ctx, "workspace", "list")
+	if strings.TrimSpace(string(output)) != CorrelationID(ctx) {
+		t.Errorf("expected the command to be run with correlation ID '%v', got '%v'", CorrelationID(ctx), string(output))
+	}
+
+	_, err := Client{}.ListWorkspaces(ctx)
+	if err == nil || !strings.Contains(err.Error(), "Correlation ID: "+CorrelationID(ctx)) {
+		t.Errorf("expected the correlation ID in the error, got: %v", err)
+	}
+}
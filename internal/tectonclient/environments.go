@@ -0,0 +1,100 @@
+package tectonclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+)
+
+// PythonEnvironment is a custom Python environment, from the output of the `python_environment.py`
+// script.
+type PythonEnvironment struct {
+	ID          string `json:"id" required:"true"`
+	Name        string `json:"name" required:"true"`
+	Description string `json:"description"`
+	// The resolution status, e.g. "PENDING", "READY" or "ERROR".
+	Status string `json:"status" required:"true" enum:"PENDING,READY,ERROR,DELETING,DELETION_FAILED,UNSPECIFIED"`
+	// Why the environment failed to resolve, if it did.
+	StatusDetails string `json:"status_details"`
+	// The requirements the environment was created from, in the format of a requirements.txt file.
+	Requirements string `json:"requirements"`
+	// Every package installed in the environment, with its exact version, once it is resolved.
+	ResolvedRequirements string `json:"resolved_requirements"`
+}
+
+// Returns an error if the client's capabilities were detected and don't include Python environments.
+func (c Client) requireEnvironments() error {
+	return c.RequireCapability(func(c Capabilities) bool { return c.Environments }, "Python environments")
+}
+
+// Parses the environment printed by the `python_environment.py` script. Returns nil if the output is
+// null.
+func parsePythonEnvironment(output []byte) (*PythonEnvironment, error) {
+	var environment *PythonEnvironment
+	err := parseJSON("python_environment.py", output, &environment)
+	if err != nil {
+		return nil, err
+	}
+	return environment, nil
+}
+
+// Creates a Python environment from requirements in the format of a requirements.txt file. Tecton
+// resolves the environment asynchronously, so it is usually still pending when this returns.
+func (c Client) CreatePythonEnvironment(ctx context.Context, name string, description string, requirements string) (PythonEnvironment, error) {
+	err := c.requireEnvironments()
+	if err != nil {
+		return PythonEnvironment{}, err
+	}
+	tflog.Info(ctx, fmt.Sprintf("Creating Python environment '%v'", name))
+	if c.SkipInDryRun(ctx, "python_environment.py create "+ShellJoin([]string{name, description})) {
+		// Ready, so that callers don't wait for an environment that doesn't exist
+		return PythonEnvironment{ID: DryRunID, Name: name, Description: description, Status: "READY", Requirements: requirements}, nil
+	}
+	output, err := c.RunScriptWithInput(ctx, []byte(requirements), "python_environment.py", "create", name, description)
+	if err != nil {
+		return PythonEnvironment{}, err
+	}
+	environment, err := parsePythonEnvironment(output)
+	if err != nil {
+		return PythonEnvironment{}, err
+	}
+	if environment == nil {
+		return PythonEnvironment{}, &diagnostics.ParseError{Source: "python_environment.py", Output: string(output), Err: fmt.Errorf("no environment was returned")}
+	}
+	c.Changes.Record(fmt.Sprintf("Created Python environment '%v' (%v)", name, environment.ID))
+	return *environment, nil
+}
+
+// Reads the Python environment with the given ID. Returns nil if it doesn't exist.
+func (c Client) GetPythonEnvironment(ctx context.Context, id string) (*PythonEnvironment, error) {
+	err := c.requireEnvironments()
+	if err != nil {
+		return nil, err
+	}
+	tflog.Info(ctx, fmt.Sprintf("Reading Python environment '%v'", id))
+	output, err := c.RunScript(ctx, "python_environment.py", "get", id)
+	if err != nil {
+		return nil, err
+	}
+	return parsePythonEnvironment(output)
+}
+
+// Deletes the Python environment with the given ID.
+func (c Client) DeletePythonEnvironment(ctx context.Context, id string) error {
+	err := c.requireEnvironments()
+	if err != nil {
+		return err
+	}
+	tflog.Info(ctx, fmt.Sprintf("Deleting Python environment '%v'", id))
+	if c.SkipInDryRun(ctx, "python_environment.py delete "+ShellJoin([]string{id})) {
+		return nil
+	}
+	_, err = c.RunScript(ctx, "python_environment.py", "delete", id)
+	if err != nil {
+		return err
+	}
+	c.Changes.Record(fmt.Sprintf("Deleted Python environment '%v'", id))
+	return nil
+}
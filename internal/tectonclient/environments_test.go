@@ -0,0 +1,30 @@
+package tectonclient
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetPythonEnvironment(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"id": "abc", "name": "rift", "description": "", "status": "READY", "status_details": "", "requirements": "pandas", "resolved_requirements": "pandas==2.2.2"}`)
+	environment, err := Client{}.GetPythonEnvironment(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if environment == nil || environment.Status != "READY" || environment.ResolvedRequirements != "pandas==2.2.2" {
+		t.Errorf("unexpected environment: %+v", environment)
+	}
+
+	fakeTectonPythonCLI(t, `null`)
+	environment, err = Client{}.GetPythonEnvironment(context.Background(), "abc")
+	if err != nil || environment != nil {
+		t.Errorf("expected no environment, got: %+v, %v", environment, err)
+	}
+}
+
+func TestPythonEnvironment_unsupported(t *testing.T) {
+	_, err := Client{Capabilities: &Capabilities{}}.GetPythonEnvironment(context.Background(), "abc")
+	if err == nil {
+		t.Error("expected an unsupported feature error")
+	}
+}
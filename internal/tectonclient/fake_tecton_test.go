@@ -0,0 +1,74 @@
+package tectonclient
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// Runs the client against the fake tecton CLI that module authors test with, so that it stays in
+// sync with the commands and scripts the provider runs.
+func TestFakeTectonCLI(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 is required to run the fake tecton CLI")
+	}
+	dir, err := filepath.Abs("../../testing/fake-tecton")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("TECTON_FAKE_STATE", filepath.Join(t.TempDir(), "state.json"))
+	ctx := context.Background()
+	cli := Client{Env: os.Environ()}
+
+	capabilities := DetectCapabilities(ctx, cli)
+	if capabilities == nil || !capabilities.JSONOutput {
+		t.Fatalf("unexpected capabilities: %+v", capabilities)
+	}
+	if _, err := cli.GetNameRules(ctx); err != nil {
+		t.Fatalf("unexpected error reading name rules: %v", err)
+	}
+
+	if err := cli.CreateWorkspace(ctx, "prod", true); err != nil {
+		t.Fatalf("unexpected error creating workspace: %v", err)
+	}
+	if err := cli.CreateWorkspace(ctx, "prod", true); err == nil {
+		t.Error("expected an error creating an existing workspace")
+	}
+	workspaces, err := cli.ListWorkspaces(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing workspaces: %v", err)
+	}
+	if isLive, found := workspaces.Lookup("prod"); !found || !isLive {
+		t.Errorf("expected live workspace 'prod', got: %+v", workspaces)
+	}
+
+	principal := Principal{UserID: "alice@example.com"}
+	if err := cli.AssignRole(ctx, principal, "owner", "prod"); err != nil {
+		t.Fatalf("unexpected error assigning role: %v", err)
+	}
+	if err := cli.AssignRole(ctx, principal, "admin", ""); err != nil {
+		t.Fatalf("unexpected error assigning role: %v", err)
+	}
+	policies, err := cli.GetRoles(ctx, principal)
+	if err != nil {
+		t.Fatalf("unexpected error reading roles: %v", err)
+	}
+	if len(policies) != 2 || policies[0].ResourceType != "ORGANIZATION" || policies[1].WorkspaceName != "prod" {
+		t.Errorf("unexpected policies: %+v", policies)
+	}
+	status, err := cli.GetPrincipalStatus(ctx, principal)
+	if err != nil || !status.Found {
+		t.Errorf("expected the principal to be found, got: %+v, %v", status, err)
+	}
+
+	if err := cli.DeleteWorkspace(ctx, "prod", true); err != nil {
+		t.Fatalf("unexpected error deleting workspace: %v", err)
+	}
+	policies, err = cli.GetRoles(ctx, principal)
+	if err != nil || len(policies) != 1 {
+		t.Errorf("expected only the organization policy after deleting the workspace, got: %+v, %v", policies, err)
+	}
+}
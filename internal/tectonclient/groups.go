@@ -0,0 +1,69 @@
+package tectonclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Group is a principal group, from the output of the `group.py` script.
+type Group struct {
+	ID          string `json:"id" required:"true"`
+	Name        string `json:"name" required:"true"`
+	Description string `json:"description"`
+	// The email of the user or the ID of the service account that created the group.
+	CreatedBy string `json:"created_by"`
+	// An RFC 3339 timestamp, or empty if Tecton doesn't report one.
+	CreatedAt string `json:"created_at"`
+	// The names of the identity provider groups that are mapped to the group, sorted.
+	IdpMappingNames []string `json:"idp_mapping_names"`
+}
+
+// GroupMembers are the members of a principal group, from the output of the `group_members.py`
+// script. Both lists are sorted.
+type GroupMembers struct {
+	Users           []string `json:"users" required:"true"`
+	ServiceAccounts []string `json:"service_accounts" required:"true"`
+}
+
+// Reads the users and service accounts that are members of a principal group.
+func (c Client) GetGroupMembers(ctx context.Context, groupID string) (GroupMembers, error) {
+	err := c.RequireCapability(func(c Capabilities) bool { return c.Groups }, "Principal groups")
+	if err != nil {
+		return GroupMembers{}, err
+	}
+	tflog.Info(ctx, fmt.Sprintf("Reading the members of principal group '%v'", groupID))
+	output, err := c.RunScript(ctx, "group_members.py", groupID)
+	if err != nil {
+		return GroupMembers{}, err
+	}
+	var members GroupMembers
+	err = parseJSON("group_members.py", output, &members)
+	if err != nil {
+		return GroupMembers{}, err
+	}
+	return members, nil
+}
+
+// Reads the principal group with the given name. Returns an error if there is none.
+func (c Client) GetGroupByName(ctx context.Context, name string) (Group, error) {
+	err := c.RequireCapability(func(c Capabilities) bool { return c.Groups }, "Principal groups")
+	if err != nil {
+		return Group{}, err
+	}
+	tflog.Info(ctx, fmt.Sprintf("Reading principal group '%v'", name))
+	output, err := c.RunScript(ctx, "group.py", name)
+	if err != nil {
+		return Group{}, err
+	}
+	var group *Group
+	err = parseJSON("group.py", output, &group)
+	if err != nil {
+		return Group{}, err
+	}
+	if group == nil {
+		return Group{}, fmt.Errorf("Principal group '%v' not found.", name)
+	}
+	return *group, nil
+}
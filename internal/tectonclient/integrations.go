@@ -0,0 +1,91 @@
+package tectonclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// IntegrationScope describes the secret scope of an integration, from the output of
+// `integration.py read`. The values of the secrets can't be read back.
+type IntegrationScope struct {
+	Exists bool     `json:"exists" required:"true"`
+	Keys   []string `json:"keys" required:"true"`
+}
+
+// The input of `integration.py apply`.
+type integrationChange struct {
+	Values     map[string]string `json:"values"`
+	DeleteKeys []string          `json:"delete_keys"`
+}
+
+// Returns an error if the client's capabilities were detected and don't include secrets, which
+// integrations are stored in.
+func (c Client) requireSecrets() error {
+	return c.RequireCapability(func(c Capabilities) bool { return c.Secrets }, "Secrets")
+}
+
+// Reads which keys are set in the secret scope of an integration.
+func (c Client) ReadIntegration(ctx context.Context, scope string) (IntegrationScope, error) {
+	err := c.requireSecrets()
+	if err != nil {
+		return IntegrationScope{}, err
+	}
+	tflog.Info(ctx, fmt.Sprintf("Reading integration scope '%v'", scope))
+	output, err := c.RunScript(ctx, "integration.py", "read", scope)
+	if err != nil {
+		return IntegrationScope{}, err
+	}
+	var integration IntegrationScope
+	err = parseJSON("integration.py", output, &integration)
+	if err != nil {
+		return IntegrationScope{}, err
+	}
+	return integration, nil
+}
+
+// Creates the secret scope of an integration if it doesn't exist, sets values in it and deletes
+// deleteKeys from it. The values are passed on stdin, so that they never appear in commands or logs.
+func (c Client) ApplyIntegration(ctx context.Context, scope string, values map[string]string, deleteKeys []string) error {
+	err := c.requireSecrets()
+	if err != nil {
+		return err
+	}
+	if deleteKeys == nil {
+		deleteKeys = []string{}
+	}
+	input, err := json.Marshal(integrationChange{Values: values, DeleteKeys: deleteKeys})
+	if err != nil {
+		return err
+	}
+	tflog.Info(ctx, fmt.Sprintf("Setting %v keys and deleting %v keys in integration scope '%v'", len(values), len(deleteKeys), scope))
+	if c.SkipInDryRun(ctx, "integration.py apply "+ShellJoin([]string{scope})) {
+		return nil
+	}
+	_, err = c.RunScriptWithInput(ctx, input, "integration.py", "apply", scope)
+	if err != nil {
+		return err
+	}
+	c.Changes.Record(fmt.Sprintf("Set %v keys and deleted %v keys of integration '%v'", len(values), len(deleteKeys), scope))
+	return nil
+}
+
+// Deletes the secret scope of an integration, including every secret in it.
+func (c Client) DeleteIntegration(ctx context.Context, scope string) error {
+	err := c.requireSecrets()
+	if err != nil {
+		return err
+	}
+	tflog.Info(ctx, fmt.Sprintf("Deleting integration scope '%v'", scope))
+	if c.SkipInDryRun(ctx, "integration.py delete "+ShellJoin([]string{scope})) {
+		return nil
+	}
+	_, err = c.RunScript(ctx, "integration.py", "delete", scope)
+	if err != nil {
+		return err
+	}
+	c.Changes.Record(fmt.Sprintf("Deleted integration '%v'", scope))
+	return nil
+}
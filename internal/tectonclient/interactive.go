@@ -0,0 +1,65 @@
+package tectonclient
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"slices"
+
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+)
+
+// Flags that stop subcommands from asking for confirmation, which are added to every command of the
+// subcommand that doesn't already have them.
+var nonInteractiveFlags = []struct {
+	Subcommand []string
+	Flag       string
+}{
+	{[]string{"apply"}, "--yes"},
+	{[]string{"destroy"}, "--yes"},
+	{[]string{"workspace", "delete"}, "--yes"},
+}
+
+// Environment variables every command is run with. Unbuffered output keeps everything a command
+// printed before it failed or was killed, e.g. by a timeout.
+var nonInteractiveEnv = []string{"PYTHONUNBUFFERED=1"}
+
+// Matches the output of a command that failed because it prompted for input and read the end of its
+// stdin, e.g. a click confirmation followed by "Aborted!".
+var promptAbortedRegex = regexp.MustCompile(`(?i)(\[y/n\]:? *\n?Aborted!|EOFError: EOF when reading a line)`)
+
+// Returns args with the flags that stop the subcommand from prompting for confirmation.
+func nonInteractiveArgs(args []string) []string {
+	for _, flags := range nonInteractiveFlags {
+		if len(args) < len(flags.Subcommand) || !slices.Equal(args[:len(flags.Subcommand)], flags.Subcommand) {
+			continue
+		}
+		if !slices.Contains(args, flags.Flag) {
+			args = append(slices.Clone(args), flags.Flag)
+		}
+	}
+	return args
+}
+
+// Runs cmd with its stdin closed and returns its combined output. A command that prompts for input
+// reads the end of stdin and fails, in which case an error wrapping diagnostics.ErrWaitingForInput
+// is returned. Quiet commands aren't killed, since long applies and deletions print nothing for
+// minutes, so a command that reads the terminal directly only stops at its timeout.
+func runNonInteractive(cmd *exec.Cmd) ([]byte, error) {
+	cmd.Stdin = nil
+	output, err := cmd.CombinedOutput()
+	if err != nil && promptAbortedRegex.Match(output) {
+		return output, waitingForInputError()
+	}
+	return output, err
+}
+
+// Returns the error of a command that failed because it waited for input. It doesn't wrap an
+// *exec.ExitError, so the command isn't retried.
+func waitingForInputError() error {
+	return fmt.Errorf(
+		"%w. The provider can't answer prompts, so the command failed. Run the command by hand to see what it asks for, "+
+			"e.g. to log in or to confirm a change, and configure the provider or the command so that it doesn't ask",
+		diagnostics.ErrWaitingForInput,
+	)
+}
@@ -0,0 +1,44 @@
+package tectonclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// CommandLimiter limits how many commands and scripts run at once across every client that shares it,
+// since Terraform runs resource operations in parallel and every command starts a Python process that
+// calls the Tecton API. It's safe for concurrent use, and a nil limiter doesn't limit anything.
+type CommandLimiter struct {
+	slots chan struct{}
+}
+
+// Returns a limiter that lets at most limit commands run at once.
+func NewCommandLimiter(limit int) *CommandLimiter {
+	return &CommandLimiter{slots: make(chan struct{}, limit)}
+}
+
+// Waits until fewer than the limiter's limit of commands are running, and returns the function that
+// must be called once the command finishes. Fails if ctx is done first.
+func (l *CommandLimiter) acquire(ctx context.Context, command string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	default:
+	}
+	tflog.Debug(ctx, fmt.Sprintf("Command '%v' is waiting, because %v commands are already running", command, cap(l.slots)))
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("Gave up waiting to run '%v', because %v commands were already running: %w", command, cap(l.slots), context.Cause(ctx))
+	}
+}
+
+func (l *CommandLimiter) release() {
+	<-l.slots
+}
@@ -0,0 +1,65 @@
+package tectonclient
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+)
+
+func TestCommandLimiter(t *testing.T) {
+	limiter := NewCommandLimiter(1)
+	release, err := limiter.acquire(context.Background(), "workspace list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(diagnostics.ErrTimedOut)
+	_, err = limiter.acquire(ctx, "workspace list")
+	if !errors.Is(err, diagnostics.ErrTimedOut) {
+		t.Errorf("expected the cause of the context, got: %v", err)
+	}
+
+	release()
+	release, err = limiter.acquire(ctx, "workspace list")
+	if err != nil {
+		t.Fatalf("expected a free slot to be acquired even if the context is done, got: %v", err)
+	}
+	release()
+
+	var nilLimiter *CommandLimiter
+	if _, err := nilLimiter.acquire(ctx, "workspace list"); err != nil {
+		t.Errorf("expected a nil limiter not to limit anything, got: %v", err)
+	}
+}
+
+func TestRunLimiter(t *testing.T) {
+	dir := t.TempDir()
+	overlaps := filepath.Join(dir, "overlaps")
+	// mkdir fails if another command is still running
+	fakeTectonCLI(t, "mkdir "+filepath.Join(dir, "running")+" 2>/dev/null || echo overlap >> "+overlaps+"\nsleep 0.05\nrmdir "+filepath.Join(dir, "running"))
+	cli := Client{Limiter: NewCommandLimiter(1)}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cli.Run(context.Background(), "workspace", "list"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(overlaps)
+	if err == nil {
+		t.Errorf("expected commands to run one at a time, got %v overlaps", strings.Count(string(data), "overlap"))
+	}
+}
@@ -0,0 +1,154 @@
+package tectonclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// OperationMetrics are the commands run by resource operations, e.g. by a single Create or by every
+// operation on a resource type.
+type OperationMetrics struct {
+	// The number of resource operations, which is 1 for a single operation.
+	Operations int
+	// The number of commands and scripts that were run, including retries.
+	Commands int
+	// The number of commands that were retried after a transient or lock error.
+	Retries int
+	// The total time commands took to run, excluding the delays between retries.
+	Duration time.Duration
+}
+
+// Returns the metrics as log fields.
+func (m OperationMetrics) fields() map[string]interface{} {
+	return map[string]interface{}{
+		"operations":  m.Operations,
+		"commands":    m.Commands,
+		"retries":     m.Retries,
+		"duration_ms": m.Duration.Milliseconds(),
+	}
+}
+
+// Adds other's metrics to m.
+func (m *OperationMetrics) add(other OperationMetrics) {
+	m.Operations += other.Operations
+	m.Commands += other.Commands
+	m.Retries += other.Retries
+	m.Duration += other.Duration
+}
+
+// MetricsRecorder accumulates the commands run by every resource operation of a plan or apply, so
+// that a summary can be logged to track the provider's performance. It's safe for concurrent use.
+type MetricsRecorder struct {
+	mu sync.Mutex
+	// The metrics of the operations in progress, by correlation ID.
+	operations map[string]*OperationMetrics
+	// The metrics of the finished operations, by resource type.
+	resources map[string]*OperationMetrics
+}
+
+// Returns the metrics of the operation ctx belongs to, creating them if needed. Must be called with
+// the lock held. Returns nil if ctx doesn't belong to an operation.
+func (r *MetricsRecorder) operation(ctx context.Context) *OperationMetrics {
+	id := CorrelationID(ctx)
+	if id == "" {
+		return nil
+	}
+	if r.operations == nil {
+		r.operations = make(map[string]*OperationMetrics)
+	}
+	metrics, ok := r.operations[id]
+	if !ok {
+		metrics = &OperationMetrics{Operations: 1}
+		r.operations[id] = metrics
+	}
+	return metrics
+}
+
+// Records a command that ran for duration in the operation ctx belongs to. Does nothing if the
+// recorder is nil.
+func (r *MetricsRecorder) recordCommand(ctx context.Context, duration time.Duration) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if metrics := r.operation(ctx); metrics != nil {
+		metrics.Commands++
+		metrics.Duration += duration
+	}
+}
+
+// Records a retry in the operation ctx belongs to. Does nothing if the recorder is nil.
+func (r *MetricsRecorder) recordRetry(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if metrics := r.operation(ctx); metrics != nil {
+		metrics.Retries++
+	}
+}
+
+// Adds the metrics of the operation ctx belongs to to the totals of its resource type, and returns
+// them.
+func (r *MetricsRecorder) finish(ctx context.Context, resourceType string) OperationMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	metrics := r.operation(ctx)
+	if metrics == nil {
+		metrics = &OperationMetrics{Operations: 1}
+	}
+	delete(r.operations, CorrelationID(ctx))
+	if r.resources == nil {
+		r.resources = make(map[string]*OperationMetrics)
+	}
+	if r.resources[resourceType] == nil {
+		r.resources[resourceType] = &OperationMetrics{}
+	}
+	r.resources[resourceType].add(*metrics)
+	return *metrics
+}
+
+// Returns the metrics of the operations finished so far, by resource type.
+func (r *MetricsRecorder) Totals() map[string]OperationMetrics {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	totals := make(map[string]OperationMetrics, len(r.resources))
+	for resourceType, metrics := range r.resources {
+		totals[resourceType] = *metrics
+	}
+	return totals
+}
+
+// Logs the commands run by the operation ctx belongs to, e.g. a tecton_workspace's Create, along
+// with the totals of every operation finished so far in the plan or apply. Terraform doesn't tell
+// providers when a run ends, so the summary logged by the last operation is the summary of the run.
+// Does nothing if the client doesn't record metrics.
+func (c Client) LogOperationSummary(ctx context.Context, resourceType string, operation string) {
+	if c.Metrics == nil {
+		return
+	}
+	metrics := c.Metrics.finish(ctx, resourceType)
+
+	var total OperationMetrics
+	byResourceType := make(map[string]interface{})
+	for resourceType, metrics := range c.Metrics.Totals() {
+		total.add(metrics)
+		byResourceType[resourceType] = metrics.fields()
+	}
+
+	fields := metrics.fields()
+	delete(fields, "operations")
+	fields["resource_type"] = resourceType
+	fields["operation"] = operation
+	fields["run_totals"] = total.fields()
+	fields["run_totals_by_resource_type"] = byResourceType
+	tflog.Info(ctx, "Operation summary", fields)
+}
@@ -0,0 +1,62 @@
+package tectonclient
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunRecordsMetrics(t *testing.T) {
+	retryDelay := commandRetryDelay
+	commandRetryDelay = time.Millisecond
+	t.Cleanup(func() { commandRetryDelay = retryDelay })
+
+	marker := filepath.Join(t.TempDir(), "failed")
+	fakeTectonCLI(t, "if [ ! -f "+marker+" ]; then touch "+marker+"; echo 'Service Unavailable'; exit 1; fi\necho ok")
+	cli := Client{Metrics: &MetricsRecorder{}}
+
+	create := Client{}.WithCorrelationID(context.Background())
+	for range 2 {
+		_, err := cli.Run(create, "workspace", "list")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	read := Client{}.WithCorrelationID(context.Background())
+	_, err := cli.Run(read, "workspace", "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Commands run outside an operation aren't attributed to any
+	_, err = cli.Run(context.Background(), "workspace", "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cli.LogOperationSummary(create, "tecton_workspace", "Create")
+	cli.LogOperationSummary(read, "tecton_workspace", "Read")
+	cli.LogOperationSummary(Client{}.WithCorrelationID(context.Background()), "data.tecton_workspace", "Read")
+
+	totals := cli.Metrics.Totals()
+	workspace := totals["tecton_workspace"]
+	if workspace.Operations != 2 || workspace.Commands != 4 || workspace.Retries != 1 || workspace.Duration <= 0 {
+		t.Errorf("expected 2 operations, 4 commands and 1 retry, got %+v", workspace)
+	}
+	dataSource := totals["data.tecton_workspace"]
+	if dataSource.Operations != 1 || dataSource.Commands != 0 {
+		t.Errorf("expected an operation without commands, got %+v", dataSource)
+	}
+	if len(cli.Metrics.operations) != 0 {
+		t.Errorf("expected finished operations to be removed, got %v", cli.Metrics.operations)
+	}
+}
+
+func TestLogOperationSummaryWithoutMetrics(t *testing.T) {
+	// Clients that don't record metrics, e.g. of unconfigured resources, don't log summaries
+	Client{}.LogOperationSummary(Client{}.WithCorrelationID(context.Background()), "tecton_workspace", "Read")
+	var recorder *MetricsRecorder
+	if recorder.Totals() != nil {
+		t.Errorf("expected no totals")
+	}
+}
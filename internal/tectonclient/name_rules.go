@@ -0,0 +1,59 @@
+package tectonclient
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// NameRule is a rule the cluster validates a kind of name with, from the output of the
+// `name_rules.py` script.
+type NameRule struct {
+	// A regular expression that the whole name must match. Empty if the cluster doesn't report one.
+	Pattern string `json:"pattern"`
+	// The longest allowed name, or 0 if the cluster doesn't report a limit.
+	MaxLength int `json:"max_length"`
+}
+
+// NameRules are the rules the cluster validates names with.
+type NameRules struct {
+	WorkspaceName    NameRule `json:"workspace_name" required:"true"`
+	UserID           NameRule `json:"user_id" required:"true"`
+	ServiceAccountID NameRule `json:"service_account_id" required:"true"`
+}
+
+// Reads the rules the cluster validates names with.
+func (c Client) GetNameRules(ctx context.Context) (NameRules, error) {
+	tflog.Info(ctx, "Reading the cluster's name validation rules")
+	output, err := c.RunScript(ctx, "name_rules.py")
+	if err != nil {
+		return NameRules{}, err
+	}
+	var rules NameRules
+	err = parseJSON("name_rules.py", output, &rules)
+	if err != nil {
+		return NameRules{}, err
+	}
+	return rules, nil
+}
+
+// Returns why name breaks the rule, or an empty string if it doesn't. A pattern that Go can't
+// compile, e.g. because it uses Python-only syntax, is ignored.
+func (r NameRule) Check(name string) string {
+	if r.MaxLength > 0 && len(name) > r.MaxLength {
+		return fmt.Sprintf("must be at most %v characters long, got %v", r.MaxLength, len(name))
+	}
+	if r.Pattern == "" {
+		return ""
+	}
+	pattern, err := regexp.Compile(`^(?:` + r.Pattern + `)$`)
+	if err != nil {
+		return ""
+	}
+	if !pattern.MatchString(name) {
+		return fmt.Sprintf("must match the cluster's pattern %v", r.Pattern)
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package tectonclient
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetNameRules(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"workspace_name": {"pattern": "[a-z0-9-]+", "max_length": 40}, "user_id": {"pattern": "", "max_length": 0}, "service_account_id": {"pattern": "", "max_length": 0}}`)
+	rules, err := Client{}.GetNameRules(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := NameRule{Pattern: "[a-z0-9-]+", MaxLength: 40}
+	if rules.WorkspaceName != expected {
+		t.Errorf("expected %+v, got %+v", expected, rules.WorkspaceName)
+	}
+
+	fakeTectonPythonCLI(t, `{"workspace_name": {"pattern": "", "max_length": 0}}`)
+	_, err = Client{}.GetNameRules(context.Background())
+	if err == nil {
+		t.Error("expected an error for missing rules")
+	}
+}
+
+func TestNameRuleCheck(t *testing.T) {
+	testCases := map[string]struct {
+		rule  NameRule
+		name  string
+		valid bool
+	}{
+		"no rule":         {rule: NameRule{}, name: "Any Name", valid: true},
+		"matches":         {rule: NameRule{Pattern: "[a-z-]+"}, name: "prod-live", valid: true},
+		"partial match":   {rule: NameRule{Pattern: "[a-z-]+"}, name: "prod_live", valid: false},
+		"alternation":     {rule: NameRule{Pattern: "a|b"}, name: "ab", valid: false},
+		"too long":        {rule: NameRule{MaxLength: 4}, name: "prod-live", valid: false},
+		"max length":      {rule: NameRule{MaxLength: 4}, name: "prod", valid: true},
+		"invalid pattern": {rule: NameRule{Pattern: "(?P=name)"}, name: "prod", valid: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			problem := testCase.rule.Check(testCase.name)
+			if testCase.valid && problem != "" {
+				t.Errorf("expected '%v' to be valid, got: %v", testCase.name, problem)
+			} else if !testCase.valid && problem == "" {
+				t.Errorf("expected '%v' to be invalid", testCase.name)
+			}
+		})
+	}
+}
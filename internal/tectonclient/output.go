@@ -0,0 +1,79 @@
+package tectonclient
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+)
+
+// Returns the error of a failed `tecton` command with the given arguments and output. action
+// describes what the command does, e.g. "create Tecton workspace 'prod'", and may be empty. If the
+// output is longer than the client's OutputLimit, the error only includes its head and tail, and the
+// full output is written to a file whose path is included instead.
+func (c Client) CommandError(ctx context.Context, action string, args []string, output []byte, err error) error {
+	return c.commandError(ctx, action, "tecton "+ShellJoin(args), output, err)
+}
+
+// Like CommandError, but for any command line, e.g. an embedded script. Secrets are redacted from the
+// command and its output, including the file the full output is written to.
+func (c Client) commandError(ctx context.Context, action string, command string, output []byte, err error) error {
+	command = c.Redactor.Redact(command)
+	output = []byte(c.Redactor.Redact(string(output)))
+	commandErr := &diagnostics.CommandError{
+		Action:        action,
+		Command:       command,
+		Output:        string(output),
+		CorrelationID: CorrelationID(ctx),
+		Err:           err,
+	}
+	if c.OutputLimit <= 0 || len(output) <= c.OutputLimit {
+		return commandErr
+	}
+
+	commandErr.Output = truncateOutput(commandErr.Output, c.OutputLimit)
+	file, fileErr := os.CreateTemp("", "tecton-command-*.log")
+	if fileErr == nil {
+		_, fileErr = fmt.Fprintf(file, "Command: %v\nCorrelation ID: %v\nError: %v\nOutput:\n%s", command, commandErr.CorrelationID, err, output)
+		if closeErr := file.Close(); fileErr == nil {
+			fileErr = closeErr
+		}
+	}
+	if fileErr != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Failed to write the full output of '%v' to a file: %v", command, fileErr.Error()))
+		return commandErr
+	}
+	commandErr.OutputFile = file.Name()
+	tflog.Debug(ctx, fmt.Sprintf("Wrote the full output of '%v' to '%v'", command, file.Name()))
+	return commandErr
+}
+
+// Returns output shortened to about limit bytes by replacing its middle with a note of how much was
+// omitted, since the start of a failed command's output usually says what it was doing and the end
+// says why it failed. Whole lines are kept where possible.
+func truncateOutput(output string, limit int) string {
+	if len(output) <= limit {
+		return output
+	}
+	head := output[:limit/2]
+	if i := strings.LastIndexByte(head, '\n'); i > 0 {
+		head = head[:i+1]
+	}
+	tail := output[len(output)-limit/2:]
+	if i := strings.IndexByte(tail, '\n'); i >= 0 && i < len(tail)-1 {
+		tail = tail[i+1:]
+	}
+	// Don't split multi-byte characters at the cuts
+	head = strings.TrimRightFunc(head, func(r rune) bool { return r == utf8.RuneError })
+	tail = strings.TrimLeftFunc(tail, func(r rune) bool { return r == utf8.RuneError })
+
+	omitted := len(output) - len(head) - len(tail)
+	if !strings.HasSuffix(head, "\n") {
+		head += "\n"
+	}
+	return fmt.Sprintf("%v... (%v bytes omitted) ...\n%v", head, omitted, tail)
+}
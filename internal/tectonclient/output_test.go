@@ -0,0 +1,84 @@
+package tectonclient
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+)
+
+func TestTruncateOutput(t *testing.T) {
+	testCases := map[string]struct {
+		output   string
+		limit    int
+		expected string
+	}{
+		"short":       {output: "a\nb\n", limit: 10, expected: "a\nb\n"},
+		"whole lines": {output: "one\ntwo\nthree\nfour\nfive\n", limit: 12, expected: "one\n... (15 bytes omitted) ...\nfive\n"},
+		"one line":    {output: "0123456789", limit: 4, expected: "01\n... (6 bytes omitted) ...\n89"},
+		"multi-byte":  {output: "ééééé", limit: 5, expected: "é\n... (6 bytes omitted) ...\né"},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := truncateOutput(testCase.output, testCase.limit)
+			if actual != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCommandErrorOutputLimit(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	output := []byte("Planning...\n" + strings.Repeat("Validating feature view\n", 1000) + "Error: invalid schema\n")
+
+	err := Client{}.CommandError(context.Background(), "", []string{"plan"}, output, errors.New("exit status 1"))
+	var commandErr *diagnostics.CommandError
+	if !errors.As(err, &commandErr) || commandErr.Output != string(output) || commandErr.OutputFile != "" {
+		t.Fatalf("expected the full output without a limit, got: %#v", err)
+	}
+
+	err = Client{OutputLimit: 100}.CommandError(context.Background(), "", []string{"plan"}, output, errors.New("exit status 1"))
+	if !errors.As(err, &commandErr) {
+		t.Fatalf("expected a command error, got: %#v", err)
+	}
+	if !strings.HasPrefix(commandErr.Output, "Planning...\n") || !strings.HasSuffix(commandErr.Output, "Error: invalid schema\n") || len(commandErr.Output) > 150 {
+		t.Errorf("expected the head and tail of the output, got: %q", commandErr.Output)
+	}
+	full, readErr := os.ReadFile(commandErr.OutputFile)
+	if readErr != nil {
+		t.Fatalf("expected the full output in a file: %v", readErr)
+	}
+	if !strings.HasSuffix(string(full), string(output)) {
+		t.Errorf("expected the file to contain the full output, got: %q", full)
+	}
+	if !strings.Contains(err.Error(), "the full output is in "+commandErr.OutputFile) {
+		t.Errorf("expected the error to reference the file, got: %v", err)
+	}
+}
+
+func TestCommandErrorRedactsSecrets(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	redactor := &diagnostics.Redactor{}
+	redactor.AddSecret("command-error-key-123")
+	output := []byte("Traceback: TECTON_API_KEY=command-error-key-123\n" + strings.Repeat("x", 100))
+
+	err := Client{OutputLimit: 50, Redactor: redactor}.CommandError(context.Background(), "", []string{"plan"}, output, errors.New("exit status 1"))
+	var commandErr *diagnostics.CommandError
+	if !errors.As(err, &commandErr) || commandErr.OutputFile == "" {
+		t.Fatalf("expected the output to be written to a file, got: %v", err)
+	}
+	data, readErr := os.ReadFile(commandErr.OutputFile)
+	if readErr != nil {
+		t.Fatalf("failed to read the output file: %v", readErr)
+	}
+	if strings.Contains(err.Error(), "command-error-key-123") {
+		t.Errorf("expected the key to be redacted from the error, got: %v", err)
+	}
+	if strings.Contains(string(data), "command-error-key-123") || !strings.Contains(string(data), "TECTON_API_KEY=<redacted>") {
+		t.Errorf("expected the key to be redacted from the output file, got: %s", data)
+	}
+}
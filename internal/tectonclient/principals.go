@@ -0,0 +1,46 @@
+package tectonclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// PrincipalStatus describes whether a user or service account exists and is active, from the output
+// of the `principal_status.py` script.
+type PrincipalStatus struct {
+	Found bool `json:"found" required:"true"`
+	// Nil if Tecton doesn't report whether the principal is active.
+	Active *bool `json:"active"`
+	// The status as Tecton reports it, e.g. "DEPROVISIONED" for a deactivated user.
+	Status string `json:"status"`
+	// When a service account's API key expires. Nil if it doesn't expire, Tecton doesn't report it, or
+	// the principal is a user.
+	KeyExpiresAt *time.Time `json:"key_expires_at"`
+}
+
+// Returns true if the principal no longer exists or is known to be deactivated.
+func (s PrincipalStatus) Deactivated() bool {
+	return !s.Found || (s.Active != nil && !*s.Active)
+}
+
+// Reads whether a user or service account exists and is active.
+func (c Client) GetPrincipalStatus(ctx context.Context, principal Principal) (PrincipalStatus, error) {
+	kind, id := "user", principal.UserID
+	if id == "" {
+		kind, id = "service", principal.ServiceAccountID
+	}
+	tflog.Info(ctx, fmt.Sprintf("Reading the status of %v", principal))
+	output, err := c.RunScript(ctx, "principal_status.py", kind, id)
+	if err != nil {
+		return PrincipalStatus{}, err
+	}
+	var status PrincipalStatus
+	err = parseJSON("principal_status.py", output, &status)
+	if err != nil {
+		return PrincipalStatus{}, err
+	}
+	return status, nil
+}
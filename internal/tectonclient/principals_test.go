@@ -0,0 +1,43 @@
+package tectonclient
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetPrincipalStatus(t *testing.T) {
+	testCases := map[string]struct {
+		output      string
+		deactivated bool
+	}{
+		"active":         {output: `{"found": true, "active": true, "status": "ACTIVE"}`},
+		"deactivated":    {output: `{"found": true, "active": false, "status": "DEPROVISIONED"}`, deactivated: true},
+		"not found":      {output: `{"found": false, "active": null, "status": ""}`, deactivated: true},
+		"status unknown": {output: `{"found": true, "active": null, "status": ""}`},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fakeTectonPythonCLI(t, testCase.output)
+			status, err := Client{}.GetPrincipalStatus(context.Background(), Principal{UserID: "alice@example.com"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status.Deactivated() != testCase.deactivated {
+				t.Errorf("expected deactivated: %v, got: %+v", testCase.deactivated, status)
+			}
+		})
+	}
+}
+
+func TestGetPrincipalStatusKeyExpiration(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"found": true, "active": true, "status": "ACTIVE", "key_expires_at": "2026-03-01T12:00:00Z"}`)
+	status, err := Client{}.GetPrincipalStatus(context.Background(), Principal{ServiceAccountID: "ci-bot"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if status.KeyExpiresAt == nil || !status.KeyExpiresAt.Equal(expected) {
+		t.Errorf("expected the key to expire at %v, got %v", expected, status.KeyExpiresAt)
+	}
+}
@@ -0,0 +1,41 @@
+# Makes the tecton CLI and the provider's scripts skip TLS certificate verification, for the
+# provider's `insecure_skip_tls_verify` and `allow_insecure`. Python imports this module at startup
+# because its directory is prepended to PYTHONPATH. Neither the standard library nor requests has an
+# environment variable that disables verification, so both are patched instead.
+import importlib.machinery
+import importlib.util
+import os
+import ssl
+import sys
+
+# urllib and http.client use this context unless they're given one.
+ssl._create_default_https_context = ssl._create_unverified_context
+
+try:
+    import requests
+    import urllib3
+except ImportError:
+    requests = None
+
+if requests is not None:
+    _merge_environment_settings = requests.Session.merge_environment_settings
+
+    # Every request of a session gets its `verify` setting from here, including requests that pass
+    # `verify=True` explicitly or that would use REQUESTS_CA_BUNDLE.
+    def merge_environment_settings(self, url, proxies, stream, verify, cert):
+        settings = _merge_environment_settings(self, url, proxies, stream, verify, cert)
+        settings["verify"] = False
+        return settings
+
+    requests.Session.merge_environment_settings = merge_environment_settings
+    # The warning would be printed for every request, and the provider already warns about it.
+    urllib3.disable_warnings(urllib3.exceptions.InsecureRequestWarning)
+
+# This module shadows any other sitecustomize module, e.g. the one of a Linux distribution's
+# Python, so that one is run too.
+_here = os.path.dirname(os.path.abspath(__file__))
+_spec = importlib.machinery.PathFinder.find_spec(
+    "sitecustomize", [entry for entry in sys.path if os.path.abspath(entry or ".") != _here]
+)
+if _spec is not None and _spec.loader is not None:
+    _spec.loader.exec_module(importlib.util.module_from_spec(_spec))
@@ -0,0 +1,215 @@
+package tectonclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+)
+
+// The number of times a `tecton` command is run before a retriable failure is reported, unless the
+// client has its own CommandRetry.
+const commandMaxAttempts = 3
+
+// How long to wait before running a command again after its first retriable failure, unless the
+// client has its own CommandRetry.
+var commandRetryDelay = 2 * time.Second
+
+// The longest delay between attempts of a command that keeps failing with retriable errors, unless
+// the client has its own CommandRetry.
+const commandMaxRetryDelay = 30 * time.Second
+
+// Matches the output of commands that Tecton rejected because another plan or apply is in progress
+// on the workspace.
+var lockErrorRegex = regexp.MustCompile(`(?i)(another (plan|apply|operation) is (already )?(in progress|running)|workspace is (currently )?locked|(held|locked) by another (plan|apply|operation))`)
+
+// LockRetry configures how commands that Tecton rejected because another plan or apply is in progress
+// on the workspace are retried. The delay between attempts starts at InitialDelay and doubles after
+// every attempt, up to MaxDelay.
+type LockRetry struct {
+	// How long to keep retrying before the failure is reported. Zero disables retries.
+	Timeout      time.Duration
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+}
+
+// Returns the delay before the attempt after one that waited delay, or InitialDelay for the first
+// retry.
+func (r LockRetry) nextDelay(delay time.Duration) time.Duration {
+	if delay <= 0 {
+		return r.InitialDelay
+	}
+	return min(delay*2, max(r.MaxDelay, r.InitialDelay))
+}
+
+// CommandRetry configures how commands that failed with a retriable error, e.g. a network error, are
+// retried. The delay between attempts starts at InitialDelay and doubles after every attempt, up to
+// MaxDelay.
+type CommandRetry struct {
+	// The number of times a command is run before the failure is reported. 1 disables retries.
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+}
+
+// Returns the retry policy of clients without their own CommandRetry.
+func DefaultCommandRetry() CommandRetry {
+	return CommandRetry{
+		MaxAttempts:  commandMaxAttempts,
+		InitialDelay: commandRetryDelay,
+		MaxDelay:     max(commandMaxRetryDelay, commandRetryDelay),
+	}
+}
+
+// Returns the delay before the attempt after one that waited delay, or InitialDelay for the first
+// retry.
+func (r CommandRetry) nextDelay(delay time.Duration) time.Duration {
+	if delay <= 0 {
+		return r.InitialDelay
+	}
+	return min(delay*2, max(r.MaxDelay, r.InitialDelay))
+}
+
+// Returns the client's retry policy for retriable failures.
+func (c Client) commandRetry() CommandRetry {
+	if c.Retry != nil {
+		return *c.Retry
+	}
+	return DefaultCommandRetry()
+}
+
+// Calls run, which runs the command described by command once and returns the output its failures
+// are classified by, until it succeeds or fails with an error that shouldn't be retried. The client's
+// OAuth session, if any, is refreshed before every attempt, and every attempt waits for its Limiter,
+// which isn't held while waiting to retry. Whether a failure is retried is decided by
+// commandFailureRules. Every attempt counts towards a single budget: failures because another plan or
+// apply holds the workspace's lock are retried until the client's LockRetry times out, and other
+// retriable failures until the command has been run the MaxAttempts of its CommandRetry, including
+// the attempts that waited for the lock. Returns the output and error of the last attempt.
+func (c Client) retry(ctx context.Context, command string, run func() ([]byte, error)) ([]byte, error) {
+	policy := c.commandRetry()
+	var lockDeadline time.Time
+	var lockDelay, retryDelay time.Duration
+	attempt := 1
+	for {
+		err := c.Session.Refresh(ctx)
+		if err != nil {
+			return nil, err
+		}
+		release, err := c.Limiter.acquire(ctx, command)
+		if err != nil {
+			return nil, err
+		}
+		output, err := run()
+		release()
+		if err == nil {
+			return output, nil
+		}
+
+		rule, matched := matchFailureRule(err, output)
+		if !matched || !rule.Retriable {
+			return output, err
+		}
+		var delay time.Duration
+		if rule.Lock {
+			if lockDeadline.IsZero() {
+				lockDeadline = time.Now().Add(c.LockRetry.Timeout)
+			}
+			lockDelay = c.LockRetry.nextDelay(lockDelay)
+			if c.LockRetry.Timeout <= 0 || lockDelay <= 0 || time.Now().Add(lockDelay).After(lockDeadline) {
+				return output, err
+			}
+			tflog.Warn(ctx, fmt.Sprintf("Command '%v' was rejected because another plan or apply is in progress, retrying in %v", command, lockDelay))
+			delay = lockDelay
+		} else {
+			if attempt >= policy.MaxAttempts {
+				return output, err
+			}
+			retryDelay = policy.nextDelay(retryDelay)
+			tflog.Warn(ctx, fmt.Sprintf("Command '%v' failed with a retriable error, retrying in %v (attempt %v of %v)", command, retryDelay, attempt+1, policy.MaxAttempts))
+			delay = retryDelay
+		}
+		attempt++
+		c.Metrics.recordRetry(ctx)
+		select {
+		case <-ctx.Done():
+			return output, err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// A rule for deciding whether a failed command can be retried. A rule matches if the command exited
+// with ExitCode (or any code if ExitCode is 0), its output is of Class (or any class if Class is
+// unknown) and its output matches Pattern (or any output if Pattern is nil).
+type commandFailureRule struct {
+	ExitCode  int
+	Class     diagnostics.FailureClass
+	Pattern   *regexp.Regexp
+	Retriable bool
+	// Whether the command was rejected because another plan or apply holds the workspace's lock, so
+	// it's retried as configured by the client's LockRetry rather than its CommandRetry.
+	Lock bool
+}
+
+// Rules for failed commands, checked in order. The first match decides whether the command is
+// retried. Failures that match no rule are not retried, since most commands aren't safe to repeat
+// after an unknown error.
+var commandFailureRules = []commandFailureRule{
+	// Another plan or apply is in progress on the workspace
+	{Pattern: lockErrorRegex, Retriable: true, Lock: true},
+	// Usage errors from the CLI's argument parser
+	{ExitCode: 2, Retriable: false},
+	// Errors that will fail the same way every time
+	{Class: diagnostics.FailureAuthentication, Retriable: false},
+	{Class: diagnostics.FailurePermissionDenied, Retriable: false},
+	{Pattern: regexp.MustCompile(`(?i)(not found|does not exist|doesn't exist|already exists)`), Retriable: false},
+	{Pattern: regexp.MustCompile(`(?i)(invalid argument|invalid value|validation error|ValueError)`), Retriable: false},
+	// Transient network and server failures
+	{Pattern: regexp.MustCompile(`(?i)(connection (reset|refused|aborted)|broken pipe|remote ?disconnected|eof occurred)`), Retriable: true},
+	{Pattern: regexp.MustCompile(`(?i)(timed? ?out|deadline[ _]exceeded|temporary failure in name resolution)`), Retriable: true},
+	{Class: diagnostics.FailureThrottled, Retriable: true},
+	{Pattern: regexp.MustCompile(`(?i)((status|code):? ?(502|503|504)|unavailable)`), Retriable: true},
+}
+
+// Returns the first of commandFailureRules that matches a command that failed with err and output,
+// or false if none does or the command couldn't be started, e.g. because the executable is missing.
+func matchFailureRule(err error, output []byte) (commandFailureRule, bool) {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return commandFailureRule{}, false
+	}
+	class := diagnostics.ClassifyOutput(string(output))
+	for _, rule := range commandFailureRules {
+		if rule.ExitCode != 0 && rule.ExitCode != exitErr.ExitCode() {
+			continue
+		}
+		if rule.Class != diagnostics.FailureUnknown && rule.Class != class {
+			continue
+		}
+		if rule.Pattern != nil && !rule.Pattern.Match(output) {
+			continue
+		}
+		return rule, true
+	}
+	return commandFailureRule{}, false
+}
+
+// Returns true if a command that failed with err and output was rejected because another plan or
+// apply is in progress on the workspace.
+func IsLockError(err error, output []byte) bool {
+	rule, matched := matchFailureRule(err, output)
+	return matched && rule.Lock
+}
+
+// Returns true if a command that failed with err and output may succeed if it is run again, other
+// than once the workspace's lock is released.
+func IsRetriable(err error, output []byte) bool {
+	rule, matched := matchFailureRule(err, output)
+	return matched && rule.Retriable && !rule.Lock
+}
@@ -0,0 +1,285 @@
+package tectonclient
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Returns the error of a command that exited with exitCode.
+func exitError(t *testing.T, exitCode int) error {
+	t.Helper()
+	err := exec.Command("sh", "-c", fmt.Sprintf("exit %v", exitCode)).Run()
+	if _, ok := err.(*exec.ExitError); !ok {
+		t.Fatalf("expected an exit error, got: %v", err)
+	}
+	return err
+}
+
+func TestIsRetriable(t *testing.T) {
+	testCases := map[string]struct {
+		exitCode  int
+		output    string
+		retriable bool
+	}{
+		"connection reset": {
+			exitCode:  1,
+			output:    "requests.exceptions.ConnectionError: ('Connection aborted.', ConnectionResetError(104, 'Connection reset by peer'))",
+			retriable: true,
+		},
+		"timeout": {
+			exitCode:  1,
+			output:    "Read timed out. (read timeout=60)",
+			retriable: true,
+		},
+		"service unavailable": {
+			exitCode:  1,
+			output:    "Error: status code 503: Service Unavailable",
+			retriable: true,
+		},
+		"throttled": {
+			exitCode:  1,
+			output:    "RESOURCE_EXHAUSTED: request was throttled",
+			retriable: true,
+		},
+		"usage error": {
+			exitCode:  2,
+			output:    "Error: No such option: --timeout (connection timed out)",
+			retriable: false,
+		},
+		"unauthenticated": {
+			exitCode:  1,
+			output:    "UNAUTHENTICATED: invalid api key",
+			retriable: false,
+		},
+		"not found": {
+			exitCode:  1,
+			output:    "Workspace 'prod' not found",
+			retriable: false,
+		},
+		"validation error": {
+			exitCode:  1,
+			output:    "ValueError: invalid value for role",
+			retriable: false,
+		},
+		"unknown": {
+			exitCode:  1,
+			output:    "Something went wrong",
+			retriable: false,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := IsRetriable(exitError(t, testCase.exitCode), []byte(testCase.output))
+			if actual != testCase.retriable {
+				t.Errorf("expected %v, got %v", testCase.retriable, actual)
+			}
+		})
+	}
+}
+
+func TestRunRetries(t *testing.T) {
+	retryDelay := commandRetryDelay
+	commandRetryDelay = time.Millisecond
+	t.Cleanup(func() { commandRetryDelay = retryDelay })
+
+	testCases := map[string]struct {
+		output   string
+		attempts int
+	}{
+		"retriable": {
+			output:   "Connection reset by peer",
+			attempts: commandMaxAttempts,
+		},
+		"fatal": {
+			output:   "Permission denied",
+			attempts: 1,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			calls := filepath.Join(t.TempDir(), "calls")
+			fakeTectonCLI(t, "echo \"$@\" >> "+calls+"\necho '"+testCase.output+"'\nexit 1")
+			_, err := Client{}.Run(context.Background(), "workspace", "list")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			data, err := os.ReadFile(calls)
+			if err != nil {
+				t.Fatalf("failed to read calls: %v", err)
+			}
+			attempts := strings.Count(string(data), "workspace list")
+			if attempts != testCase.attempts {
+				t.Errorf("expected %v attempts, got %v", testCase.attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestRunRetryPolicy(t *testing.T) {
+	testCases := map[string]struct {
+		retry    CommandRetry
+		attempts int
+	}{
+		"more attempts": {retry: CommandRetry{MaxAttempts: 4, InitialDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}, attempts: 4},
+		"disabled":      {retry: CommandRetry{MaxAttempts: 1}, attempts: 1},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			calls := filepath.Join(t.TempDir(), "calls")
+			fakeTectonCLI(t, "echo \"$@\" >> "+calls+"\necho 'Connection reset by peer'\nexit 1")
+			_, err := Client{Retry: &testCase.retry}.Run(context.Background(), "access-control", "get-roles")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			data, err := os.ReadFile(calls)
+			if err != nil {
+				t.Fatalf("failed to read calls: %v", err)
+			}
+			attempts := strings.Count(string(data), "get-roles")
+			if attempts != testCase.attempts {
+				t.Errorf("expected %v attempts, got %v", testCase.attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestRunRetryBudget(t *testing.T) {
+	// The command waits for the lock once, then fails with a network error, which gets no new budget
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	fakeTectonCLI(t, "echo \"$@\" >> "+calls+"\nif [ ! -f "+dir+"/locked ]; then touch "+dir+"/locked; echo 'Another apply is in progress'; else echo 'Connection reset by peer'; fi\nexit 1")
+	client := Client{
+		LockRetry: LockRetry{Timeout: time.Minute, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond},
+		Retry:     &CommandRetry{MaxAttempts: 2, InitialDelay: time.Millisecond},
+	}
+	_, err := client.Run(context.Background(), "access-control", "get-roles")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	data, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatalf("failed to read calls: %v", err)
+	}
+	if attempts := strings.Count(string(data), "get-roles"); attempts != 2 {
+		t.Errorf("expected 2 attempts, got %v", attempts)
+	}
+}
+
+func TestCommandRetryNextDelay(t *testing.T) {
+	retry := CommandRetry{MaxAttempts: 5, InitialDelay: time.Second, MaxDelay: 3 * time.Second}
+	var delays []time.Duration
+	var delay time.Duration
+	for range 4 {
+		delay = retry.nextDelay(delay)
+		delays = append(delays, delay)
+	}
+	expected := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}
+	if !slices.Equal(delays, expected) {
+		t.Errorf("expected %v, got %v", expected, delays)
+	}
+}
+
+func TestRunScriptRetries(t *testing.T) {
+	// The fake interpreter fails with a network error the first time it's run
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "failed")
+	interpreter := filepath.Join(dir, "python")
+	err := os.WriteFile(interpreter, []byte("#!/bin/sh\nif [ ! -f "+marker+" ]; then touch "+marker+"; echo 'Connection reset by peer' >&2; exit 1; fi\necho ok\n"), 0o755)
+	if err != nil {
+		t.Fatalf("failed to write fake python: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "tecton"), []byte("#!"+interpreter+"\n"), 0o755)
+	if err != nil {
+		t.Fatalf("failed to write fake tecton CLI: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	retry := CommandRetry{MaxAttempts: 2, InitialDelay: time.Millisecond}
+	output, err := Client{Retry: &retry}.RunScript(context.Background(), "principal_status.py")
+	if err != nil {
+		t.Fatalf("expected success after retry, got: %v", err)
+	}
+	if strings.TrimSpace(string(output)) != "ok" {
+		t.Errorf("expected 'ok', got '%v'", string(output))
+	}
+}
+
+func TestRunSucceedsAfterRetry(t *testing.T) {
+	retryDelay := commandRetryDelay
+	commandRetryDelay = time.Millisecond
+	t.Cleanup(func() { commandRetryDelay = retryDelay })
+
+	marker := filepath.Join(t.TempDir(), "failed")
+	fakeTectonCLI(t, "if [ ! -f "+marker+" ]; then touch "+marker+"; echo 'Service Unavailable'; exit 1; fi\necho ok")
+	output, err := Client{}.Run(context.Background(), "workspace", "list")
+	if err != nil {
+		t.Fatalf("expected success after retry, got: %v", err)
+	}
+	if strings.TrimSpace(string(output)) != "ok" {
+		t.Errorf("expected 'ok', got '%v'", string(output))
+	}
+}
+
+func TestRunLockRetries(t *testing.T) {
+	lockedOutput := "Error: another apply is already in progress on workspace prod"
+	testCases := map[string]struct {
+		retry    LockRetry
+		attempts int
+	}{
+		// Retries after 200ms and 400ms fit in the timeout, but the next one after 400ms doesn't
+		"backoff":  {retry: LockRetry{Timeout: 900 * time.Millisecond, InitialDelay: 200 * time.Millisecond, MaxDelay: 400 * time.Millisecond}, attempts: 3},
+		"disabled": {retry: LockRetry{}, attempts: 1},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			calls := filepath.Join(t.TempDir(), "calls")
+			fakeTectonCLI(t, "echo \"$@\" >> "+calls+"\necho '"+lockedOutput+"'\nexit 1")
+			_, err := Client{LockRetry: testCase.retry}.Run(context.Background(), "access-control", "assign-role")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			data, err := os.ReadFile(calls)
+			if err != nil {
+				t.Fatalf("failed to read calls: %v", err)
+			}
+			attempts := strings.Count(string(data), "assign-role")
+			if attempts != testCase.attempts {
+				t.Errorf("expected %v attempts, got %v", testCase.attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestLockRetryNextDelay(t *testing.T) {
+	retry := LockRetry{InitialDelay: 5 * time.Second, MaxDelay: 15 * time.Second}
+	var delays []time.Duration
+	var delay time.Duration
+	for i := 0; i < 4; i++ {
+		delay = retry.nextDelay(delay)
+		delays = append(delays, delay)
+	}
+	expected := []time.Duration{5 * time.Second, 10 * time.Second, 15 * time.Second, 15 * time.Second}
+	if !slices.Equal(delays, expected) {
+		t.Errorf("expected %v, got %v", expected, delays)
+	}
+}
+
+func TestRunSucceedsAfterLockReleased(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "locked")
+	fakeTectonCLI(t, "if [ ! -f "+marker+" ]; then touch "+marker+"; echo 'Workspace is currently locked by another apply'; exit 1; fi\necho ok")
+	retry := LockRetry{Timeout: time.Minute, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	output, err := Client{LockRetry: retry}.Run(context.Background(), "workspace", "delete", "prod")
+	if err != nil {
+		t.Fatalf("expected success after the lock was released, got: %v", err)
+	}
+	if strings.TrimSpace(string(output)) != "ok" {
+		t.Errorf("expected 'ok', got '%v'", string(output))
+	}
+}
@@ -0,0 +1,172 @@
+package tectonclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Principal is a user or service account that roles are granted to. Exactly one of the IDs is set.
+type Principal struct {
+	UserID           string
+	ServiceAccountID string
+}
+
+func (p Principal) String() string {
+	if p.UserID != "" {
+		return fmt.Sprintf("user '%v'", p.UserID)
+	}
+	return fmt.Sprintf("service '%v'", p.ServiceAccountID)
+}
+
+// Returns the `tecton access-control` arguments that select the principal.
+func (p Principal) args() ([]string, error) {
+	if p.UserID != "" {
+		return []string{"--user", p.UserID}, nil
+	} else if p.ServiceAccountID != "" {
+		return []string{"--service-account", p.ServiceAccountID}, nil
+	}
+	return nil, errors.New("Cannot access roles in Tecton without an ID. This is a bug in the provider.")
+}
+
+// RolesPolicy is the policy for a single workspace (or organization) in the JSON output of
+// `tecton access-control get-roles`.
+type RolesPolicy struct {
+	ResourceType  string `json:"resource_type" required:"true"`
+	WorkspaceName string `json:"workspace_name,omitempty"`
+	// Identify the resource of policies on other resource types, e.g. a secret scope, on newer
+	// clusters. Which of them is set depends on the resource type.
+	ResourceID   string        `json:"resource_id,omitempty"`
+	ResourceName string        `json:"resource_name,omitempty"`
+	RolesGranted []RoleGranted `json:"roles_granted" required:"true"`
+}
+
+// Returns the name of the resource of a policy whose resource type isn't ORGANIZATION or WORKSPACE,
+// or its ID if Tecton doesn't report a name.
+func (p RolesPolicy) Resource() string {
+	if p.ResourceName != "" {
+		return p.ResourceName
+	}
+	return p.ResourceID
+}
+
+// RoleGranted is a single role (e.g. "owner") in the JSON output of `tecton access-control get-roles`.
+type RoleGranted struct {
+	Role              string                 `json:"role" required:"true"`
+	AssignmentSources []RoleAssignmentSource `json:"assignment_sources"`
+}
+
+// Returns true if the role is granted directly to the principal, whether or not it's also inherited
+// through a group. Roles without assignment sources, which older clusters don't report, are direct.
+func (r RoleGranted) IsDirect() bool {
+	if len(r.AssignmentSources) == 0 {
+		return true
+	}
+	for _, source := range r.AssignmentSources {
+		if source.IsDirect() {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleAssignmentSource is an assignment source (e.g. DIRECT) in the JSON output of
+// `tecton access-control get-roles`.
+type RoleAssignmentSource struct {
+	AssignmentType string `json:"assignment_type"`
+	// Set if the role is granted through a principal group.
+	PrincipalGroupID   string `json:"principal_group_id,omitempty"`
+	PrincipalGroupName string `json:"principal_group_name,omitempty"`
+}
+
+// Returns true if the role is granted directly to the principal rather than through a group.
+func (s RoleAssignmentSource) IsDirect() bool {
+	return strings.TrimPrefix(s.AssignmentType, "ASSIGNMENT_TYPE_") == "DIRECT"
+}
+
+// Returns true if the role is granted through a principal group.
+func (s RoleAssignmentSource) IsGroup() bool {
+	return strings.Contains(s.AssignmentType, "GROUP") || s.PrincipalGroupName != "" || s.PrincipalGroupID != ""
+}
+
+// Reads the roles granted to a particular user or service from Tecton.
+func (c Client) GetRoles(ctx context.Context, principal Principal) ([]RolesPolicy, error) {
+	principalArgs, err := principal.args()
+	if err != nil {
+		return nil, err
+	}
+	err = c.RequireCapability(func(c Capabilities) bool { return c.JSONOutput }, "Reading roles with `tecton access-control get-roles --json-out`")
+	if err != nil {
+		return nil, err
+	}
+	tflog.Info(ctx, fmt.Sprintf("Reading roles for '%v'", strings.Join(principalArgs, " ")))
+
+	args := append([]string{"access-control", "get-roles", "--json-out"}, principalArgs...)
+	output, err := c.Run(ctx, args...)
+	if err != nil {
+		return nil, c.CommandError(ctx, fmt.Sprintf("read Tecton roles for %v", principal), args, output, err)
+	}
+
+	// Parse the output
+	var policies []RolesPolicy
+	err = parseJSON("tecton access-control get-roles", output, &policies)
+	if err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// Grants a role to a user or service. If no workspace is provided, the role is granted on all
+// workspaces.
+func (c Client) AssignRole(ctx context.Context, principal Principal, role string, workspace string) error {
+	return c.ModifyRole(ctx, principal, role, workspace, true)
+}
+
+// Revokes a role from a user or service. If no workspace is provided, the role is revoked on all
+// workspaces.
+func (c Client) UnassignRole(ctx context.Context, principal Principal, role string, workspace string) error {
+	return c.ModifyRole(ctx, principal, role, workspace, false)
+}
+
+// Modifies a role in Tecton for a particular user or service. If grant is true, the role will be added. If it is false, the role will be removed.
+// If no workspace is provided, the role will be applied to all workspaces.
+func (c Client) ModifyRole(ctx context.Context, principal Principal, role string, workspace string, grant bool) error {
+	var accessControlSubcommand string
+	if grant {
+		accessControlSubcommand = "assign-role"
+	} else {
+		accessControlSubcommand = "unassign-role"
+	}
+	var args = []string{"access-control", accessControlSubcommand, "--role", role}
+	if workspace != "" {
+		args = append(args, "--workspace", workspace)
+	}
+	principalArgs, err := principal.args()
+	if err != nil {
+		return err
+	}
+	args = append(args, principalArgs...)
+	if c.SkipInDryRun(ctx, "tecton "+ShellJoin(args)) {
+		return nil
+	}
+	tflog.Info(ctx, fmt.Sprintf("Running 'tecton %v'", ShellJoin(args)))
+
+	output, err := c.Run(ctx, args...)
+	if err != nil {
+		return c.CommandError(ctx, fmt.Sprintf("set Tecton role '%v' for %v", role, principal), args, output, err)
+	}
+	change := fmt.Sprintf("Granted role '%v'", role)
+	if !grant {
+		change = fmt.Sprintf("Revoked role '%v'", role)
+	}
+	if workspace == "" && role != "admin" {
+		change += " on all workspaces"
+	} else if workspace != "" {
+		change += fmt.Sprintf(" on workspace '%v'", workspace)
+	}
+	c.Changes.Record(fmt.Sprintf("%v for %v", change, principal))
+	return nil
+}
@@ -0,0 +1,58 @@
+package tectonclient
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGetRoles(t *testing.T) {
+	fakeTectonCLI(t, `echo "[{\"resource_type\": \"WORKSPACE\", \"workspace_name\": \"$5\", \"roles_granted\": [{\"role\": \"owner\"}]}]"`)
+	policies, err := Client{}.GetRoles(context.Background(), Principal{ServiceAccountID: "prod"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(policies) != 1 || policies[0].WorkspaceName != "prod" || policies[0].RolesGranted[0].Role != "owner" {
+		t.Errorf("unexpected policies: %+v", policies)
+	}
+
+	if _, err := (Client{}).GetRoles(context.Background(), Principal{}); err == nil {
+		t.Error("expected an error for a principal without an ID")
+	}
+}
+
+func TestModifyRoleRecordsChanges(t *testing.T) {
+	fakeTectonCLI(t, "exit 0")
+	client, changes := Client{}.RecordingChanges()
+	ctx := context.Background()
+	if err := client.AssignRole(ctx, Principal{UserID: "alice@example.com"}, "owner", "prod"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.UnassignRole(ctx, Principal{ServiceAccountID: "abc123"}, "viewer", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "Granted role 'owner' on workspace 'prod' for user 'alice@example.com'\n" +
+		"Revoked role 'viewer' on all workspaces for service 'abc123'"
+	if strings.Join(changes.Changes(), "\n") != expected {
+		t.Errorf("expected changes:\n%v\ngot:\n%v", expected, strings.Join(changes.Changes(), "\n"))
+	}
+}
+
+func TestModifyRole_dryRun(t *testing.T) {
+	// Any command that's run fails the test
+	fakeTectonCLI(t, "echo 'Command was run' && exit 1")
+	client, changes := Client{DryRun: true}.RecordingChanges()
+	ctx := context.Background()
+	if err := client.AssignRole(ctx, Principal{UserID: "alice@example.com"}, "owner", "prod"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.CreateWorkspace(ctx, "prod", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.CloneWorkspace(ctx, "prod", "staging"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes.Changes()) != 0 {
+		t.Errorf("expected no changes, got: %v", changes.Changes())
+	}
+}
@@ -0,0 +1,80 @@
+package tectonclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+)
+
+// Unmarshals the JSON output of source into v, like json.Unmarshal, and then checks that it has the
+// structure the provider expects, so that a different CLI version is reported instead of acting on a
+// partially unmarshalled struct. Struct fields tagged `required:"true"` must be present, and string
+// fields tagged `enum:"A,B"` must have one of the listed values. Returns a *diagnostics.ParseError if
+// the output isn't valid JSON and a *diagnostics.SchemaError if it doesn't have the expected structure.
+func parseJSON(source string, output []byte, v any) error {
+	err := json.Unmarshal(output, v)
+	if err != nil {
+		return &diagnostics.ParseError{Source: source, Output: string(output), Err: err}
+	}
+	var raw any
+	err = json.Unmarshal(output, &raw)
+	if err != nil {
+		return &diagnostics.ParseError{Source: source, Output: string(output), Err: err}
+	}
+	var problems []string
+	checkSchema(reflect.TypeOf(v).Elem(), raw, "", &problems)
+	if len(problems) > 0 {
+		return &diagnostics.SchemaError{Source: source, Output: string(output), Problems: problems}
+	}
+	return nil
+}
+
+// Checks raw, a value decoded into an interface, against the tags of t and adds a problem for every
+// deviation. path is the location of raw in the output, e.g. "[0].roles_granted".
+func checkSchema(t reflect.Type, raw any, path string, problems *[]string) {
+	if raw == nil {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		checkSchema(t.Elem(), raw, path, problems)
+	case reflect.Slice:
+		elements, ok := raw.([]any)
+		if !ok {
+			return
+		}
+		for i, element := range elements {
+			checkSchema(t.Elem(), element, fmt.Sprintf("%v[%v]", path, i), problems)
+		}
+	case reflect.Struct:
+		object, ok := raw.(map[string]any)
+		if !ok {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			fieldPath := strings.TrimPrefix(path+"."+name, ".")
+			value, present := object[name]
+			if !present {
+				if field.Tag.Get("required") == "true" {
+					*problems = append(*problems, fmt.Sprintf("%v is missing", fieldPath))
+				}
+				continue
+			}
+			if enum := field.Tag.Get("enum"); enum != "" {
+				if s, ok := value.(string); ok && !slices.Contains(strings.Split(enum, ","), s) {
+					*problems = append(*problems, fmt.Sprintf("%v has the unknown value '%v', expected one of (%v)", fieldPath, s, enum))
+				}
+			}
+			checkSchema(field.Type, value, fieldPath, problems)
+		}
+	}
+}
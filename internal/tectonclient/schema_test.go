@@ -0,0 +1,61 @@
+package tectonclient
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+)
+
+func TestParseJSON(t *testing.T) {
+	testCases := map[string]struct {
+		output   string
+		problems []string
+		isParse  bool
+	}{
+		"valid": {
+			output: `[{"resource_type": "WORKSPACE", "workspace_name": "prod", "roles_granted": [{"role": "owner"}]}]`,
+		},
+		"missing required fields": {
+			output:   `[{"workspace_name": "prod", "roles_granted": [{"name": "owner"}]}]`,
+			problems: []string{"[0].resource_type is missing", "[0].roles_granted[0].role is missing"},
+		},
+		"not JSON": {
+			output:  `Error: something broke`,
+			isParse: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var policies []RolesPolicy
+			err := parseJSON("tecton access-control get-roles", []byte(testCase.output), &policies)
+			var parseErr *diagnostics.ParseError
+			if errors.As(err, &parseErr) != testCase.isParse {
+				t.Fatalf("expected a parse error: %v, got: %v", testCase.isParse, err)
+			}
+			var schemaErr *diagnostics.SchemaError
+			if errors.As(err, &schemaErr) {
+				if strings.Join(schemaErr.Problems, "\n") != strings.Join(testCase.problems, "\n") {
+					t.Errorf("expected problems:\n%v\ngot:\n%v", strings.Join(testCase.problems, "\n"), strings.Join(schemaErr.Problems, "\n"))
+				}
+			} else if len(testCase.problems) > 0 {
+				t.Errorf("expected a schema error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseJSON_enum(t *testing.T) {
+	var environment *PythonEnvironment
+	err := parseJSON("python_environment.py", []byte(`{"id": "abc", "name": "rift", "status": "EXPLODED"}`), &environment)
+	var schemaErr *diagnostics.SchemaError
+	if !errors.As(err, &schemaErr) || !strings.Contains(schemaErr.Problems[0], "status has the unknown value 'EXPLODED'") {
+		t.Errorf("expected an unknown enum value error, got: %v", err)
+	}
+
+	err = parseJSON("python_environment.py", []byte(`null`), &environment)
+	if err != nil || environment != nil {
+		t.Errorf("expected null to be accepted, got: %v, %v", environment, err)
+	}
+}
@@ -0,0 +1,116 @@
+package tectonclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Python scripts that use the Tecton SDK for data the `tecton` CLI doesn't expose. Each script
+// prints a single JSON document to stdout.
+//
+//go:embed scripts/*.py
+var scripts embed.FS
+
+// Runs one of the embedded Python scripts with the interpreter the tecton CLI is installed with, so
+// that the Tecton SDK is importable. Returns the script's stdout.
+func (c Client) RunScript(ctx context.Context, script string, args ...string) ([]byte, error) {
+	return c.RunScriptWithInput(ctx, nil, script, args...)
+}
+
+// Like RunScript, but passes input to the script's stdin, e.g. for secrets that must not appear in
+// its arguments.
+func (c Client) RunScriptWithInput(ctx context.Context, input []byte, script string, args ...string) ([]byte, error) {
+	source, err := scripts.ReadFile("scripts/" + script)
+	if err != nil {
+		return nil, fmt.Errorf("Script '%v' does not exist. This is a bug in the provider.", script)
+	}
+	err = c.requireInternalAPI(script, source)
+	if err != nil {
+		return nil, err
+	}
+	interpreter, err := tectonPythonInterpreter()
+	if err != nil {
+		return nil, err
+	}
+
+	// `python -c` sets sys.argv[0] to "-c", so the script's own arguments still start at sys.argv[1].
+	// Failures are classified by the script's stderr, e.g. a network error raised by the SDK.
+	var stdout, stderr bytes.Buffer
+	_, err = c.retry(ctx, strings.TrimSpace(script+" "+ShellJoin(args)), func() ([]byte, error) {
+		stdout.Reset()
+		stderr.Reset()
+		cmdCtx, cancel := c.commandContext(ctx)
+		defer cancel()
+		cmd := exec.CommandContext(cmdCtx, interpreter, append([]string{"-c", string(source)}, args...)...)
+		cmd.Env = c.commandEnv(ctx)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if input != nil {
+			cmd.Stdin = bytes.NewReader(input)
+		}
+		cmd.WaitDelay = commandWaitDelay
+		tflog.Debug(ctx, fmt.Sprintf("Running script '%v %v'", script, ShellJoin(args)))
+		start := time.Now()
+		err := cmd.Run()
+		c.Metrics.recordCommand(ctx, time.Since(start))
+		err = c.timeoutError(cmdCtx, err)
+		c.recordWarnings(ctx, script, stderr.Bytes())
+		return stderr.Bytes(), err
+	})
+	if err != nil && promptAbortedRegex.Match(stderr.Bytes()) {
+		// The script's stdin is empty, so a prompt, e.g. from the SDK's login, fails immediately
+		err = waitingForInputError()
+	}
+	if err != nil {
+		return nil, c.commandError(ctx, fmt.Sprintf("run script '%v'", script), strings.TrimSpace(script+" "+ShellJoin(args)), stderr.Bytes(), err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// Returns the Python interpreter from the shebang line of the `tecton` executable, falling back to
+// `python3` if the executable isn't a Python entrypoint script.
+func tectonPythonInterpreter() (string, error) {
+	tectonPath, err := exec.LookPath("tecton")
+	if err != nil {
+		return "", fmt.Errorf("Didn't find 'tecton' executable: %v", err.Error())
+	}
+	file, err := os.Open(tectonPath)
+	if err != nil {
+		return "", fmt.Errorf("Failed to open '%v': %v", tectonPath, err.Error())
+	}
+	defer file.Close()
+
+	firstLine, _ := bufio.NewReader(file).ReadString('\n')
+	if interpreter := parseShebang(firstLine); interpreter != "" {
+		return interpreter, nil
+	}
+	return exec.LookPath("python3")
+}
+
+// Returns the interpreter from a Python shebang line, or "" if the line isn't one.
+func parseShebang(line string) string {
+	if !strings.HasPrefix(line, "#!") {
+		return ""
+	}
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+	// Handle `#!/usr/bin/env python3`
+	if strings.HasSuffix(fields[0], "/env") && len(fields) > 1 {
+		fields = fields[1:]
+	}
+	if !strings.Contains(fields[0], "python") {
+		return ""
+	}
+	return fields[0]
+}
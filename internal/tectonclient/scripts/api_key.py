@@ -0,0 +1,25 @@
+# Creates or deletes a Tecton API key. `create` prints the new key and its ID as JSON; `delete`
+# prints an empty JSON object.
+#
+# Usage: python api_key.py create <description>
+#        python api_key.py delete <API key ID>
+import json
+import sys
+
+from tecton._internals import metadata_service
+from tecton_core.id_helper import IdHelper
+from tecton_proto.metadataservice import metadata_service_pb2
+
+action, value = sys.argv[1], sys.argv[2]
+
+if action == "create":
+    request = metadata_service_pb2.CreateApiKeyRequest(description=value, is_admin=False)
+    response = metadata_service.instance().CreateApiKey(request)
+    json.dump({"id": IdHelper.to_string(response.id), "key": response.key}, sys.stdout)
+elif action == "delete":
+    request = metadata_service_pb2.DeleteApiKeyRequest()
+    request.id.CopyFrom(IdHelper.from_string(value))
+    metadata_service.instance().DeleteApiKey(request)
+    json.dump({}, sys.stdout)
+else:
+    sys.exit(f"Unknown action '{action}'")
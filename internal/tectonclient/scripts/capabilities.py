@@ -0,0 +1,52 @@
+# Prints a JSON object describing the optional features supported by the installed tecton CLI and
+# SDK, so that the provider can fail clearly instead of with a cryptic CLI error. The internal
+# modules some scripts use are imported here, so that a missing module is reported as a capability.
+#
+# Usage: python capabilities.py
+import json
+import sys
+from importlib import metadata
+
+import click
+from tecton.cli import cli as cli_module
+
+try:
+    from tecton._internals import metadata_service  # noqa: F401
+    from tecton_proto.auth import principal_pb2
+    from tecton_proto.metadataservice import metadata_service_pb2  # noqa: F401
+except ImportError:
+    principal_pb2 = None
+
+try:
+    version = metadata.version("tecton")
+except metadata.PackageNotFoundError:
+    version = ""
+
+
+def subcommand(group, name):
+    if not isinstance(group, click.Group):
+        return None
+    return group.commands.get(name)
+
+
+def has_option(command, option):
+    if command is None:
+        return False
+    return any(option in getattr(param, "opts", []) for param in command.params)
+
+
+cli = getattr(cli_module, "cli", None)
+get_roles = subcommand(subcommand(cli, "access-control"), "get-roles")
+
+json.dump(
+    {
+        "json_output": has_option(get_roles, "--json-out"),
+        "groups": principal_pb2 is not None and "PRINCIPAL_TYPE_GROUP" in principal_pb2.PrincipalType.keys(),
+        "secrets": subcommand(cli, "secrets") is not None,
+        "server_groups": subcommand(cli, "server-group") is not None,
+        "environments": subcommand(cli, "environment") is not None,
+        "internal_api": principal_pb2 is not None,
+        "version": version,
+    },
+    sys.stdout,
+)
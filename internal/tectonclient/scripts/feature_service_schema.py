@@ -0,0 +1,68 @@
+# Prints a JSON description of the join keys and request context keys that a feature service's
+# online requests must provide, from the feature server's metadata endpoint. The endpoint is called
+# with the API key in TECTON_API_KEY, so the session of `tecton login` and OAuth access tokens aren't
+# supported.
+#
+# Usage: python feature_service_schema.py <workspace> <feature service>
+import json
+import os
+import ssl
+import sys
+import urllib.request
+
+
+def urlopen(request):
+    # The provider's `ca_bundle_path` and `https_proxy` are passed explicitly rather than left to
+    # urllib's defaults, so that they apply the same way as to the requests sessions of the SDK.
+    ca_bundle = os.environ.get("REQUESTS_CA_BUNDLE")
+    context = ssl.create_default_context(cafile=ca_bundle) if ca_bundle else None
+    handlers = [urllib.request.HTTPSHandler(context=context)]
+    proxy = os.environ.get("HTTPS_PROXY") or os.environ.get("https_proxy")
+    if proxy:
+        handlers.append(urllib.request.ProxyHandler({"https": proxy}))
+    return urllib.request.build_opener(*handlers).open(request)
+
+
+workspace_name, feature_service_name = sys.argv[1], sys.argv[2]
+api_key = os.environ.get("TECTON_API_KEY")
+if not api_key:
+    sys.exit("Reading a feature service's schema requires an API key, but TECTON_API_KEY isn't set.")
+
+request = urllib.request.Request(
+    os.environ["API_SERVICE"] + "/v1/feature-service/metadata",
+    data=json.dumps(
+        {"params": {"workspace_name": workspace_name, "feature_service_name": feature_service_name}}
+    ).encode(),
+    headers={
+        "Authorization": "Tecton-key " + api_key,
+        "Content-Type": "application/json",
+        "X-Correlation-ID": os.environ.get("TECTON_CORRELATION_ID", ""),
+    },
+    method="POST",
+)
+with urlopen(request) as response:
+    metadata = json.load(response)
+
+
+def type_name(data_type):
+    # e.g. {"type": "array", "elementType": {"type": "float64"}} is "array<float64>"
+    if "elementType" in data_type:
+        return "%s<%s>" % (data_type.get("type", ""), type_name(data_type["elementType"]))
+    return data_type.get("type", "")
+
+
+def fields(key):
+    # Depending on the cluster version, the type is under either "dataType" or "type"
+    return [
+        {"name": field["name"], "type": type_name(field.get("dataType") or field.get("type") or {})}
+        for field in metadata.get(key, [])
+    ]
+
+
+json.dump(
+    {
+        "join_keys": fields("inputJoinKeys"),
+        "request_context": fields("inputRequestContextKeys"),
+    },
+    sys.stdout,
+)
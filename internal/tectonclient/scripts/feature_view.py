@@ -0,0 +1,53 @@
+# Prints a JSON description of a feature view: its metadata, features and materialization settings.
+# Durations are in seconds, and are 0 if they don't apply to the feature view, e.g. the batch
+# schedule of an on-demand feature view.
+#
+# Usage: python feature_view.py <workspace> <feature view>
+import json
+import sys
+from datetime import timezone
+
+import tecton
+
+workspace_name, feature_view_name = sys.argv[1], sys.argv[2]
+
+feature_view = tecton.get_workspace(workspace_name).get_feature_view(feature_view_name)
+definition = feature_view._feature_definition
+
+
+def seconds(duration):
+    return int(duration.total_seconds()) if duration else 0
+
+
+def timestamp(value):
+    if value is None:
+        return ""
+    if value.tzinfo is None:
+        value = value.replace(tzinfo=timezone.utc)
+    return value.astimezone(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ")
+
+
+# Aggregate features aren't in the view schema, so their types come from the materialization schema
+column_types = {}
+for schema in (getattr(definition, "materialization_schema", None), getattr(definition, "view_schema", None)):
+    if schema is not None:
+        column_types.update({name: str(data_type) for name, data_type in schema.column_name_and_data_types()})
+
+json.dump(
+    {
+        "id": feature_view.id,
+        "name": feature_view.name,
+        "description": feature_view.description or "",
+        "owner": feature_view.owner or "",
+        "tags": dict(feature_view.tags or {}),
+        "join_keys": list(feature_view.join_keys),
+        "timestamp_field": getattr(definition, "timestamp_key", None) or "",
+        "features": [{"name": name, "type": column_types.get(name, "")} for name in definition.features],
+        "online": bool(getattr(definition, "writes_to_online_store", False)),
+        "offline": bool(getattr(definition, "writes_to_offline_store", False)),
+        "feature_start_time": timestamp(getattr(feature_view, "feature_start_time", None)),
+        "batch_schedule_seconds": seconds(getattr(feature_view, "batch_schedule", None)),
+        "ttl_seconds": seconds(getattr(definition, "serving_ttl", None)),
+    },
+    sys.stdout,
+)
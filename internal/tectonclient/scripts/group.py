@@ -0,0 +1,31 @@
+# Prints a JSON object describing the principal group with the given name, or null if there is none.
+#
+# Usage: python group.py <group name>
+import json
+import sys
+
+from tecton._internals import metadata_service
+from tecton_proto.principal import principal_service_pb2
+
+name = sys.argv[1]
+
+request = principal_service_pb2.ListPrincipalGroupsRequest()
+response = metadata_service.instance().ListPrincipalGroups(request)
+
+group = None
+for principal_group in response.principal_groups:
+    if principal_group.name != name:
+        continue
+    group = {
+        "id": principal_group.id,
+        "name": principal_group.name,
+        "description": principal_group.description,
+        "created_by": principal_group.created_by.user.login_email
+        if principal_group.created_by.HasField("user")
+        else principal_group.created_by.service_account.id,
+        "created_at": principal_group.created_at.ToJsonString() if principal_group.HasField("created_at") else "",
+        "idp_mapping_names": sorted(principal_group.idp_mapping_names),
+    }
+    break
+
+json.dump(group, sys.stdout)
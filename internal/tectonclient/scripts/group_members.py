@@ -0,0 +1,30 @@
+# Prints a JSON object listing the users and service accounts that are members of a principal group.
+#
+# Usage: python group_members.py <group ID>
+import json
+import sys
+
+from tecton._internals import metadata_service
+from tecton_proto.principal import principal_service_pb2
+
+group_id = sys.argv[1]
+
+request = principal_service_pb2.ListPrincipalGroupMembersRequest(id=group_id)
+response = metadata_service.instance().ListPrincipalGroupMembers(request)
+
+users = set()
+service_accounts = set()
+for member in response.members:
+    principal = member.principal
+    if principal.HasField("user"):
+        users.add(principal.user.login_email)
+    elif principal.HasField("service_account"):
+        service_accounts.add(principal.service_account.id)
+
+json.dump(
+    {
+        "users": sorted(users),
+        "service_accounts": sorted(service_accounts),
+    },
+    sys.stdout,
+)
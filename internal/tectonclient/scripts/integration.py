@@ -0,0 +1,48 @@
+# Manages the secret scope that holds the connection settings of a data platform integration, e.g.
+# a Snowflake account. Every setting is stored as a secret in the scope, so that data sources can
+# reference it with `Secret(scope=..., key=...)`.
+#
+# Usage: python integration.py read <scope>
+#            Prints {"exists": ..., "keys": [...]}.
+#        python integration.py apply <scope>
+#            Reads {"values": {<key>: <value>}, "delete_keys": [...]} from stdin, creates the scope if
+#            it doesn't exist, puts the values and deletes the other keys. Prints {}.
+#        python integration.py delete <scope>
+#            Deletes the scope and every secret in it. Prints {}.
+import json
+import sys
+
+from tecton._internals import metadata_service
+from tecton_proto.secrets import secrets_service_pb2
+
+action, scope = sys.argv[1], sys.argv[2]
+service = metadata_service.instance()
+
+
+def scope_exists():
+    response = service.ListSecretScopes(secrets_service_pb2.ListSecretScopesRequest())
+    return any(existing.name == scope for existing in response.scopes)
+
+
+def list_keys():
+    response = service.ListSecrets(secrets_service_pb2.ListSecretsRequest(scope=scope))
+    return sorted(secret.name for secret in response.keys)
+
+
+if action == "read":
+    exists = scope_exists()
+    json.dump({"exists": exists, "keys": list_keys() if exists else []}, sys.stdout)
+elif action == "apply":
+    request = json.load(sys.stdin)
+    if not scope_exists():
+        service.CreateSecretScope(secrets_service_pb2.CreateSecretScopeRequest(scope=scope))
+    for key, value in sorted(request["values"].items()):
+        service.PutSecretValue(secrets_service_pb2.PutSecretValueRequest(scope=scope, key=key, value=value))
+    for key in request["delete_keys"]:
+        service.DeleteSecret(secrets_service_pb2.DeleteSecretRequest(scope=scope, key=key))
+    json.dump({}, sys.stdout)
+elif action == "delete":
+    service.DeleteSecretScope(secrets_service_pb2.DeleteSecretScopeRequest(scope=scope))
+    json.dump({}, sys.stdout)
+else:
+    sys.exit(f"Unknown action '{action}'")
@@ -0,0 +1,39 @@
+# Prints a JSON list of the users and service accounts that have the admin role on the organization,
+# whether it was assigned directly or through a group.
+#
+# Usage: python list_admins.py
+import json
+import sys
+
+from tecton._internals import metadata_service
+from tecton_proto.auth import authorization_service_pb2
+from tecton_proto.auth import principal_pb2
+from tecton_proto.auth import resource_pb2
+
+request = authorization_service_pb2.GetAssignedPrincipalsRequest(
+    resource_type=resource_pb2.ResourceType.RESOURCE_TYPE_ORGANIZATION,
+    principal_types=[
+        principal_pb2.PrincipalType.PRINCIPAL_TYPE_USER,
+        principal_pb2.PrincipalType.PRINCIPAL_TYPE_SERVICE_ACCOUNT,
+    ],
+)
+response = metadata_service.instance().GetAssignedPrincipals(request)
+
+users = set()
+service_accounts = set()
+for assignment in response.assignments:
+    if not any(role_assignment.role == "admin" for role_assignment in assignment.role_assignments):
+        continue
+    principal = assignment.principal
+    if principal.HasField("user"):
+        users.add(principal.user.login_email)
+    elif principal.HasField("service_account"):
+        service_accounts.add(principal.service_account.id)
+
+json.dump(
+    {
+        "users": sorted(users),
+        "service_accounts": sorted(service_accounts),
+    },
+    sys.stdout,
+)
@@ -0,0 +1,52 @@
+# Prints a JSON list of the users and service accounts that have roles assigned directly (rather
+# than through a group) on the organization or on any of the given workspaces. The `tecton` CLI can
+# only read the roles of a principal that is already known.
+#
+# Usage: python list_principals.py [<workspace> ...]
+import json
+import sys
+
+from tecton._internals import metadata_service
+from tecton_proto.auth import authorization_service_pb2
+from tecton_proto.auth import principal_pb2
+from tecton_proto.auth import resource_pb2
+
+PRINCIPAL_TYPES = [
+    principal_pb2.PrincipalType.PRINCIPAL_TYPE_USER,
+    principal_pb2.PrincipalType.PRINCIPAL_TYPE_SERVICE_ACCOUNT,
+]
+
+resources = [(resource_pb2.ResourceType.RESOURCE_TYPE_ORGANIZATION, None)]
+resources += [(resource_pb2.ResourceType.RESOURCE_TYPE_WORKSPACE, name) for name in sys.argv[1:]]
+
+users = set()
+service_accounts = set()
+for resource_type, resource_id in resources:
+    request = authorization_service_pb2.GetAssignedPrincipalsRequest(
+        resource_type=resource_type,
+        principal_types=PRINCIPAL_TYPES,
+    )
+    if resource_id is not None:
+        request.resource_id = resource_id
+    response = metadata_service.instance().GetAssignedPrincipals(request)
+    for assignment in response.assignments:
+        is_direct = any(
+            source.assignment_type == authorization_service_pb2.AssignmentType.ASSIGNMENT_TYPE_DIRECT
+            for role_assignment in assignment.role_assignments
+            for source in role_assignment.assignment_sources
+        )
+        if not is_direct:
+            continue
+        principal = assignment.principal
+        if principal.HasField("user"):
+            users.add(principal.user.login_email)
+        elif principal.HasField("service_account"):
+            service_accounts.add(principal.service_account.id)
+
+json.dump(
+    {
+        "users": sorted(users),
+        "service_accounts": sorted(service_accounts),
+    },
+    sys.stdout,
+)
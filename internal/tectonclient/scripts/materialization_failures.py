@@ -0,0 +1,57 @@
+# Prints a JSON summary of the failed materialization jobs of each feature view in a workspace that
+# were created at or after a given time.
+#
+# Usage: python materialization_failures.py <workspace> <since, as an ISO 8601 timestamp>
+import json
+import sys
+from datetime import datetime, timezone
+
+import tecton
+
+FAILED_JOB_STATES = ("ERROR", "FAIL")
+
+
+def as_utc(timestamp):
+    if timestamp.tzinfo is None:
+        return timestamp.replace(tzinfo=timezone.utc)
+    return timestamp.astimezone(timezone.utc)
+
+
+workspace = tecton.get_workspace(sys.argv[1])
+since = as_utc(datetime.fromisoformat(sys.argv[2].replace("Z", "+00:00")))
+
+feature_views = []
+for name in sorted(workspace.list_feature_views()):
+    try:
+        jobs = workspace.get_feature_view(name).list_materialization_jobs()
+    except Exception:
+        # Feature views without materialization (e.g. on-demand feature views) have no jobs.
+        continue
+
+    failed_jobs = []
+    for job in jobs:
+        state = str(job.state).upper()
+        created_at = getattr(job, "created_at", None)
+        if created_at is None or as_utc(created_at) < since:
+            continue
+        if any(failed_state in state for failed_state in FAILED_JOB_STATES):
+            failed_jobs.append((as_utc(created_at), job, state))
+    if not failed_jobs:
+        continue
+
+    created_at, job, state = max(failed_jobs, key=lambda failed_job: failed_job[0])
+    attempts = getattr(job, "attempts", None) or []
+    feature_views.append(
+        {
+            "name": name,
+            "failed_job_count": len(failed_jobs),
+            "last_failure": {
+                "id": job.id,
+                "state": state,
+                "created_at": created_at.strftime("%Y-%m-%dT%H:%M:%SZ"),
+                "run_url": getattr(attempts[-1], "run_url", "") if attempts else "",
+            },
+        }
+    )
+
+json.dump({"feature_views": feature_views}, sys.stdout)
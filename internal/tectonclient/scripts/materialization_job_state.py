@@ -0,0 +1,14 @@
+# Prints the state of a materialization job of a feature view as JSON.
+#
+# Usage: python materialization_job_state.py <workspace> <feature view> <job id>
+import json
+import sys
+
+import tecton
+
+workspace, feature_view_name, job_id = sys.argv[1:4]
+
+feature_view = tecton.get_workspace(workspace).get_feature_view(feature_view_name)
+job = feature_view.get_materialization_job(job_id)
+
+json.dump({"id": job.id, "state": str(job.state).upper()}, sys.stdout)
@@ -0,0 +1,31 @@
+# Prints a JSON object with the rules the cluster validates names with, so that the provider can
+# validate names at plan time the same way. Rules the cluster doesn't report have an empty pattern and
+# a max_length of 0, in which case only the provider's own validation applies.
+#
+# Usage: python name_rules.py
+import json
+import sys
+
+from tecton._internals import metadata_service
+from tecton_proto.metadataservice import metadata_service_pb2
+
+response = metadata_service.instance().GetConfigs(metadata_service_pb2.GetConfigsRequest())
+configs = dict(response.key_values)
+
+
+def rule(prefix):
+    max_length = configs.get(f"{prefix}_MAX_LENGTH", "")
+    return {
+        "pattern": configs.get(f"{prefix}_PATTERN", ""),
+        "max_length": int(max_length) if max_length.isdigit() else 0,
+    }
+
+
+json.dump(
+    {
+        "workspace_name": rule("WORKSPACE_NAME"),
+        "user_id": rule("USER_ID"),
+        "service_account_id": rule("SERVICE_ACCOUNT_ID"),
+    },
+    sys.stdout,
+)
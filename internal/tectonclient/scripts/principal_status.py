@@ -0,0 +1,48 @@
+# Prints a JSON object describing whether a user or service account exists and is active, so that
+# the provider can warn about roles granted to deactivated accounts. `active` is null if Tecton
+# doesn't report whether the account is active. For service accounts, `key_expires_at` is when the
+# account's API key expires as an RFC 3339 timestamp, or null if it doesn't expire or Tecton doesn't
+# report it.
+#
+# Usage: python principal_status.py user <email>
+#        python principal_status.py service <service account ID>
+import json
+import sys
+
+from tecton._internals import metadata_service
+from tecton_proto.metadataservice import metadata_service_pb2
+
+kind, principal_id = sys.argv[1], sys.argv[2]
+
+status = {"found": False, "active": None, "status": "", "key_expires_at": None}
+if kind == "service":
+    request = metadata_service_pb2.GetServiceAccountsRequest(ids=[principal_id])
+    response = metadata_service.instance().GetServiceAccounts(request)
+    for account in response.service_accounts:
+        if account.id == principal_id:
+            # Older clusters don't have key expirations, so the field may not exist
+            expiration = getattr(account, "expiration", None)
+            status = {
+                "found": True,
+                "active": account.is_active,
+                "status": "ACTIVE" if account.is_active else "INACTIVE",
+                "key_expires_at": expiration.ToJsonString() if expiration is not None and account.HasField("expiration") else None,
+            }
+else:
+    request = metadata_service_pb2.GetUserRequest(email=principal_id)
+    try:
+        response = metadata_service.instance().GetUser(request)
+    except Exception as e:
+        if "not found" not in str(e).lower():
+            raise
+    else:
+        # Users are deactivated in the identity provider, which Tecton reports as the Okta status
+        okta_status = getattr(response.user, "okta_status", "")
+        status = {
+            "found": True,
+            "active": okta_status == "ACTIVE" if okta_status else None,
+            "status": okta_status,
+            "key_expires_at": None,
+        }
+
+json.dump(status, sys.stdout)
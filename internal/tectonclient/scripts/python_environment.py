@@ -0,0 +1,57 @@
+# Manages a custom Python environment, in which realtime feature views and Rift jobs run.
+#
+# Usage: python python_environment.py create <name> <description>
+#            Reads the requirements, in the format of a requirements.txt file, from stdin and creates
+#            the environment. Prints the environment.
+#        python python_environment.py get <id>
+#            Prints the environment, or null if it doesn't exist.
+#        python python_environment.py delete <id>
+#            Deletes the environment. Prints {}.
+#
+# An environment is printed as {"id": ..., "name": ..., "description": ..., "status": ...,
+# "status_details": ..., "requirements": ..., "resolved_requirements": ...}, where status is e.g.
+# "PENDING", "READY" or "ERROR".
+import json
+import sys
+
+from tecton._internals import metadata_service
+from tecton_proto.data import remote_compute_environment_pb2
+from tecton_proto.remoteenvironmentservice import remote_environment_service_pb2
+
+action = sys.argv[1]
+service = metadata_service.instance()
+
+STATUS_PREFIX = "REMOTE_ENVIRONMENT_STATUS_"
+
+
+def to_json(environment):
+    status = remote_compute_environment_pb2.RemoteEnvironmentStatus.Name(environment.status)
+    return {
+        "id": environment.id,
+        "name": environment.name,
+        "description": environment.description,
+        "status": status[len(STATUS_PREFIX) :] if status.startswith(STATUS_PREFIX) else status,
+        "status_details": getattr(environment, "status_details", ""),
+        "requirements": getattr(environment, "requirements", ""),
+        "resolved_requirements": getattr(environment, "resolved_requirements", ""),
+    }
+
+
+if action == "create":
+    name, description = sys.argv[2], sys.argv[3]
+    request = remote_environment_service_pb2.CreateRemoteEnvironmentRequest(
+        name=name, description=description, requirements=sys.stdin.read()
+    )
+    response = service.CreateRemoteEnvironment(request)
+    json.dump(to_json(response.remote_environment), sys.stdout)
+elif action == "get":
+    environment_id = sys.argv[2]
+    response = service.ListRemoteEnvironments(remote_environment_service_pb2.ListRemoteEnvironmentsRequest())
+    environment = next((e for e in response.remote_environments if e.id == environment_id), None)
+    json.dump(to_json(environment) if environment is not None else None, sys.stdout)
+elif action == "delete":
+    environment_id = sys.argv[2]
+    service.DeleteRemoteEnvironments(remote_environment_service_pb2.DeleteRemoteEnvironmentsRequest(ids=[environment_id]))
+    json.dump({}, sys.stdout)
+else:
+    sys.exit(f"Unknown action '{action}'")
@@ -0,0 +1,27 @@
+# Triggers a materialization job for a feature view and prints its ID as JSON.
+#
+# Usage: python trigger_materialization_job.py <workspace> <feature view> <start time> <end time> <online> <offline> <overwrite>
+# where the times are RFC 3339 timestamps and the flags are "true" or "false".
+import json
+import sys
+from datetime import datetime
+
+import tecton
+
+workspace, feature_view_name, start_time, end_time, online, offline, overwrite = sys.argv[1:8]
+
+
+def parse_time(value):
+    return datetime.fromisoformat(value.replace("Z", "+00:00"))
+
+
+feature_view = tecton.get_workspace(workspace).get_feature_view(feature_view_name)
+job_id = feature_view.trigger_materialization_job(
+    start_time=parse_time(start_time),
+    end_time=parse_time(end_time),
+    online=online == "true",
+    offline=offline == "true",
+    overwrite=overwrite == "true",
+)
+
+json.dump({"id": job_id}, sys.stdout)
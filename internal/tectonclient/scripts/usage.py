@@ -0,0 +1,139 @@
+# Prints a JSON summary of the materialization jobs of each feature view and the online requests of
+# each feature service in the given workspaces since a given time, for usage and cost reporting.
+# Compute hours are the run times of the jobs' attempts within the window. Request counts are null
+# if the installed SDK doesn't report serving metrics. The feature views each feature service serves
+# are read from the feature server's metadata endpoint, and are empty if the feature service isn't
+# served. The endpoint is called with the API key in TECTON_API_KEY, so the session of `tecton login`
+# and OAuth access tokens aren't supported.
+#
+# Usage: python usage.py <since, as an ISO 8601 timestamp> <workspace>...
+import json
+import os
+import ssl
+import sys
+import urllib.error
+import urllib.request
+from datetime import datetime, timezone
+
+import tecton
+
+# Job attempts in these states are still running, so their run time is counted up to now.
+RUNNING_STATES = ("PENDING", "RUNNING")
+
+
+def as_utc(timestamp):
+    if timestamp.tzinfo is None:
+        return timestamp.replace(tzinfo=timezone.utc)
+    return timestamp.astimezone(timezone.utc)
+
+
+def attempt_hours(attempt, since, now):
+    started_at = getattr(attempt, "created_at", None)
+    if started_at is None:
+        return 0.0
+    ended_at = getattr(attempt, "updated_at", None)
+    if ended_at is None or any(state in str(getattr(attempt, "state", "")).upper() for state in RUNNING_STATES):
+        ended_at = now
+    started_at, ended_at = max(as_utc(started_at), since), min(as_utc(ended_at), now)
+    return max((ended_at - started_at).total_seconds(), 0.0) / 3600
+
+
+def serving_request_count(feature_service, since, now):
+    get_metrics = getattr(feature_service, "get_serving_metrics", None)
+    if get_metrics is None:
+        return None
+    try:
+        metrics = get_metrics(start_time=since, end_time=now)
+    except Exception:
+        return None
+    count = getattr(metrics, "request_count", None)
+    return int(count) if count is not None else None
+
+
+def urlopen(request):
+    # The provider's `ca_bundle_path` and `https_proxy` are passed explicitly rather than left to
+    # urllib's defaults, so that they apply the same way as to the requests sessions of the SDK.
+    ca_bundle = os.environ.get("REQUESTS_CA_BUNDLE")
+    context = ssl.create_default_context(cafile=ca_bundle) if ca_bundle else None
+    handlers = [urllib.request.HTTPSHandler(context=context)]
+    proxy = os.environ.get("HTTPS_PROXY") or os.environ.get("https_proxy")
+    if proxy:
+        handlers.append(urllib.request.ProxyHandler({"https": proxy}))
+    return urllib.request.build_opener(*handlers).open(request)
+
+
+def served_feature_views(workspace_name, feature_service_name):
+    request = urllib.request.Request(
+        os.environ["API_SERVICE"] + "/v1/feature-service/metadata",
+        data=json.dumps(
+            {"params": {"workspace_name": workspace_name, "feature_service_name": feature_service_name}}
+        ).encode(),
+        headers={
+            "Authorization": "Tecton-key " + api_key,
+            "Content-Type": "application/json",
+            "X-Correlation-ID": os.environ.get("TECTON_CORRELATION_ID", ""),
+        },
+        method="POST",
+    )
+    try:
+        with urlopen(request) as response:
+            metadata = json.load(response)
+    except urllib.error.HTTPError as e:
+        # An invalid API key would otherwise look like feature services that serve nothing.
+        if e.code in (401, 403):
+            raise
+        return []
+    # Feature values are named "<feature view>.<feature>"
+    return sorted({value["name"].split(".", 1)[0] for value in metadata.get("featureValues", []) if "." in value.get("name", "")})
+
+
+api_key = os.environ.get("TECTON_API_KEY")
+if not api_key:
+    sys.exit("Reading the feature views that feature services serve requires an API key, but TECTON_API_KEY isn't set.")
+
+since = as_utc(datetime.fromisoformat(sys.argv[1].replace("Z", "+00:00")))
+now = datetime.now(timezone.utc)
+
+workspaces = []
+for workspace_name in sys.argv[2:]:
+    workspace = tecton.get_workspace(workspace_name)
+
+    feature_views = []
+    for name in sorted(workspace.list_feature_views()):
+        try:
+            jobs = workspace.get_feature_view(name).list_materialization_jobs()
+        except Exception:
+            # Feature views without materialization (e.g. on-demand feature views) have no jobs.
+            jobs = []
+        job_count = 0
+        compute_hours = 0.0
+        for job in jobs:
+            attempts = getattr(job, "attempts", None) or []
+            hours = sum(attempt_hours(attempt, since, now) for attempt in attempts)
+            created_at = getattr(job, "created_at", None)
+            if hours == 0 and (created_at is None or as_utc(created_at) < since):
+                continue
+            job_count += 1
+            compute_hours += hours
+        feature_views.append(
+            {
+                "name": name,
+                "materialization_job_count": job_count,
+                "materialization_compute_hours": round(compute_hours, 4),
+            }
+        )
+
+    feature_services = []
+    for name in sorted(workspace.list_feature_services()):
+        feature_service = workspace.get_feature_service(name)
+        feature_services.append(
+            {
+                "name": name,
+                "feature_views": served_feature_views(workspace_name, name),
+                "serving_request_count": serving_request_count(feature_service, since, now),
+            }
+        )
+
+    workspaces.append({"name": workspace_name, "feature_views": feature_views, "feature_services": feature_services})
+
+json.dump({"workspaces": workspaces}, sys.stdout)
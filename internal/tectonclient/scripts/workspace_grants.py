@@ -0,0 +1,44 @@
+# Prints a JSON list of the roles assigned directly (rather than through a group or on all
+# workspaces) to users and service accounts on a workspace.
+#
+# Usage: python workspace_grants.py <workspace>
+import json
+import sys
+
+from tecton._internals import metadata_service
+from tecton_proto.auth import authorization_service_pb2
+from tecton_proto.auth import principal_pb2
+from tecton_proto.auth import resource_pb2
+
+WORKSPACE_ROLES = ("viewer", "operator", "editor", "owner")
+
+request = authorization_service_pb2.GetAssignedPrincipalsRequest(
+    resource_type=resource_pb2.ResourceType.RESOURCE_TYPE_WORKSPACE,
+    resource_id=sys.argv[1],
+    principal_types=[
+        principal_pb2.PrincipalType.PRINCIPAL_TYPE_USER,
+        principal_pb2.PrincipalType.PRINCIPAL_TYPE_SERVICE_ACCOUNT,
+    ],
+)
+response = metadata_service.instance().GetAssignedPrincipals(request)
+
+grants = []
+for assignment in response.assignments:
+    roles = sorted(
+        role_assignment.role
+        for role_assignment in assignment.role_assignments
+        if role_assignment.role in WORKSPACE_ROLES
+        and any(
+            source.assignment_type == authorization_service_pb2.AssignmentType.ASSIGNMENT_TYPE_DIRECT
+            for source in role_assignment.assignment_sources
+        )
+    )
+    if not roles:
+        continue
+    principal = assignment.principal
+    if principal.HasField("user"):
+        grants.append({"user_id": principal.user.login_email, "roles": roles})
+    elif principal.HasField("service_account"):
+        grants.append({"service_account_id": principal.service_account.id, "roles": roles})
+
+json.dump(grants, sys.stdout)
@@ -0,0 +1,37 @@
+# Prints a JSON object mapping each of the given workspaces to the users and service accounts that
+# have the owner role on it, whether it was assigned directly, through a group, or on all workspaces.
+#
+# Usage: python workspace_owners.py <workspace> [<workspace> ...]
+import json
+import sys
+
+from tecton._internals import metadata_service
+from tecton_proto.auth import authorization_service_pb2
+from tecton_proto.auth import principal_pb2
+from tecton_proto.auth import resource_pb2
+
+owners = {}
+for workspace in sys.argv[1:]:
+    request = authorization_service_pb2.GetAssignedPrincipalsRequest(
+        resource_type=resource_pb2.ResourceType.RESOURCE_TYPE_WORKSPACE,
+        resource_id=workspace,
+        principal_types=[
+            principal_pb2.PrincipalType.PRINCIPAL_TYPE_USER,
+            principal_pb2.PrincipalType.PRINCIPAL_TYPE_SERVICE_ACCOUNT,
+        ],
+    )
+    response = metadata_service.instance().GetAssignedPrincipals(request)
+
+    users = set()
+    service_accounts = set()
+    for assignment in response.assignments:
+        if not any(role_assignment.role == "owner" for role_assignment in assignment.role_assignments):
+            continue
+        principal = assignment.principal
+        if principal.HasField("user"):
+            users.add(principal.user.login_email)
+        elif principal.HasField("service_account"):
+            service_accounts.add(principal.service_account.id)
+    owners[workspace] = {"users": sorted(users), "service_accounts": sorted(service_accounts)}
+
+json.dump(owners, sys.stdout)
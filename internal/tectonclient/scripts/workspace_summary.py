@@ -0,0 +1,47 @@
+# Prints a JSON summary of the objects in a Tecton workspace that are relevant to deciding whether
+# the workspace is safe to delete, and of the names of its other objects for comparing workspaces.
+#
+# Usage: python workspace_summary.py <workspace>
+import json
+import sys
+
+import tecton
+
+ACTIVE_JOB_STATES = ("PENDING", "RUNNING")
+
+
+def list_names(workspace, method):
+    # Older SDKs can't list every kind of object.
+    list_objects = getattr(workspace, method, None)
+    return sorted(list_objects()) if list_objects is not None else []
+
+
+workspace = tecton.get_workspace(sys.argv[1])
+
+feature_views = sorted(workspace.list_feature_views())
+feature_services = sorted(workspace.list_feature_services())
+
+active_jobs = []
+for name in feature_views:
+    feature_view = workspace.get_feature_view(name)
+    try:
+        jobs = feature_view.list_materialization_jobs()
+    except Exception:
+        # Feature views without materialization (e.g. on-demand feature views) have no jobs.
+        continue
+    for job in jobs:
+        state = str(job.state).upper()
+        if any(active_state in state for active_state in ACTIVE_JOB_STATES):
+            active_jobs.append({"feature_view": name, "id": job.id, "state": state})
+
+json.dump(
+    {
+        "feature_views": feature_views,
+        "feature_services": feature_services,
+        "entities": list_names(workspace, "list_entities"),
+        "data_sources": list_names(workspace, "list_data_sources"),
+        "transformations": list_names(workspace, "list_transformations"),
+        "active_materialization_jobs": active_jobs,
+    },
+    sys.stdout,
+)
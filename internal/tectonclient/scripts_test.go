@@ -0,0 +1,24 @@
+package tectonclient
+
+import "testing"
+
+func TestParseShebang(t *testing.T) {
+	testCases := map[string]struct {
+		line     string
+		expected string
+	}{
+		"absolute path": {line: "#!/opt/venv/bin/python3.9\n", expected: "/opt/venv/bin/python3.9"},
+		"env":           {line: "#!/usr/bin/env python3\n", expected: "python3"},
+		"not python":    {line: "#!/bin/sh\n", expected: ""},
+		"binary":        {line: "\x7fELF\x02\x01", expected: ""},
+		"empty":         {line: "", expected: ""},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := parseShebang(testCase.line)
+			if actual != testCase.expected {
+				t.Errorf("expected '%v', got '%v'", testCase.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package tectonclient
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Usage is the materialization and serving usage of workspaces over a window, from the output of the
+// `usage.py` script.
+type Usage struct {
+	Workspaces []WorkspaceUsage `json:"workspaces" required:"true"`
+}
+
+// WorkspaceUsage is the usage of a single workspace.
+type WorkspaceUsage struct {
+	Name            string                `json:"name" required:"true"`
+	FeatureViews    []FeatureViewUsage    `json:"feature_views" required:"true"`
+	FeatureServices []FeatureServiceUsage `json:"feature_services" required:"true"`
+}
+
+// FeatureViewUsage is the materialization usage of a feature view.
+type FeatureViewUsage struct {
+	Name string `json:"name" required:"true"`
+	// The number of materialization jobs that ran within the window.
+	MaterializationJobCount int64 `json:"materialization_job_count" required:"true"`
+	// The run time of the jobs' attempts within the window, in hours.
+	MaterializationComputeHours float64 `json:"materialization_compute_hours" required:"true"`
+}
+
+// FeatureServiceUsage is the serving usage of a feature service.
+type FeatureServiceUsage struct {
+	Name string `json:"name" required:"true"`
+	// The feature views the feature service serves. Empty if they couldn't be read.
+	FeatureViews []string `json:"feature_views"`
+	// The number of online requests within the window, or nil if the SDK doesn't report it.
+	ServingRequestCount *int64 `json:"serving_request_count"`
+}
+
+// Reads the usage of the given workspaces since a time.
+func (c Client) GetUsage(ctx context.Context, since time.Time, workspaces []string) (Usage, error) {
+	tflog.Info(ctx, fmt.Sprintf("Reading the usage of workspaces [%v] since %v", strings.Join(workspaces, ", "), since.UTC().Format(time.RFC3339)))
+	output, err := c.RunScript(ctx, "usage.py", append([]string{since.UTC().Format(time.RFC3339)}, workspaces...)...)
+	if err != nil {
+		return Usage{}, err
+	}
+	var usage Usage
+	err = parseJSON("usage.py", output, &usage)
+	if err != nil {
+		return Usage{}, err
+	}
+	return usage, nil
+}
+
+// Returns the total number of materialization jobs of the workspace's feature views.
+func (u WorkspaceUsage) MaterializationJobCount() int64 {
+	var count int64
+	for _, featureView := range u.FeatureViews {
+		count += featureView.MaterializationJobCount
+	}
+	return count
+}
+
+// Returns the total materialization compute hours of the workspace's feature views.
+func (u WorkspaceUsage) MaterializationComputeHours() float64 {
+	var hours float64
+	for _, featureView := range u.FeatureViews {
+		hours += featureView.MaterializationComputeHours
+	}
+	return hours
+}
+
+// Returns the total number of online requests to the workspace's feature services, or nil if the
+// count of any of them isn't reported.
+func (u WorkspaceUsage) ServingRequestCount() *int64 {
+	var count int64
+	for _, featureService := range u.FeatureServices {
+		if featureService.ServingRequestCount == nil {
+			return nil
+		}
+		count += *featureService.ServingRequestCount
+	}
+	return &count
+}
+
+// Returns the feature services of the workspace that serve a feature view, sorted by name.
+func (u WorkspaceUsage) FeatureServicesServing(featureView string) []FeatureServiceUsage {
+	var featureServices []FeatureServiceUsage
+	for _, featureService := range u.FeatureServices {
+		if slices.Contains(featureService.FeatureViews, featureView) {
+			featureServices = append(featureServices, featureService)
+		}
+	}
+	slices.SortFunc(featureServices, func(a, b FeatureServiceUsage) int { return strings.Compare(a.Name, b.Name) })
+	return featureServices
+}
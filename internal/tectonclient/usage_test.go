@@ -0,0 +1,33 @@
+package tectonclient
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetUsage(t *testing.T) {
+	fakeTectonPythonCLI(t, `{"workspaces": [{"name": "prod", "feature_views": [
+		{"name": "user_clicks", "materialization_job_count": 3, "materialization_compute_hours": 1.25},
+		{"name": "user_transactions", "materialization_job_count": 1, "materialization_compute_hours": 0.5}
+	], "feature_services": [{"name": "fraud", "serving_request_count": 10}, {"name": "ads", "serving_request_count": 5}]}]}`)
+	usage, err := Client{}.GetUsage(context.Background(), time.Now().Add(-time.Hour), []string{"prod"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(usage.Workspaces) != 1 {
+		t.Fatalf("expected one workspace, got %+v", usage)
+	}
+	prod := usage.Workspaces[0]
+	if prod.MaterializationJobCount() != 4 || prod.MaterializationComputeHours() != 1.75 {
+		t.Errorf("unexpected materialization totals: %v jobs, %v hours", prod.MaterializationJobCount(), prod.MaterializationComputeHours())
+	}
+	if count := prod.ServingRequestCount(); count == nil || *count != 15 {
+		t.Errorf("expected 15 requests, got %v", count)
+	}
+
+	prod.FeatureServices[1].ServingRequestCount = nil
+	if count := prod.ServingRequestCount(); count != nil {
+		t.Errorf("expected an unknown request count if any feature service's is unknown, got %v", *count)
+	}
+}
@@ -0,0 +1,83 @@
+package tectonclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Matches a line of command output that warns about a deprecated or incompatible feature, e.g. a
+// Python DeprecationWarning from the SDK or the CLI's notice that it's too old for the cluster.
+var deprecationWarningRegex = regexp.MustCompile(`(?i)(DeprecationWarning|FutureWarning|is deprecated|will be (removed|deprecated)|no longer (be )?supported|not compatible with|incompatible with|please upgrade)`)
+
+// WarningRecorder collects the distinct deprecation and compatibility warnings that commands printed,
+// so that they can be reported as Terraform warnings. It's safe for concurrent use.
+type WarningRecorder struct {
+	mu       sync.Mutex
+	warnings []string
+	seen     map[string]bool
+}
+
+// Records a warning, unless it was already recorded. Returns true if it's new. Does nothing if the
+// recorder is nil.
+func (r *WarningRecorder) Record(warning string) bool {
+	if r == nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.seen[warning] {
+		return false
+	}
+	if r.seen == nil {
+		r.seen = make(map[string]bool)
+	}
+	r.seen[warning] = true
+	r.warnings = append(r.warnings, warning)
+	return true
+}
+
+// Returns the warnings recorded so far, in the order they were first printed.
+func (r *WarningRecorder) Warnings() []string {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.warnings...)
+}
+
+// Returns the distinct deprecation and compatibility warnings in a command's output. Python's warning
+// lines start with the source location, e.g. "/lib/tecton/cli.py:12: DeprecationWarning: ...", which
+// is dropped so that the same warning from different places is only reported once.
+func deprecationWarnings(output []byte) []string {
+	var warnings []string
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !deprecationWarningRegex.MatchString(line) {
+			continue
+		}
+		if i := strings.Index(line, "Warning: "); i >= 0 && strings.Contains(line[:i], ".py:") {
+			line = line[strings.LastIndex(line[:i], " ")+1:]
+		}
+		warnings = append(warnings, line)
+	}
+	return warnings
+}
+
+// Records the deprecation and compatibility warnings in a command's output, and logs the new ones.
+func (c Client) recordWarnings(ctx context.Context, command string, output []byte) {
+	for _, warning := range deprecationWarnings(output) {
+		if c.Warnings == nil || c.Warnings.Record(warning) {
+			tflog.Warn(ctx, fmt.Sprintf("Command '%v' printed a deprecation warning: %v", command, warning))
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package tectonclient
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestDeprecationWarnings(t *testing.T) {
+	output := []byte(`Live Workspaces:
+  prod
+/usr/lib/python3.9/site-packages/tecton/cli/cli.py:42: DeprecationWarning: --json-out is deprecated, use --output json
+  warnings.warn(
+Warning: this version of the Tecton CLI is not compatible with your cluster. Please upgrade.
+`)
+	expected := []string{
+		"DeprecationWarning: --json-out is deprecated, use --output json",
+		"Warning: this version of the Tecton CLI is not compatible with your cluster. Please upgrade.",
+	}
+	if actual := deprecationWarnings(output); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestRunRecordsWarnings(t *testing.T) {
+	fakeTectonCLI(t, `echo "Live Workspaces:"; echo "/lib/tecton/cli.py:1: FutureWarning: workspace list will be removed" >&2`)
+	cli := Client{Warnings: &WarningRecorder{}}
+	for range 2 {
+		_, err := cli.Run(context.Background(), "workspace", "list")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	expected := []string{"FutureWarning: workspace list will be removed"}
+	if actual := cli.Warnings.Warnings(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected each warning to be recorded once, %q, got %q", expected, actual)
+	}
+}
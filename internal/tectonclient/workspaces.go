@@ -0,0 +1,237 @@
+package tectonclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kgreer-plaid/terraform-provider-tecton/internal/diagnostics"
+)
+
+// Workspaces are the names of the live and development workspaces in the Tecton instance.
+type Workspaces struct {
+	Lives []string
+	Devs  []string
+	// Whether each workspace is live, so that clusters with thousands of workspaces can be looked up
+	// without scanning the lists. Built by NewWorkspaces; Lookup scans the lists if it's nil.
+	index map[string]bool
+}
+
+// Returns the workspaces with the given live and development workspace names, indexed for Lookup.
+func NewWorkspaces(lives []string, devs []string) Workspaces {
+	index := make(map[string]bool, len(lives)+len(devs))
+	for _, ws := range lives {
+		index[ws] = true
+	}
+	for _, ws := range devs {
+		index[ws] = false
+	}
+	return Workspaces{Lives: lives, Devs: devs, index: index}
+}
+
+// Returns whether the workspace with the given name exists and, if it does, whether it's live.
+func (w Workspaces) Lookup(name string) (isLive bool, found bool) {
+	if w.index != nil {
+		isLive, found = w.index[name]
+		return isLive, found
+	}
+	for _, ws := range w.Lives {
+		if ws == name {
+			isLive, found = true, true
+		}
+	}
+	for _, ws := range w.Devs {
+		if ws == name {
+			isLive, found = false, true
+		}
+	}
+	return isLive, found
+}
+
+// Returns a copy of the workspaces that includes the workspace with the given name, e.g. after it was
+// created. A workspace with the same name is replaced.
+func (w Workspaces) With(name string, isLive bool) Workspaces {
+	w = w.Without(name)
+	if isLive {
+		w.Lives = append(w.Lives, name)
+	} else {
+		w.Devs = append(w.Devs, name)
+	}
+	return NewWorkspaces(w.Lives, w.Devs)
+}
+
+// Returns a copy of the workspaces without the workspace with the given name, e.g. after it was deleted.
+func (w Workspaces) Without(name string) Workspaces {
+	isName := func(ws string) bool { return ws == name }
+	return NewWorkspaces(slices.DeleteFunc(slices.Clone(w.Lives), isName), slices.DeleteFunc(slices.Clone(w.Devs), isName))
+}
+
+// Queries the complete list of workspaces in the Tecton instance and parses the output. The CLI
+// doesn't paginate the list, so a single call returns every workspace.
+func (c Client) ListWorkspaces(ctx context.Context) (Workspaces, error) {
+	args := []string{"workspace", "list"}
+	output, err := c.Run(ctx, args...)
+	if err != nil {
+		return Workspaces{}, c.CommandError(ctx, "list Tecton workspaces", args, output, err)
+	}
+	return parseWorkspaceList(output)
+}
+
+// Parses the output of `tecton workspace list`, e.g.
+//
+//	Live Workspaces:
+//	  a
+//	  b
+//
+//	Development Workspaces:
+//	  c
+//	* d
+//	  e
+//
+// into Workspaces{Lives: []string{"a", "b"}, Devs: []string{"c", "d", "e"}}. The '*' character begins
+// the line of the current "active" workspace, which this provider doesn't use. The output is scanned
+// line by line, since it has thousands of lines on large clusters. Lines before the first header, e.g.
+// warnings, are ignored.
+func parseWorkspaceList(output []byte) (Workspaces, error) {
+	const (
+		beforeHeaders = iota
+		liveSection
+		devSection
+	)
+	section := beforeHeaders
+	var lives, devs []string
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "Live Workspaces:") {
+			section = liveSection
+			continue
+		} else if strings.HasPrefix(line, "Development Workspaces:") {
+			if section != liveSection {
+				break
+			}
+			section = devSection
+			continue
+		}
+		if section == beforeHeaders || strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		// One workspace line will start with "*"
+		workspace := strings.TrimSpace(strings.TrimPrefix(line, "*"))
+		if strings.ContainsAny(workspace, " \t") {
+			return Workspaces{}, &diagnostics.ParseError{
+				Source: "tecton workspace list",
+				Output: string(output),
+				Err:    fmt.Errorf("unexpected line %q", line),
+			}
+		}
+		if section == liveSection {
+			lives = append(lives, workspace)
+		} else {
+			devs = append(devs, workspace)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Workspaces{}, &diagnostics.ParseError{Source: "tecton workspace list", Output: string(output), Err: err}
+	}
+	if section != devSection {
+		return Workspaces{}, &diagnostics.ParseError{
+			Source: "tecton workspace list",
+			Output: string(output),
+			Err:    errors.New("expected a 'Live Workspaces:' header followed by a 'Development Workspaces:' header"),
+		}
+	}
+	return NewWorkspaces(lives, devs), nil
+}
+
+// Creates a live or development workspace. The name should already be validated.
+func (c Client) CreateWorkspace(ctx context.Context, name string, live bool) error {
+	liveArg := "--no-live"
+	if live {
+		liveArg = "--live"
+	}
+	tflog.Info(ctx, fmt.Sprintf("Creating workspace '%v'", name))
+	args := []string{"workspace", "create", name, liveArg}
+	if c.SkipInDryRun(ctx, "tecton "+ShellJoin(args)) {
+		return nil
+	}
+	output, err := c.Run(ctx, args...)
+	if err != nil {
+		return c.CommandError(ctx, fmt.Sprintf("create Tecton workspace '%v'", name), args, output, err)
+	}
+	c.Changes.Record(fmt.Sprintf("Created %v workspace '%v'", workspaceKind(live), name))
+	return nil
+}
+
+// Applies the feature repo that was last applied to workspace from to workspace to, so that to has the
+// same feature views, feature services and other objects. The repo is restored with `tecton restore`
+// into a temporary directory, which selects the workspace with the TECTON_WORKSPACE variable, and
+// is then applied with `tecton apply`.
+func (c Client) CloneWorkspace(ctx context.Context, from string, to string) error {
+	tflog.Info(ctx, fmt.Sprintf("Cloning the feature repo of workspace '%v' to workspace '%v'", from, to))
+	if c.SkipInDryRun(ctx, "TECTON_WORKSPACE="+ShellJoin([]string{from})+" tecton restore && tecton "+ShellJoin([]string{"apply", "--yes", "--workspace", to})) {
+		return nil
+	}
+	dir, err := os.MkdirTemp("", "tecton-clone-*")
+	if err != nil {
+		return fmt.Errorf("Failed to create a directory for the feature repo of workspace '%v': %w", from, err)
+	}
+	defer os.RemoveAll(dir)
+	// `tecton restore` and `tecton apply` must be run in a feature repo, which is marked by this file
+	err = os.WriteFile(filepath.Join(dir, ".tecton"), nil, 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed to create a feature repo for workspace '%v': %w", from, err)
+	}
+
+	repo := c
+	repo.Dir = dir
+	restore := repo
+	restore.Env = c.Env
+	if restore.Env == nil {
+		restore.Env = os.Environ()
+	}
+	restore.Env = append(slices.Clone(restore.Env), "TECTON_WORKSPACE="+from)
+	args := []string{"restore"}
+	output, err := restore.Run(ctx, args...)
+	if err != nil {
+		return c.CommandError(ctx, fmt.Sprintf("restore the feature repo of Tecton workspace '%v'", from), args, output, err)
+	}
+	args = []string{"apply", "--yes", "--workspace", to}
+	output, err = repo.Run(ctx, args...)
+	if err != nil {
+		return c.CommandError(ctx, fmt.Sprintf("apply the feature repo of Tecton workspace '%v' to workspace '%v'", from, to), args, output, err)
+	}
+	c.Changes.Record(fmt.Sprintf("Applied the feature repo of workspace '%v' to workspace '%v'", from, to))
+	return nil
+}
+
+// Deletes a workspace. live is only used to describe the change.
+func (c Client) DeleteWorkspace(ctx context.Context, name string, live bool) error {
+	tflog.Info(ctx, fmt.Sprintf("Deleting workspace '%v'", name))
+	args := []string{"workspace", "delete", "--yes", name}
+	if c.SkipInDryRun(ctx, "tecton "+ShellJoin(args)) {
+		return nil
+	}
+	output, err := c.Run(ctx, args...)
+	if err != nil {
+		return c.CommandError(ctx, fmt.Sprintf("delete Tecton workspace '%v'", name), args, output, err)
+	}
+	c.Changes.Record(fmt.Sprintf("Deleted %v workspace '%v'", workspaceKind(live), name))
+	return nil
+}
+
+// Returns "live" or "development" for use in messages.
+func workspaceKind(isLive bool) string {
+	if isLive {
+		return "live"
+	}
+	return "development"
+}
@@ -0,0 +1,189 @@
+package tectonclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestListWorkspaces(t *testing.T) {
+	fakeTectonCLI(t, `printf 'Live Workspaces:\n  a\n  b\n\nDevelopment Workspaces:\n  c\n* d\n'`)
+	workspaces, err := Client{}.ListWorkspaces(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(workspaces.Lives, ",") != "a,b" || strings.Join(workspaces.Devs, ",") != "c,d" {
+		t.Errorf("unexpected workspaces: %+v", workspaces)
+	}
+
+	fakeTectonCLI(t, "echo 'unexpected'")
+	if _, err := (Client{}).ListWorkspaces(context.Background()); err == nil {
+		t.Error("expected an error for unexpected output")
+	}
+}
+
+func TestParseWorkspaceList(t *testing.T) {
+	testCases := map[string]struct {
+		output  string
+		lives   string
+		devs    string
+		isError bool
+	}{
+		"leading warning": {
+			output: "Warning: a newer version of tecton is available\nLive Workspaces:\n  a\n\nDevelopment Workspaces:\n* b\n",
+			lives:  "a",
+			devs:   "b",
+		},
+		"empty": {
+			output: "Live Workspaces:\n\nDevelopment Workspaces:\n",
+		},
+		"missing development header": {
+			output:  "Live Workspaces:\n  a\n",
+			isError: true,
+		},
+		"unexpected line": {
+			output:  "Live Workspaces:\n  a b\n\nDevelopment Workspaces:\n",
+			isError: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			workspaces, err := parseWorkspaceList([]byte(testCase.output))
+			if (err != nil) != testCase.isError {
+				t.Fatalf("expected error: %v, got: %v", testCase.isError, err)
+			}
+			if strings.Join(workspaces.Lives, ",") != testCase.lives || strings.Join(workspaces.Devs, ",") != testCase.devs {
+				t.Errorf("unexpected workspaces: %+v", workspaces)
+			}
+		})
+	}
+}
+
+func TestWorkspacesLookup(t *testing.T) {
+	for name, workspaces := range map[string]Workspaces{
+		"indexed":     NewWorkspaces([]string{"prod"}, []string{"dev"}),
+		"not indexed": {Lives: []string{"prod"}, Devs: []string{"dev"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if isLive, found := workspaces.Lookup("prod"); !isLive || !found {
+				t.Errorf("expected prod to be live, got isLive: %v, found: %v", isLive, found)
+			}
+			if isLive, found := workspaces.Lookup("dev"); isLive || !found {
+				t.Errorf("expected dev to be a development workspace, got isLive: %v, found: %v", isLive, found)
+			}
+			if _, found := workspaces.Lookup("missing"); found {
+				t.Error("expected missing to not be found")
+			}
+		})
+	}
+}
+
+func TestWorkspacesWithAndWithout(t *testing.T) {
+	workspaces := NewWorkspaces([]string{"prod"}, []string{"dev"})
+
+	added := workspaces.With("new", true).With("dev", true)
+	if isLive, found := added.Lookup("new"); !isLive || !found {
+		t.Errorf("expected new to be live, got isLive: %v, found: %v", isLive, found)
+	}
+	if isLive, _ := added.Lookup("dev"); !isLive || len(added.Devs) != 0 {
+		t.Errorf("expected dev to be replaced with a live workspace, got %v", added)
+	}
+
+	removed := added.Without("prod")
+	if _, found := removed.Lookup("prod"); found {
+		t.Error("expected prod to be removed")
+	}
+
+	// The original workspaces may be shared, so they must not change
+	if len(workspaces.Lives) != 1 || len(workspaces.Devs) != 1 || len(added.Lives) != 3 {
+		t.Errorf("expected the original workspaces to be unchanged, got %v and %v", workspaces, added)
+	}
+}
+
+// Returns the output of `tecton workspace list` for a cluster with n live and n development
+// workspaces.
+func largeWorkspaceList(n int) []byte {
+	var output strings.Builder
+	output.WriteString("Live Workspaces:\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&output, "  live-%v\n", i)
+	}
+	output.WriteString("\nDevelopment Workspaces:\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&output, "  dev-%v\n", i)
+	}
+	return []byte(output.String())
+}
+
+func BenchmarkParseWorkspaceList(b *testing.B) {
+	output := largeWorkspaceList(5000)
+	for b.Loop() {
+		if _, err := parseWorkspaceList(output); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Looks up every workspace of a large cluster, like a refresh of a state that manages all of them.
+func BenchmarkWorkspacesLookup(b *testing.B) {
+	parsed, err := parseWorkspaceList(largeWorkspaceList(5000))
+	if err != nil {
+		b.Fatal(err)
+	}
+	workspaces := NewWorkspaces(parsed.Lives, parsed.Devs)
+	names := append(append([]string{}, workspaces.Lives...), workspaces.Devs...)
+	for b.Loop() {
+		for _, name := range names {
+			if _, found := workspaces.Lookup(name); !found {
+				b.Fatalf("workspace %v not found", name)
+			}
+		}
+	}
+}
+
+func TestCreateAndDeleteWorkspace(t *testing.T) {
+	fakeTectonCLI(t, `[ "$3" = "taken" ] && echo 'Workspace already exists' && exit 1; exit 0`)
+	client, changes := Client{}.RecordingChanges()
+	ctx := context.Background()
+	if err := client.CreateWorkspace(ctx, "prod", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.DeleteWorkspace(ctx, "dev", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := client.CreateWorkspace(ctx, "taken", false)
+	if err == nil || !strings.Contains(err.Error(), "Workspace already exists") {
+		t.Errorf("expected the command output in the error, got: %v", err)
+	}
+	expected := "Created live workspace 'prod'\nDeleted development workspace 'dev'"
+	if strings.Join(changes.Changes(), "\n") != expected {
+		t.Errorf("expected changes:\n%v\ngot:\n%v", expected, strings.Join(changes.Changes(), "\n"))
+	}
+}
+
+func TestCloneWorkspace(t *testing.T) {
+	// restore must run in a feature repo with the source workspace selected, and apply must target the
+	// new workspace from the same repo
+	fakeTectonCLI(t, `test -f .tecton || { echo "not a feature repo"; exit 1; }
+case "$1" in
+restore) test "$TECTON_WORKSPACE" = template || { echo "wrong workspace '$TECTON_WORKSPACE'"; exit 1; }; echo "fv = 1" > features.py ;;
+apply) test -f features.py && test "$*" = "apply --yes --workspace team-a" || { echo "unexpected apply: $*"; exit 1; } ;;
+*) exit 1 ;;
+esac`)
+	cli, changes := Client{}.RecordingChanges()
+	err := cli.CloneWorkspace(context.Background(), "template", "team-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "Applied the feature repo of workspace 'template' to workspace 'team-a'"
+	if recorded := changes.Changes(); len(recorded) != 1 || recorded[0] != expected {
+		t.Errorf("expected the change %q, got: %q", expected, recorded)
+	}
+
+	fakeTectonCLI(t, `echo "No applies found for workspace"; exit 1`)
+	err = cli.CloneWorkspace(context.Background(), "template", "team-a")
+	if err == nil || !strings.Contains(err.Error(), "No applies found") {
+		t.Errorf("expected the restore's error, got: %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/kgreer-plaid/terraform-provider-tecton/internal/provider"
@@ -32,6 +33,13 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "preflight" {
+		os.Exit(preflight(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		os.Exit(generate(os.Args[2:]))
+	}
+
 	var debug bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
@@ -49,3 +57,54 @@ func main() {
 		log.Fatal(err.Error())
 	}
 }
+
+// Runs the `preflight` subcommand, which checks the tecton CLI, credentials and connectivity without
+// running Terraform. It takes the provider's settings as flags. Returns the process exit code.
+func preflight(args []string) int {
+	flags := flag.NewFlagSet("preflight", flag.ExitOnError)
+	providerFlags := provider.NewProviderFlags(flags)
+	_ = flags.Parse(args)
+
+	ctx := context.Background()
+	config, err := providerFlags.Config(ctx)
+	if err != nil {
+		log.Print(err.Error())
+		return 1
+	}
+	if !provider.Preflight(ctx, os.Stdout, config) {
+		return 1
+	}
+	return 0
+}
+
+// Runs the `generate` subcommand, which writes the configuration and import blocks of every
+// workspace and access policy in the cluster. Returns the process exit code.
+func generate(args []string) int {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	providerFlags := provider.NewProviderFlags(flags)
+	outPath := flags.String("out", "", "the file to write the configuration to. Defaults to stdout")
+	_ = flags.Parse(args)
+
+	ctx := context.Background()
+	config, err := providerFlags.Config(ctx)
+	if err != nil {
+		log.Print(err.Error())
+		return 1
+	}
+	out := os.Stdout
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			log.Print(err.Error())
+			return 1
+		}
+		defer file.Close()
+		out = file
+	}
+	err = provider.Generate(ctx, out, config)
+	if err != nil {
+		log.Print(err.Error())
+		return 1
+	}
+	return 0
+}
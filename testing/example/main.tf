@@ -0,0 +1,36 @@
+# An example module that creates a workspace for a team and makes its members owners, with tests
+# in tests/ that run against the fake tecton CLI.
+terraform {
+  required_providers {
+    tecton = {
+      source = "registry.terraform.io/kgreer-plaid/tecton"
+    }
+  }
+}
+
+variable "name" {
+  type = string
+}
+
+variable "owners" {
+  type    = list(string)
+  default = []
+}
+
+resource "tecton_workspace" "this" {
+  name = var.name
+  live = true
+}
+
+resource "tecton_access_policy" "owners" {
+  for_each = toset(var.owners)
+
+  user_id = each.value
+  workspaces = {
+    (tecton_workspace.this.name) = ["owner"]
+  }
+}
+
+output "workspace" {
+  value = tecton_workspace.this.name
+}
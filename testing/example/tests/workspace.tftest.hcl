@@ -0,0 +1,32 @@
+# Run from testing/example with the fake tecton CLI on the PATH:
+#
+#   export PATH="$PWD/../fake-tecton:$PATH"
+#   export TECTON_FAKE_STATE="$(mktemp -d)/state.json" TECTON_FAKE_SEED="$PWD/tests/seed.json"
+#   terraform init && terraform test
+provider "tecton" {
+  url     = "https://fake.tecton.ai"
+  api_key = "fake"
+}
+
+variables {
+  name   = "team-a"
+  owners = ["alice@example.com", "bob@example.com"]
+}
+
+run "creates_workspace" {
+  assert {
+    condition     = output.workspace == "team-a" && tecton_workspace.this.live
+    error_message = "Expected a live workspace named team-a"
+  }
+}
+
+run "grants_owner" {
+  assert {
+    condition     = tecton_access_policy.owners["alice@example.com"].workspaces["team-a"] == tolist(["owner"])
+    error_message = "Expected alice@example.com to own team-a"
+  }
+  assert {
+    condition     = length(tecton_access_policy.owners) == 2
+    error_message = "Expected an access policy for every owner"
+  }
+}